@@ -0,0 +1,71 @@
+package eotel_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+func TestStartupTimeoutUnreachableCollector(t *testing.T) {
+	eoteltest.NewRecorder(t)
+
+	// A port nothing listens on: every connection attempt is refused.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	var mu sync.Mutex
+	closed := map[eotel.Signal]bool{}
+	const timeout = 300 * time.Millisecond
+	start := time.Now()
+	_, err = eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:    "unreachable",
+		OtelCollector:  addr,
+		EnableTracing:  true,
+		EnableMetrics:  true,
+		StartupTimeout: timeout,
+		StrictInit:     true,
+		OnCollectorState: func(s eotel.Signal, state connectivity.State) {
+			if state == connectivity.Shutdown {
+				mu.Lock()
+				closed[s] = true
+				mu.Unlock()
+			}
+		},
+	})
+	elapsed := time.Since(start)
+
+	var serr *eotel.SubsystemError
+	if !errors.As(err, &serr) || serr.Subsystem != eotel.SubsystemCollector {
+		t.Fatalf("err = %v, want a collector SubsystemError", err)
+	}
+	if elapsed > timeout+2*time.Second {
+		t.Errorf("InitEOTEL took %s, want about the %s startup timeout", elapsed, timeout)
+	}
+
+	// The failed init must close the connections it dialed.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		done := closed[eotel.SignalTracing] && closed[eotel.SignalMetrics]
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("collector connections not closed after the failed init: %v", closed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
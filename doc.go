@@ -0,0 +1,18 @@
+// Package eotel keeps the original import path working on top of the core
+// package, github.com/nicedev97/eotel-v2/eotel. It re-exports the core API,
+// adds the Gin helpers and the gRPC interceptors, and registers the Sentry,
+// Prometheus and log rotation backends, so importing it behaves as before.
+//
+// A service that does not need all of that, such as a worker using only
+// logging and metrics, imports the core and the backend modules it uses
+// instead, and keeps gin, sentry-go and the Prometheus client out of its
+// module graph.
+package eotel
+
+//go:generate go run ./internal/genfacade
+
+import (
+	_ "github.com/nicedev97/eotel-v2/eotelprom"
+	_ "github.com/nicedev97/eotel-v2/eotelrotate"
+	_ "github.com/nicedev97/eotel-v2/eotelsentry"
+)
@@ -0,0 +1,66 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	abortedOnce sync.Once
+	abortedReqs metric.Int64Counter
+)
+
+// abortedBy tells why a request's context ended before the response:
+// "server_timeout" when a deadline (http.TimeoutHandler, a timeout
+// middleware) expired, "client" when net/http cancelled it because the
+// client went away, "" when it completed normally. reqCtx is the context
+// the server handed to the middleware, handlerCtx the one the handler saw
+// last, which may carry a deadline added further down the chain.
+func abortedBy(reqCtx, handlerCtx context.Context) string {
+	switch {
+	case errors.Is(handlerCtx.Err(), context.DeadlineExceeded), errors.Is(reqCtx.Err(), context.DeadlineExceeded):
+		return "server_timeout"
+	case errors.Is(reqCtx.Err(), context.Canceled):
+		return "client"
+	}
+	return ""
+}
+
+// markCanceled flags span with context_canceled when err comes from a
+// cancelled context, and reports whether it did. Such errors are the caller
+// giving up rather than a failure, so callers leave the span status unset.
+func markCanceled(span trace.Span, err error) bool {
+	if !errors.Is(err, context.Canceled) {
+		return false
+	}
+	span.SetAttributes(attribute.Bool("context_canceled", true))
+	return true
+}
+
+// tagAborted records request.aborted_by on span and logger and counts it in
+// http_server_aborted_requests_total. Client aborts also set
+// context_canceled.
+func tagAborted(span trace.Span, logger *Eotel, reason string) *Eotel {
+	if reason == "" {
+		return logger
+	}
+	attr := attribute.String("request.aborted_by", reason)
+	span.SetAttributes(attr)
+	if reason == "client" {
+		span.SetAttributes(attribute.Bool("context_canceled", true))
+	}
+
+	abortedOnce.Do(func() {
+		abortedReqs, _ = getMeter().Int64Counter("http_server_aborted_requests_total",
+			metric.WithDescription("Requests whose context ended before completion, by cause."))
+	})
+	if abortedReqs != nil {
+		abortedReqs.Add(context.Background(), 1, metric.WithAttributes(attr))
+	}
+	return logger.WithField("request.aborted_by", reason)
+}
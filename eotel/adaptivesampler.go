@@ -0,0 +1,135 @@
+package eotel
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultSamplerTargetTPS = 10
+	adaptiveWindow          = 10 * time.Second
+	maxAdaptiveRoutes       = 1000
+	minAdaptiveProbability  = 0.0001
+)
+
+// adaptiveSampler samples root spans so that each route keeps roughly
+// targetTPS traces per second. Every adaptiveWindow it divides the target by
+// the throughput observed in the previous window, so the probability drops
+// during spikes and climbs back to 1 when traffic is quiet. Routes are keyed
+// by the http.route attribute, falling back to the span name.
+type adaptiveSampler struct {
+	target float64
+
+	mu     sync.Mutex
+	routes map[string]*adaptiveRoute
+}
+
+type adaptiveRoute struct {
+	probability float64
+	seen        int
+	since       time.Time
+}
+
+func newAdaptiveSampler(target float64) *adaptiveSampler {
+	if target <= 0 {
+		target = defaultSamplerTargetTPS
+	}
+	return &adaptiveSampler{target: target, routes: map[string]*adaptiveRoute{}}
+}
+
+func (s *adaptiveSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	key := p.Name
+	for _, kv := range p.Attributes {
+		if kv.Key == "http.route" {
+			key = kv.Value.AsString()
+			break
+		}
+	}
+	prob := s.probability(key, time.Now())
+
+	psc := trace.SpanContextFromContext(p.ParentContext)
+	res := sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: psc.TraceState()}
+	x := binary.BigEndian.Uint64(p.TraceID[8:16]) >> 1
+	if x < uint64(prob*(1<<63)) {
+		res.Decision = sdktrace.RecordAndSample
+	}
+	return res
+}
+
+func (s *adaptiveSampler) probability(key string, now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.routes[key]
+	if !ok {
+		if len(s.routes) >= maxAdaptiveRoutes {
+			key = "other"
+			r, ok = s.routes[key]
+		}
+		if !ok {
+			r = &adaptiveRoute{probability: 1, since: now}
+			s.routes[key] = r
+		}
+	}
+	r.seen++
+	if elapsed := now.Sub(r.since); elapsed >= adaptiveWindow {
+		rate := float64(r.seen) / elapsed.Seconds()
+		r.probability = min(1, max(minAdaptiveProbability, s.target/rate))
+		r.seen, r.since = 0, now
+	}
+	return r.probability
+}
+
+func (s *adaptiveSampler) Description() string {
+	return fmt.Sprintf("AdaptiveSampler{target=%g/s}", s.target)
+}
+
+// registerAdaptiveMetrics reports the probability per route of the current
+// trace sampler, when it is adaptive, as eotel.sampler.probability.
+func registerAdaptiveMetrics(m metric.Meter) {
+	gauge, err := m.Float64ObservableGauge("eotel.sampler.probability",
+		metric.WithDescription("Effective sampling probability of the adaptive sampler, per route."))
+	if err != nil {
+		return
+	}
+	_, _ = m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := adaptiveOf(traceSampler.get())
+		if s == nil {
+			return nil
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for route, r := range s.routes {
+			o.ObserveFloat64(gauge, r.probability, metric.WithAttributes(attribute.String("route", route)))
+		}
+		return nil
+	}, gauge)
+}
+
+// parentBasedAdaptive keeps a handle on the adaptive root sampler behind
+// sdktrace.ParentBased for its metrics.
+type parentBasedAdaptive struct {
+	sdktrace.Sampler
+	adaptive *adaptiveSampler
+}
+
+// adaptiveOf returns the adaptive sampler inside s, if any.
+func adaptiveOf(s sdktrace.Sampler) *adaptiveSampler {
+	if ps, ok := s.(prioritySampler); ok {
+		s = ps.base
+	}
+	switch x := s.(type) {
+	case *adaptiveSampler:
+		return x
+	case parentBasedAdaptive:
+		return x.adaptive
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package eotel
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Aggregated collapses children of the same name beyond the first n into a
+// single summary span (count/min/max/avg), emitted when the parent ends: on
+// End for spans started with Child, when the request completes for the
+// logger the server middlewares inject.
+func Aggregated(n int) ChildOption {
+	return func(c *childConfig) {
+		c.aggregateAfter = n
+	}
+}
+
+// aggregator counts the children of one span. Every logger owning a span
+// gets its own when it is built, and copies made by the With* methods share
+// it, so the pointer never changes after construction.
+type aggregator struct {
+	mu     sync.Mutex
+	counts map[string]int
+	spans  map[string]*spanAggregate
+}
+
+type spanAggregate struct {
+	mu    sync.Mutex
+	name  string
+	count int
+	min   time.Duration
+	max   time.Duration
+	total time.Duration
+	first time.Time
+	last  time.Time
+}
+
+// admit returns nil while the child may still create a real span, and the
+// shared aggregate once the threshold has been passed. Noop loggers have no
+// aggregator and always get real children.
+func (a *aggregator) admit(name string, limit int) *spanAggregate {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.counts == nil {
+		a.counts = map[string]int{}
+		a.spans = map[string]*spanAggregate{}
+	}
+	a.counts[name]++
+	if a.counts[name] <= limit {
+		return nil
+	}
+	agg, ok := a.spans[name]
+	if !ok {
+		agg = &spanAggregate{name: name}
+		a.spans[name] = agg
+	}
+	return agg
+}
+
+func (a *spanAggregate) record(start, end time.Time) {
+	d := end.Sub(start)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.count == 0 || d < a.min {
+		a.min = d
+	}
+	if d > a.max {
+		a.max = d
+	}
+	if a.first.IsZero() || start.Before(a.first) {
+		a.first = start
+	}
+	if end.After(a.last) {
+		a.last = end
+	}
+	a.count++
+	a.total += d
+}
+
+// FlushAggregates emits the summary spans of the children aggregated so
+// far. End calls it; the server middlewares call it for the request logger
+// before the server span ends.
+func (l *Eotel) FlushAggregates() {
+	if l == nil || l.aggs == nil || l.tracer == nil {
+		return
+	}
+
+	l.aggs.mu.Lock()
+	spans := l.aggs.spans
+	l.aggs.spans = map[string]*spanAggregate{}
+	l.aggs.mu.Unlock()
+
+	for _, agg := range spans {
+		agg.mu.Lock()
+		if agg.count == 0 {
+			agg.mu.Unlock()
+			continue
+		}
+		avg := agg.total / time.Duration(agg.count)
+		_, span := l.tracer.Start(l.ctx, agg.name, trace.WithTimestamp(agg.first))
+		span.SetAttributes(
+			attribute.Bool("aggregated", true),
+			attribute.Int("aggregated.count", agg.count),
+			attribute.Float64("aggregated.min_ms", agg.min.Seconds()*1000),
+			attribute.Float64("aggregated.max_ms", agg.max.Seconds()*1000),
+			attribute.Float64("aggregated.avg_ms", avg.Seconds()*1000),
+		)
+		span.End(trace.WithTimestamp(agg.last))
+		agg.mu.Unlock()
+	}
+}
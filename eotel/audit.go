@@ -0,0 +1,132 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrAuditIncomplete is returned for an audit event missing its actor,
+	// action or resource.
+	ErrAuditIncomplete = errors.New("eotel: audit event needs actor, action and resource")
+	// ErrAuditUndelivered is returned when no destination accepted the event:
+	// Loki is disabled or its queue is full, and there is no AuditSink.
+	ErrAuditUndelivered = errors.New("eotel: audit event could not be enqueued")
+)
+
+// AuditFields describes who did what to which resource.
+type AuditFields struct {
+	Actor    string
+	Action   string
+	Resource string
+	Outcome  string
+	Fields   map[string]any
+}
+
+// AuditRecord is an audit event as handed to Config.AuditSink.
+type AuditRecord struct {
+	Time     time.Time
+	Event    string
+	Actor    string
+	Action   string
+	Resource string
+	Outcome  string
+	Service  string
+	TraceID  string
+	SpanID   string
+	Fields   map[string]any
+}
+
+// Audit records a compliance event. Unlike the log methods it ignores
+// MinLevel, log sampling and rate limits, and is exported to Loki on its own
+// "audit" level stream (spilling to the disk buffer when the queue is full)
+// and to Config.AuditSink. It returns an error when no destination took it,
+// so callers can refuse the audited operation.
+func (l *Eotel) Audit(event string, f AuditFields) error {
+	if l == nil {
+		return ErrAuditUndelivered
+	}
+	if f.Actor == "" || f.Action == "" || f.Resource == "" {
+		return ErrAuditIncomplete
+	}
+	if isShutdown.Load() {
+		return ErrAuditUndelivered
+	}
+	span := l.activeSpan()
+	sc := span.SpanContext()
+	rec := AuditRecord{
+		Time:     time.Now(),
+		Event:    event,
+		Actor:    f.Actor,
+		Action:   f.Action,
+		Resource: f.Resource,
+		Outcome:  f.Outcome,
+		Service:  l.serviceName(),
+		Fields:   activeRedactor.Load().redactMap(f.Fields),
+	}
+	if sc.IsValid() {
+		rec.TraceID, rec.SpanID = sc.TraceID().String(), sc.SpanID().String()
+	}
+
+	fields := rec.fields()
+	if l.logger != nil {
+		zf := make([]zap.Field, 0, 3+len(fields))
+		zf = append(zf, zap.Bool("audit", true), zap.String("trace_id", rec.TraceID), zap.String("span_id", rec.SpanID))
+		for k, v := range fields {
+			zf = append(zf, zap.Any(k, v))
+		}
+		l.logger.Info(event, zf...)
+	}
+	if span.IsRecording() {
+		span.AddEvent("audit", trace.WithAttributes(
+			attribute.String("audit.event", event),
+			attribute.String("audit.actor", f.Actor),
+			attribute.String("audit.action", f.Action),
+			attribute.String("audit.resource", f.Resource),
+		))
+	}
+
+	cfg := currentConfig()
+	delivered := false
+	var errs []error
+	if cfg.EnableLoki {
+		if p := lokiClient.Load(); p != nil {
+			labels, line := buildLokiEntry(*cfg, "audit", event, rec.TraceID, rec.SpanID, fields)
+			entry := LokiEntry{Labels: labels, Message: line, Time: rec.Time, OrgID: lokiOrgID(l.ctx)}
+			if p.tryEnqueue(entry) || p.spill.spillLoki([]LokiEntry{entry}) {
+				delivered = true
+			}
+		}
+	}
+	if sink := cfg.AuditSink; sink != nil {
+		if err := sink(context.WithoutCancel(l.ctx), rec); err != nil {
+			errs = append(errs, fmt.Errorf("audit sink: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+	if !delivered {
+		return errors.Join(append([]error{ErrAuditUndelivered}, errs...)...)
+	}
+	return nil
+}
+
+func (r AuditRecord) fields() map[string]any {
+	m := make(map[string]any, 4+len(r.Fields))
+	for k, v := range r.Fields {
+		m[k] = v
+	}
+	m["audit.actor"] = r.Actor
+	m["audit.action"] = r.Action
+	m["audit.resource"] = r.Resource
+	if r.Outcome != "" {
+		m["audit.outcome"] = r.Outcome
+	}
+	return m
+}
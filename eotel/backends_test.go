@@ -0,0 +1,48 @@
+package eotel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The core is tested without the backend modules, as a service importing
+// only the core runs.
+
+func TestUnregisteredBackendsNameTheirModule(t *testing.T) {
+	if _, err := newSentryBackend(Config{SentryDSN: "https://public@example.com/1"}); err == nil || !strings.Contains(err.Error(), "eotelsentry") {
+		t.Errorf("sentry: err = %v, want a pointer to eotelsentry", err)
+	}
+	if _, _, _, err := newPrometheusReader(Config{}); err == nil || !strings.Contains(err.Error(), "eotelprom") {
+		t.Errorf("prometheus reader: err = %v, want a pointer to eotelprom", err)
+	}
+	if _, _, err := newPushGateway(Config{MetricsPushGatewayURL: "http://127.0.0.1:9091"}); err == nil || !strings.Contains(err.Error(), "eotelprom") {
+		t.Errorf("push gateway: err = %v, want a pointer to eotelprom", err)
+	}
+}
+
+func TestLogFileWithoutRotator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("earlier\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := logWriter(path, RotationConfig{MaxSizeMB: 1})
+	if err != nil {
+		t.Fatalf("logWriter: %v", err)
+	}
+	if _, err := w.Write([]byte("later\n")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "earlier\nlater\n" {
+		t.Errorf("file = %q, want the entry appended", got)
+	}
+
+	if _, err := logWriter(filepath.Join(path, "nested.log"), RotationConfig{}); err == nil {
+		t.Error("logWriter under a file: want an error")
+	}
+}
@@ -0,0 +1,91 @@
+package eotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// DefaultPromotedBaggage lists the baggage members promoted when
+// Config.PromotedBaggage is nil.
+var DefaultPromotedBaggage = []string{"tenant_id", "user_id", "request_id"}
+
+// WithBaggage returns a logger whose context carries key=value as W3C
+// baggage, so it travels with every outgoing call made with Ctx(). Invalid
+// keys are ignored.
+func (l *Eotel) WithBaggage(key, value string) *Eotel {
+	if l == nil {
+		return Noop("WithBaggage")
+	}
+	m, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return l
+	}
+	b, err := baggage.FromContext(l.ctx).SetMember(m)
+	if err != nil {
+		return l
+	}
+	cp := l.clone()
+	cp.ctx = baggage.ContextWithBaggage(l.ctx, b)
+	return cp
+}
+
+func promotedBaggageKeys() []string {
+	cfg := currentConfig()
+	if cfg.PromotedBaggage != nil {
+		return cfg.PromotedBaggage
+	}
+	return DefaultPromotedBaggage
+}
+
+// promotedBaggage calls fn for each promoted member present in ctx.
+func promotedBaggage(ctx context.Context, fn func(key, value string)) {
+	if ctx == nil {
+		return
+	}
+	b := baggage.FromContext(ctx)
+	if b.Len() == 0 {
+		return
+	}
+	for _, k := range promotedBaggageKeys() {
+		if m := b.Member(k); m.Key() != "" {
+			fn(k, m.Value())
+		}
+	}
+}
+
+// baggageFields appends the promoted baggage members of ctx to fields.
+func baggageFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	promotedBaggage(ctx, func(k, v string) {
+		fields = append(fields[:len(fields):len(fields)], zap.String(k, v))
+	})
+	return fields
+}
+
+// baggageProcessor sets the promoted baggage members as attributes on the
+// spans at service boundaries: server and consumer spans after extraction,
+// client and producer spans before injection. An idempotency key goes on
+// every span.
+type baggageProcessor struct{}
+
+func (baggageProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if key := idempotencyKey(parent); key != "" {
+		s.SetAttributes(attribute.String("idempotency.key", key))
+	}
+	if s.SpanKind() == trace.SpanKindInternal || s.SpanKind() == trace.SpanKindUnspecified {
+		return
+	}
+	promotedBaggage(parent, func(k, v string) {
+		s.SetAttributes(attribute.String(k, v))
+	})
+}
+
+func (baggageProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (baggageProcessor) Shutdown(context.Context) error { return nil }
+
+func (baggageProcessor) ForceFlush(context.Context) error { return nil }
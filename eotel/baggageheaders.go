@@ -0,0 +1,69 @@
+package eotel
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// headerBaggage copies the headers listed in Config.BaggageHeaders into
+// baggage on extract and writes them back out as plain headers on inject.
+// Every eotel entry and exit point (HTTP and gRPC middleware and clients,
+// Kafka producers and consumers) goes through the global propagator, so the
+// headers follow the request without application code.
+type headerBaggage struct {
+	headers []string
+}
+
+func newHeaderBaggage(headers []string) headerBaggage {
+	hb := headerBaggage{}
+	for _, h := range headers {
+		if h = strings.TrimSpace(h); h != "" {
+			hb.headers = append(hb.headers, h)
+		}
+	}
+	return hb
+}
+
+// memberKey is the baggage key for header h; header names are
+// case-insensitive so the key is lowercased.
+func (headerBaggage) memberKey(h string) string {
+	return strings.ToLower(h)
+}
+
+func (p headerBaggage) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	bag := baggage.FromContext(ctx)
+	for _, h := range p.headers {
+		if m := bag.Member(p.memberKey(h)); m.Key() != "" {
+			carrier.Set(h, m.Value())
+		}
+	}
+}
+
+func (p headerBaggage) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	bag := baggage.FromContext(ctx)
+	changed := false
+	for _, h := range p.headers {
+		v := carrier.Get(h)
+		if v == "" {
+			continue
+		}
+		m, err := baggage.NewMemberRaw(p.memberKey(h), v)
+		if err != nil {
+			continue
+		}
+		if b, err := bag.SetMember(m); err == nil {
+			bag, changed = b, true
+		}
+	}
+	if !changed {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func (p headerBaggage) Fields() []string {
+	return p.headers
+}
@@ -0,0 +1,172 @@
+package eotel
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultBodyCaptureMaxBytes = 4096
+
+// BodyCapture records JSON request and response bodies, up to MaxBytes
+// each, as an http.body span event and as fields of the "request completed"
+// log line. Bodies are only attached to requests that failed (status 400 or
+// above), that carry Header (when set), or to every request with Always.
+// Redaction rules apply to JSON keys and string values. Zero MaxBytes
+// disables capture; negative means the default 4 KiB.
+type BodyCapture struct {
+	MaxBytes int    `yaml:"max_bytes"`
+	Header   string `yaml:"header"`
+	Always   bool   `yaml:"always"`
+}
+
+func (bc BodyCapture) enabled() bool { return bc.MaxBytes != 0 }
+
+func (bc BodyCapture) limit() int {
+	if bc.MaxBytes < 0 {
+		return defaultBodyCaptureMaxBytes
+	}
+	return bc.MaxBytes
+}
+
+// WithBodyCapture enables body capture for the router middleware,
+// overriding Config.BodyCapture.
+func WithBodyCapture(bc BodyCapture) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.bodies = &bc
+	}
+}
+
+// bodyTap keeps the first max bytes written to it and counts the rest.
+type bodyTap struct {
+	buf   bytes.Buffer
+	max   int
+	total int
+}
+
+func (t *bodyTap) Write(p []byte) (int, error) {
+	if room := t.max - t.buf.Len(); room > 0 {
+		t.buf.Write(p[:min(room, len(p))])
+	}
+	t.total += len(p)
+	return len(p), nil
+}
+
+func (t *bodyTap) truncated() bool { return t.total > t.buf.Len() }
+
+type tapReadCloser struct {
+	io.ReadCloser
+	tap *bodyTap
+}
+
+func (r tapReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	_, _ = r.tap.Write(p[:n])
+	return n, err
+}
+
+type tapResponseWriter struct {
+	http.ResponseWriter
+	tap *bodyTap
+}
+
+func (w *tapResponseWriter) Write(b []byte) (int, error) {
+	_, _ = w.tap.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *tapResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// bodyCapture holds the taps of one request.
+type bodyCapture struct {
+	cfg  BodyCapture
+	req  *bodyTap
+	resp *bodyTap
+}
+
+// tapRequest starts capturing r's body, returning nil when capture is off.
+func tapRequest(cfg *BodyCapture, r *http.Request) *bodyCapture {
+	bc := currentConfig().BodyCapture
+	if cfg != nil {
+		bc = *cfg
+	}
+	if !bc.enabled() {
+		return nil
+	}
+	c := &bodyCapture{cfg: bc, req: &bodyTap{max: bc.limit()}, resp: &bodyTap{max: bc.limit()}}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = tapReadCloser{ReadCloser: r.Body, tap: c.req}
+	}
+	return c
+}
+
+// attach adds the captured bodies to span and logger when the request
+// qualifies.
+func (c *bodyCapture) attach(span trace.Span, logger *Eotel, r *http.Request, respHeader http.Header, status int) *Eotel {
+	if c == nil {
+		return logger
+	}
+	if status < http.StatusBadRequest && !c.cfg.Always && (c.cfg.Header == "" || r.Header.Get(c.cfg.Header) == "") {
+		return logger
+	}
+	var attrs []attribute.KeyValue
+	add := func(key string, tap *bodyTap, contentType string) {
+		body, ok := renderBody(tap, contentType)
+		if !ok {
+			return
+		}
+		attrs = append(attrs, attribute.String(key, body))
+		logger = logger.WithField(key, body)
+		if tap.truncated() {
+			attrs = append(attrs, attribute.Bool(key+".truncated", true))
+			logger = logger.WithField(key+".truncated", true)
+		}
+	}
+	add("http.request.body", c.req, r.Header.Get("Content-Type"))
+	add("http.response.body", c.resp, respHeader.Get("Content-Type"))
+	if len(attrs) > 0 {
+		span.AddEvent("http.body", trace.WithAttributes(attrs...))
+	}
+	return logger
+}
+
+// renderBody returns a redacted JSON body. Truncated or invalid JSON is
+// kept as text with the redaction patterns applied.
+func renderBody(tap *bodyTap, contentType string) (string, bool) {
+	if tap.buf.Len() == 0 || !strings.Contains(strings.ToLower(contentType), "json") {
+		return "", false
+	}
+	rd := activeRedactor.Load()
+	var v any
+	if !tap.truncated() && json.Unmarshal(tap.buf.Bytes(), &v) == nil {
+		if b, err := json.Marshal(rd.redactJSON("", v)); err == nil {
+			return string(b), true
+		}
+	}
+	return rd.scrub(tap.buf.String()), true
+}
+
+// redactJSON applies redactValue through decoded JSON.
+func (rd *redactor) redactJSON(key string, v any) any {
+	if rd == nil {
+		return v
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = rd.redactJSON(k, e)
+		}
+		return t
+	case []any:
+		for i, e := range t {
+			t[i] = rd.redactJSON(key, e)
+		}
+		return t
+	}
+	return rd.redactValue(key, v)
+}
@@ -0,0 +1,59 @@
+package eotel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	initialized   atomic.Bool
+	strictInit    atomic.Bool
+	bootstrapOnce sync.Once
+)
+
+// SetStrictInit makes New and the middlewares panic when used before
+// InitEOTEL instead of falling back to the bootstrap config. EOTEL_STRICT_INIT
+// and Config.StrictInit have the same effect.
+func SetStrictInit(strict bool) {
+	strictInit.Store(strict)
+}
+
+func strictInitEnabled() bool {
+	if strictInit.Load() {
+		return true
+	}
+	strict, _ := strconv.ParseBool(os.Getenv("EOTEL_STRICT_INIT"))
+	return strict
+}
+
+// ensureInit reports use of op before InitEOTEL: it panics in strict mode,
+// otherwise it fills in a bootstrap service name (OTEL_SERVICE_NAME or the
+// executable name) and warns once on stderr.
+func ensureInit(op string) {
+	if initialized.Load() {
+		return
+	}
+	if strictInitEnabled() {
+		panic(fmt.Sprintf("eotel: %s called before InitEOTEL", op))
+	}
+	bootstrapOnce.Do(func() {
+		name := os.Getenv("OTEL_SERVICE_NAME")
+		if name == "" {
+			name = filepath.Base(os.Args[0])
+		}
+		prev := globalCfg.Load()
+		cfg := *currentConfig()
+		if cfg.ServiceName == "" {
+			cfg.ServiceName = name
+		}
+		if cfg.JobName == "" {
+			cfg.JobName = name
+		}
+		globalCfg.CompareAndSwap(prev, &cfg)
+		fmt.Fprintf(os.Stderr, "eotel: %s called before InitEOTEL, using bootstrap config (service %q)\n", op, cfg.ServiceName)
+	})
+}
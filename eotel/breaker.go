@@ -0,0 +1,193 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+const defaultBreakerOpenFor = 30 * time.Second
+
+// CircuitBreaker stops exporting to a backend after Failures consecutive
+// failed exports, so a dead collector or Loki costs the application nothing
+// but a counter. While open, batches are dropped and counted in
+// eotel.export.rejected; after OpenFor a single trial export decides whether
+// it closes again. Zero Failures disables the breaker.
+type CircuitBreaker struct {
+	Failures int           `yaml:"failures"`
+	OpenFor  time.Duration `yaml:"open_for"`
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type breaker struct {
+	backend   string
+	threshold int
+	openFor   time.Duration
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openUntil time.Time
+
+	rejected metric.Int64Counter
+}
+
+// newBreaker returns nil when cfg disables breaking; a nil breaker allows
+// everything.
+func newBreaker(backend string, cfg CircuitBreaker, status *pipelineStatus, meter metric.Meter) *breaker {
+	if cfg.Failures <= 0 {
+		status.circuit.Store(nil)
+		return nil
+	}
+	b := &breaker{backend: backend, threshold: cfg.Failures, openFor: cfg.OpenFor}
+	if b.openFor <= 0 {
+		b.openFor = defaultBreakerOpenFor
+	}
+	b.rejected, _ = meter.Int64Counter("eotel.export.rejected",
+		metric.WithUnit("{batch}"),
+		metric.WithDescription("Export batches dropped while a backend's circuit breaker was open."))
+	status.circuit.Store(b)
+	return b
+}
+
+// allow reports whether an export may be attempted. Once OpenFor has passed
+// a single caller is let through as the trial.
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if time.Now().Before(b.openUntil) {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	}
+	return true
+}
+
+// done records the outcome of an export let through by allow.
+func (b *breaker) done(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if b.state != breakerClosed {
+			getLogger().Info("eotel: export circuit closed", zap.String("backend", b.backend))
+		}
+		b.state, b.failures = breakerClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state == breakerClosed {
+			getLogger().Warn("eotel: export circuit open, dropping telemetry",
+				zap.String("backend", b.backend),
+				zap.Int("consecutive_failures", b.failures),
+				zap.Error(err))
+		}
+		b.state = breakerOpen
+		b.openUntil = time.Now().Add(b.openFor)
+	}
+}
+
+func (b *breaker) open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != breakerClosed
+}
+
+func (b *breaker) reject(n int, status *pipelineStatus) {
+	status.drop(n)
+	b.rejected.Add(context.Background(), 1, metric.WithAttributes(attribute.String("backend", b.backend)))
+}
+
+// breakerSpanExporter also owns the disk buffer, if any: rejected and failed
+// batches spill to it and a successful export replays it.
+type breakerSpanExporter struct {
+	sdktrace.SpanExporter
+	breaker *breaker
+	status  *pipelineStatus
+	spill   *spillQueue
+}
+
+func (e breakerSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if !e.breaker.allow() {
+		if !e.spill.spillSpans(spans) {
+			e.breaker.reject(len(spans), e.status)
+		}
+		return nil
+	}
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.breaker.done(err)
+	if err != nil {
+		if e.spill.spillSpans(spans) {
+			return nil
+		}
+		return err
+	}
+	e.spill.replaySpans(e.SpanExporter)
+	return nil
+}
+
+func (e breakerSpanExporter) Shutdown(ctx context.Context) error {
+	e.spill.close()
+	return e.SpanExporter.Shutdown(ctx)
+}
+
+type breakerMetricExporter struct {
+	sdkmetric.Exporter
+	breaker *breaker
+	status  *pipelineStatus
+}
+
+func (e breakerMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	if !e.breaker.allow() {
+		e.breaker.reject(1, e.status)
+		return nil
+	}
+	err := e.Exporter.Export(ctx, rm)
+	e.breaker.done(err)
+	return err
+}
+
+type breakerLogExporter struct {
+	sdklog.Exporter
+	breaker *breaker
+	status  *pipelineStatus
+}
+
+func (e breakerLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if !e.breaker.allow() {
+		e.breaker.reject(len(records), e.status)
+		return nil
+	}
+	err := e.Exporter.Export(ctx, records)
+	e.breaker.done(err)
+	return err
+}
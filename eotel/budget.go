@@ -0,0 +1,85 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	defaultLokiTimeout   = 10 * time.Second
+	defaultSentryTimeout = 2 * time.Second
+	defaultOTLPTimeout   = 10 * time.Second
+	defaultCustomTimeout = 5 * time.Second
+
+	defaultLokiPushTimeout = time.Minute
+)
+
+// ExporterTimeouts bounds each backend individually so a slow one cannot
+// stall the others.
+type ExporterTimeouts struct {
+	Loki time.Duration `yaml:"loki"`
+	// LokiPush bounds one Loki batch across all its retries (1m by
+	// default); Loki bounds each attempt.
+	LokiPush time.Duration `yaml:"loki_push"`
+	Sentry   time.Duration `yaml:"sentry"`
+	OTLP     time.Duration `yaml:"otlp"`
+	// Custom bounds each call into an exporter added with RegisterExporter,
+	// Config.Exporters or WithScopeExporter, such as a webhook (5s by
+	// default); WithExporterTimeout overrides it per exporter.
+	Custom time.Duration `yaml:"custom"`
+}
+
+func timeoutOr(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+var (
+	budgetExceededOnce sync.Once
+	budgetExceeded     metric.Int64Counter
+)
+
+// exportContext returns a context for exporting on behalf of parent: it
+// keeps parent's values (tenant, baggage, span) but not its cancellation,
+// and expires after timeout.
+func exportContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	return context.WithTimeout(context.WithoutCancel(parent), timeout)
+}
+
+// withinBudget runs fn and waits at most Config.LogExportBudget for it. When
+// the budget is exhausted the caller moves on and fn completes in the
+// background. A zero budget runs fn inline.
+func withinBudget(fn func()) {
+	budget := currentConfig().LogExportBudget
+	if budget <= 0 {
+		fn()
+		return
+	}
+
+	done := make(chan struct{})
+	goTracked(func() {
+		defer close(done)
+		fn()
+	})
+
+	timer := time.NewTimer(budget)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		budgetExceededOnce.Do(func() {
+			budgetExceeded, _ = getMeter().Int64Counter("eotel_export_budget_exceeded_total")
+		})
+		if budgetExceeded != nil {
+			budgetExceeded.Add(context.Background(), 1)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package eotel
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Capture is the telemetry recorded for one request in capture mode, as
+// written to and read back from a capture file.
+type Capture struct {
+	TraceID string           `json:"trace_id"`
+	Logs    []CapturedLog    `json:"logs"`
+	Spans   []CapturedSpan   `json:"spans"`
+	Metrics []CapturedMetric `json:"metrics"`
+}
+
+type CapturedLog struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+type CapturedSpan struct {
+	SpanID     string         `json:"span_id"`
+	ParentID   string         `json:"parent_id,omitempty"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Status     string         `json:"status,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type CapturedMetric struct {
+	Time       time.Time      `json:"time"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Value      float64        `json:"value"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// captures holds the requests being captured, by trace ID.
+var captures struct {
+	mu     sync.Mutex
+	active atomic.Int32
+	byID   map[trace.TraceID]*Capture
+}
+
+// captureRequested reports whether a request carrying header value v asked
+// for capture: Config.CaptureHeader must be set and, when CaptureToken is
+// configured, v must match it.
+func captureRequested(v string) bool {
+	cfg := currentConfig()
+	if cfg.CaptureHeader == "" || v == "" {
+		return false
+	}
+	return cfg.CaptureToken == "" || v == cfg.CaptureToken
+}
+
+func startCapture(id trace.TraceID) {
+	captures.mu.Lock()
+	defer captures.mu.Unlock()
+	if captures.byID == nil {
+		captures.byID = map[trace.TraceID]*Capture{}
+	}
+	if _, ok := captures.byID[id]; !ok {
+		captures.byID[id] = &Capture{TraceID: id.String()}
+		captures.active.Add(1)
+	}
+}
+
+// withCapture runs fn on the capture for id, if one is active.
+func withCapture(id trace.TraceID, fn func(c *Capture)) {
+	if captures.active.Load() == 0 || !id.IsValid() {
+		return
+	}
+	captures.mu.Lock()
+	defer captures.mu.Unlock()
+	if c, ok := captures.byID[id]; ok {
+		fn(c)
+	}
+}
+
+// finishCapture writes the capture for id to Config.CaptureDir.
+func finishCapture(id trace.TraceID) {
+	captures.mu.Lock()
+	c, ok := captures.byID[id]
+	delete(captures.byID, id)
+	captures.mu.Unlock()
+	if !ok {
+		return
+	}
+	captures.active.Add(-1)
+
+	if err := writeCapture(c); err != nil {
+		getLogger().Warn("eotel: write capture", zap.String("trace_id", c.TraceID), zap.Error(err))
+	}
+}
+
+func captureDir() string {
+	cfg := currentConfig()
+	if cfg.CaptureDir != "" {
+		return cfg.CaptureDir
+	}
+	return filepath.Join(os.TempDir(), "eotel-captures")
+}
+
+func writeCapture(c *Capture) error {
+	dir := captureDir()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, c.TraceID+".json"), data, 0o640)
+}
+
+func captureLog(id trace.TraceID, level, msg string, fields []zap.Field) {
+	withCapture(id, func(c *Capture) {
+		c.Logs = append(c.Logs, capturedLog(level, msg, fields))
+	})
+}
+
+func capturedLog(level, msg string, fields []zap.Field) CapturedLog {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return CapturedLog{Time: time.Now(), Level: level, Message: msg, Fields: enc.Fields}
+}
+
+func captureMetric(ctx context.Context, name, kind string, value float64, attrs []attribute.KeyValue) {
+	if captures.active.Load() == 0 || ctx == nil {
+		return
+	}
+	withCapture(trace.SpanContextFromContext(ctx).TraceID(), func(c *Capture) {
+		c.Metrics = append(c.Metrics, CapturedMetric{
+			Time: time.Now(), Name: name, Kind: kind, Value: value, Attributes: attrsMap(attrs),
+		})
+	})
+}
+
+func attrsMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+// captureProcessor copies ended spans of captured traces.
+type captureProcessor struct{}
+
+func (captureProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (captureProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	withCapture(s.SpanContext().TraceID(), func(c *Capture) {
+		c.Spans = append(c.Spans, capturedSpan(s))
+	})
+}
+
+func capturedSpan(s sdktrace.ReadOnlySpan) CapturedSpan {
+	cs := CapturedSpan{
+		SpanID:     s.SpanContext().SpanID().String(),
+		Name:       s.Name(),
+		Kind:       s.SpanKind().String(),
+		Start:      s.StartTime(),
+		End:        s.EndTime(),
+		Attributes: attrsMap(s.Attributes()),
+	}
+	if s.Parent().HasSpanID() && s.Parent().TraceID() == s.SpanContext().TraceID() {
+		cs.ParentID = s.Parent().SpanID().String()
+	}
+	if s.Status().Code == codes.Error {
+		cs.Status = s.Status().Description
+		if cs.Status == "" {
+			cs.Status = "error"
+		}
+	}
+	return cs
+}
+
+func (captureProcessor) Shutdown(context.Context) error { return nil }
+
+func (captureProcessor) ForceFlush(context.Context) error { return nil }
+
+// LoadCapture reads a capture file written in capture mode.
+func LoadCapture(path string) (*Capture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var c Capture
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(&c); err != nil {
+		return nil, fmt.Errorf("decode capture %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Replay re-emits the captured spans (with their original timings and
+// hierarchy, under a new trace), logs and metrics through the current
+// pipeline.
+func (c *Capture) Replay(ctx context.Context) {
+	spans := append([]CapturedSpan(nil), c.Spans...)
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+
+	tracer := getTracer()
+	ctxByID := map[string]context.Context{}
+	root := ctx
+	for _, s := range spans {
+		parent := ctx
+		if p, ok := ctxByID[s.ParentID]; ok {
+			parent = p
+		}
+		attrs := []attribute.KeyValue{attribute.String("eotel.replay.trace_id", c.TraceID)}
+		for k, v := range s.Attributes {
+			attrs = append(attrs, attribute.String(k, fmt.Sprint(v)))
+		}
+		sctx, span := tracer.Start(parent, s.Name, trace.WithTimestamp(s.Start), trace.WithAttributes(attrs...))
+		if s.Status != "" {
+			span.SetStatus(codes.Error, s.Status)
+		}
+		span.End(trace.WithTimestamp(s.End))
+		ctxByID[s.SpanID] = sctx
+		if s.ParentID == "" && root == ctx {
+			root = sctx
+		}
+	}
+
+	logger := New(root, "replay").WithField("eotel.replay.trace_id", c.TraceID)
+	for _, l := range c.Logs {
+		logger.WithFields(l.Fields).log(l.Level, l.Message)
+	}
+	for _, m := range c.Metrics {
+		var attrs []attribute.KeyValue
+		for k, v := range m.Attributes {
+			attrs = append(attrs, attribute.String(k, fmt.Sprint(v)))
+		}
+		switch m.Kind {
+		case "counter":
+			logger.Count(m.Name, int64(m.Value), attrs...)
+		case "histogram":
+			logger.Record(m.Name, m.Value, attrs...)
+		case "gauge":
+			logger.Gauge(m.Name).Set(m.Value, attrs...)
+		}
+	}
+}
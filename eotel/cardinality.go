@@ -0,0 +1,84 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	defaultMaxLabelValues = 500
+	overflowValue         = "overflow"
+)
+
+// cardinalityGuard remembers the distinct values seen per metric label.
+// Once a label reaches the limit, unseen values are replaced by "overflow"
+// so unbounded inputs (paths, tenant IDs) cannot explode the series count.
+type cardinalityGuard struct {
+	mu     sync.Mutex
+	values map[[2]string]map[string]struct{}
+
+	overflowOnce sync.Once
+	overflow     metric.Int64Counter
+}
+
+var labelGuard = &cardinalityGuard{values: map[[2]string]map[string]struct{}{}}
+
+func maxLabelValues() int {
+	cfg := currentConfig()
+	if cfg.MaxLabelValues == 0 {
+		return defaultMaxLabelValues
+	}
+	return cfg.MaxLabelValues
+}
+
+// guard returns attrs with over-limit values replaced. attrs is not modified.
+func (g *cardinalityGuard) guard(name string, attrs []attribute.KeyValue) []attribute.KeyValue {
+	limit := maxLabelValues()
+	if limit < 0 || len(attrs) == 0 {
+		return attrs
+	}
+
+	var out []attribute.KeyValue
+	g.mu.Lock()
+	for i, kv := range attrs {
+		key := [2]string{name, string(kv.Key)}
+		seen, ok := g.values[key]
+		if !ok {
+			seen = map[string]struct{}{}
+			g.values[key] = seen
+		}
+		v := kv.Value.Emit()
+		if _, ok := seen[v]; ok || len(seen) < limit {
+			seen[v] = struct{}{}
+			continue
+		}
+		if out == nil {
+			out = append([]attribute.KeyValue(nil), attrs...)
+		}
+		out[i] = attribute.String(string(kv.Key), overflowValue)
+		g.reportOverflow(name, string(kv.Key))
+	}
+	g.mu.Unlock()
+
+	if out == nil {
+		return attrs
+	}
+	return out
+}
+
+func (g *cardinalityGuard) reportOverflow(name, label string) {
+	g.overflowOnce.Do(func() {
+		g.overflow, _ = getMeter().Int64Counter("eotel.metric.label_overflow",
+			metric.WithUnit("{value}"),
+			metric.WithDescription("Label values replaced by \"overflow\" after the cardinality limit was reached."))
+	})
+	if g.overflow != nil {
+		g.overflow.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("metric", name),
+			attribute.String("label", label),
+		))
+	}
+}
@@ -0,0 +1,30 @@
+package eotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ChildOption func(*childConfig)
+
+type childConfig struct {
+	aggregateAfter int
+	kind           trace.SpanKind
+	attrs          []attribute.KeyValue
+}
+
+// WithSpanKind sets the kind of the child span, e.g. trace.SpanKindClient for
+// an outgoing call. Children are internal spans by default.
+func WithSpanKind(kind trace.SpanKind) ChildOption {
+	return func(c *childConfig) {
+		c.kind = kind
+	}
+}
+
+// WithSpanAttributes sets attributes on the child span at creation, where
+// samplers can see them.
+func WithSpanAttributes(attrs ...attribute.KeyValue) ChildOption {
+	return func(c *childConfig) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
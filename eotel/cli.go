@@ -0,0 +1,88 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+)
+
+const cliShutdownTimeout = 10 * time.Second
+
+// CLI is the root logger of a command-line run. Its span covers the whole
+// command and is closed, with the exit code, by Exit or Run.
+type CLI struct {
+	*Eotel
+	shutdown func(context.Context) error
+}
+
+// InitCLI initialises eotel for a command-line tool: configuration is read
+// from the environment (see LoadConfig) with name as the service name, logs
+// use the console encoder, spans are exported synchronously, and a single
+// root span named after the command is started.
+func InitCLI(name string) (*CLI, error) {
+	cfg, err := loadConfig(os.Getenv("EOTEL_CONFIG_FILE"), Config{ServiceName: name, JobName: name})
+	if err != nil {
+		return nil, err
+	}
+	cfg.SyncExport = true
+	if cfg.LokiBatchInterval == 0 {
+		cfg.LokiBatchInterval = 100 * time.Millisecond
+	}
+	if cfg.Logger == nil {
+		logger, err := zap.NewDevelopment()
+		if err != nil {
+			return nil, fmt.Errorf("console logger: %w", err)
+		}
+		cfg.Logger = logger
+	}
+
+	shutdown, err := InitEOTEL(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	root := New(context.Background(), name).StartSpan(name).
+		WithField("process.command_args", os.Args)
+	return &CLI{Eotel: root, shutdown: shutdown}, nil
+}
+
+// Exit records the exit code, ends the root span, flushes every pipeline and
+// exits the process.
+func (c *CLI) Exit(code int) {
+	c.Eotel.Span().SetAttributes(attribute.Int("process.exit.code", code))
+	if code != 0 {
+		c.Eotel.Span().SetStatus(codes.Error, fmt.Sprintf("exit code %d", code))
+	}
+	c.Eotel.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cliShutdownTimeout)
+	if err := c.shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "eotel: shutdown: %v\n", err)
+	}
+	cancel()
+	_ = c.Eotel.logger.Sync()
+	os.Exit(code)
+}
+
+// Run executes fn under the root span and exits with 0 on success, the code
+// of an ExitCoder error, or 1 otherwise.
+func (c *CLI) Run(fn func(ctx context.Context) error) {
+	err := fn(c.Ctx())
+	code := 0
+	if err != nil {
+		code = 1
+		var ec interface{ ExitCode() int }
+		if errors.As(err, &ec) {
+			code = ec.ExitCode()
+		}
+		c.Eotel = c.Eotel.WithError(err)
+		c.Eotel.Error("command failed")
+	}
+	c.Exit(code)
+}
@@ -0,0 +1,165 @@
+package eotel
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// OTLPRetry tunes how the OTLP exporters retry a batch the collector
+// refused as temporarily unavailable. Zero durations keep the SDK defaults:
+// first retry after 5s, at most 30s apart, for at most 1m.
+// ExporterTimeouts.OTLP still bounds each batch, retries included.
+type OTLPRetry struct {
+	Disabled        bool          `yaml:"disabled"`
+	InitialInterval time.Duration `yaml:"initial_interval"`
+	MaxInterval     time.Duration `yaml:"max_interval"`
+	MaxElapsedTime  time.Duration `yaml:"max_elapsed_time"`
+}
+
+// retryConfig has the layout of the exporters' RetryConfig types, so it
+// converts to each of them.
+type retryConfig struct {
+	Enabled         bool
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxElapsedTime  time.Duration
+}
+
+func (r OTLPRetry) config() retryConfig {
+	return retryConfig{
+		Enabled:         !r.Disabled,
+		InitialInterval: timeoutOr(r.InitialInterval, 5*time.Second),
+		MaxInterval:     timeoutOr(r.MaxInterval, 30*time.Second),
+		MaxElapsedTime:  timeoutOr(r.MaxElapsedTime, time.Minute),
+	}
+}
+
+// collectorConns holds the open gRPC connections to the collector, for
+// waitCollector.
+var collectorConns struct {
+	mu    sync.Mutex
+	conns map[*grpc.ClientConn]Signal
+}
+
+// dialCollector opens the gRPC connection of one signal's exporter without
+// blocking: it connects in the background, reconnects with gRPC's backoff
+// when the collector goes away, and reports every state change to
+// Config.OnCollectorState. closeCollector releases it.
+func dialCollector(cfg Config, signal Signal, tlsCfg *tls.Config, usage string) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if cfg.EnableUsageReporting {
+		opts = append(opts, grpc.WithUnaryInterceptor(usageInterceptor(usage)))
+	}
+	conn, err := grpc.NewClient(cfg.OtelCollector, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("collector connection: %w", err)
+	}
+	collectorConns.mu.Lock()
+	if collectorConns.conns == nil {
+		collectorConns.conns = map[*grpc.ClientConn]Signal{}
+	}
+	collectorConns.conns[conn] = signal
+	collectorConns.mu.Unlock()
+
+	conn.Connect()
+	go watchCollector(conn, signal, cfg.OnCollectorState)
+	return conn, nil
+}
+
+func watchCollector(conn *grpc.ClientConn, signal Signal, notify func(Signal, connectivity.State)) {
+	state := conn.GetState()
+	for {
+		if notify != nil {
+			notify(signal, state)
+		}
+		if state == connectivity.TransientFailure {
+			getLogger().Debug("eotel: collector unreachable, reconnecting", zap.String("signal", string(signal)))
+		}
+		if state == connectivity.Shutdown || !conn.WaitForStateChange(context.Background(), state) {
+			return
+		}
+		state = conn.GetState()
+	}
+}
+
+func closeCollector(conn *grpc.ClientConn) {
+	collectorConns.mu.Lock()
+	delete(collectorConns.conns, conn)
+	collectorConns.mu.Unlock()
+	_ = conn.Close()
+}
+
+// openCollectors returns the collector connections currently open.
+func openCollectors() map[*grpc.ClientConn]Signal {
+	collectorConns.mu.Lock()
+	defer collectorConns.mu.Unlock()
+	conns := make(map[*grpc.ClientConn]Signal, len(collectorConns.conns))
+	for c, s := range collectorConns.conns {
+		conns[c] = s
+	}
+	return conns
+}
+
+// waitCollector waits up to timeout for every connection in conns to be
+// ready.
+func waitCollector(ctx context.Context, timeout time.Duration, conns map[*grpc.ClientConn]Signal) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for conn, signal := range conns {
+		for state := conn.GetState(); state != connectivity.Ready; state = conn.GetState() {
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("collector %s not ready for %s after %s: %s", conn.Target(), signal, timeout, state)
+			}
+		}
+	}
+	return nil
+}
+
+// The exporters do not close a connection they were given; these close it
+// on Shutdown.
+
+type connSpanExporter struct {
+	sdktrace.SpanExporter
+	conn *grpc.ClientConn
+}
+
+func (e connSpanExporter) Shutdown(ctx context.Context) error {
+	defer closeCollector(e.conn)
+	return e.SpanExporter.Shutdown(ctx)
+}
+
+type connMetricExporter struct {
+	sdkmetric.Exporter
+	conn *grpc.ClientConn
+}
+
+func (e connMetricExporter) Shutdown(ctx context.Context) error {
+	defer closeCollector(e.conn)
+	return e.Exporter.Shutdown(ctx)
+}
+
+type connLogExporter struct {
+	sdklog.Exporter
+	conn *grpc.ClientConn
+}
+
+func (e connLogExporter) Shutdown(ctx context.Context) error {
+	defer closeCollector(e.conn)
+	return e.Exporter.Shutdown(ctx)
+}
@@ -0,0 +1,102 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCollectorHealthInterval = 30 * time.Second
+	defaultCollectorHealthFailures = 3
+)
+
+// startCollectorHealth polls the collector's health_check extension (e.g.
+// http://collector:13133/) and reports it as the eotel.collector.up gauge.
+// A warning is logged once CollectorHealthFailures consecutive checks fail,
+// and again when the collector recovers.
+func startCollectorHealth(cfg Config) func() {
+	interval := cfg.CollectorHealthInterval
+	if interval <= 0 {
+		interval = defaultCollectorHealthInterval
+	}
+	threshold := cfg.CollectorHealthFailures
+	if threshold <= 0 {
+		threshold = defaultCollectorHealthFailures
+	}
+	client := &http.Client{Timeout: min(interval, 5*time.Second)}
+	endpoint := attribute.String("url.full", cfg.CollectorHealthURL)
+
+	var up, failures atomic.Int64
+	gauge, err := getMeter().Int64ObservableGauge("eotel.collector.up",
+		metric.WithDescription("1 when the collector health check passes, 0 otherwise."))
+	var reg metric.Registration
+	if err == nil {
+		reg, _ = getMeter().RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			o.ObserveInt64(gauge, up.Load(), metric.WithAttributes(endpoint))
+			return nil
+		}, gauge)
+	}
+
+	check := func() {
+		err := checkCollector(client, cfg.CollectorHealthURL)
+		collectorStatus.record(err)
+		if err == nil {
+			up.Store(1)
+			if failures.Swap(0) >= int64(threshold) {
+				getLogger().Info("eotel: collector healthy again", zap.String("url", cfg.CollectorHealthURL))
+			}
+			return
+		}
+		up.Store(0)
+		if n := failures.Add(1); n == int64(threshold) {
+			getLogger().Warn("eotel: collector health check failing; telemetry may be lost",
+				zap.String("url", cfg.CollectorHealthURL),
+				zap.Int64("consecutive_failures", n),
+				zap.Error(err))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		check()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				check()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			if reg != nil {
+				_ = reg.Unregister()
+			}
+		})
+	}
+}
+
+func checkCollector(client *http.Client, url string) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("collector health: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+package eotel
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/connectivity"
+)
+
+type Config struct {
+	ServiceName   string `yaml:"service_name"`
+	JobName       string `yaml:"job_name"`
+	OtelCollector string `yaml:"otel_collector"`
+
+	// ServiceVersion and DeploymentEnvironment become the service.version
+	// and deployment.environment.name resource attributes, and the Sentry
+	// release and environment.
+	ServiceVersion        string `yaml:"service_version"`
+	DeploymentEnvironment string `yaml:"deployment_environment"`
+
+	// StaticFields are attached to every log line, span, Loki stream and
+	// Sentry event; see WithGlobalFields.
+	StaticFields map[string]any `yaml:"static_fields"`
+
+	// OtelProtocol selects the OTLP transport: "grpc" (default) or "http".
+	OtelProtocol       string `yaml:"otel_protocol"`
+	OtelTracesURLPath  string `yaml:"otel_traces_url_path"`
+	OtelMetricsURLPath string `yaml:"otel_metrics_url_path"`
+	OtelLogsURLPath    string `yaml:"otel_logs_url_path"`
+
+	// OtelTLS configures TLS towards the collector; nil verifies it against
+	// the system roots. OtelInsecure connects in plaintext instead, and
+	// cannot be combined with OtelTLS. OtelHeaders are sent with every
+	// export (e.g. Authorization).
+	OtelTLS      *TLSConfig        `yaml:"otel_tls"`
+	OtelInsecure bool              `yaml:"otel_insecure"`
+	OtelHeaders  map[string]string `yaml:"otel_headers"`
+
+	EnableTracing bool `yaml:"enable_tracing"`
+	EnableMetrics bool `yaml:"enable_metrics"`
+	EnableSentry  bool `yaml:"enable_sentry"`
+	EnableLoki    bool `yaml:"enable_loki"`
+
+	// EnableOTLPLogs exports logs through the OTel Logs SDK to the collector,
+	// correlated with the active span, alongside or instead of Loki.
+	EnableOTLPLogs bool `yaml:"enable_otlp_logs"`
+
+	SentryDSN string `yaml:"sentry_dsn"`
+	LokiURL   string `yaml:"loki_url"`
+
+	// Sentry client settings. SentryEnvironment and SentryRelease default to
+	// DeploymentEnvironment and ServiceVersion. SentrySampleRate applies to
+	// error events (0 means all); SentryTracesSampleRate defaults to 1 when
+	// nil. SentryBeforeSend can scrub or drop events before they are sent; it
+	// holds a func(*sentry.Event, *sentry.EventHint) *sentry.Event, typed any
+	// so the core does not import the Sentry SDK.
+	SentryEnvironment      string   `yaml:"sentry_environment"`
+	SentryRelease          string   `yaml:"sentry_release"`
+	SentrySampleRate       float64  `yaml:"sentry_sample_rate"`
+	SentryTracesSampleRate *float64 `yaml:"sentry_traces_sample_rate"`
+	SentryDebug            bool     `yaml:"sentry_debug"`
+	SentryBeforeSend       any      `yaml:"-"`
+
+	// CaptureEveryError sends every WithError of a request to the exporters.
+	// By default only the first one is captured; later ones become span
+	// events and Sentry breadcrumbs.
+	CaptureEveryError bool `yaml:"capture_every_error"`
+
+	// Captured errors are sent from a background worker holding up to
+	// SentryQueueSize events (default 256), behaving as SentryQueue says when
+	// full; the disk kind is not available. Errors grouped together by
+	// SentryFingerprintRules (or sharing type and message) are sent once per
+	// SentryDedupWindow; zero disables deduplication.
+	SentryQueueSize        int                     `yaml:"sentry_queue_size"`
+	SentryQueue            ExportQueue             `yaml:"sentry_queue"`
+	SentryDedupWindow      time.Duration           `yaml:"sentry_dedup_window"`
+	SentryFingerprintRules []SentryFingerprintRule `yaml:"sentry_fingerprint_rules"`
+
+	// Loki batching: entries are pushed when LokiBatchSize is reached or every
+	// LokiBatchInterval. Failed pushes are retried up to LokiMaxRetries times.
+	// LokiQueue picks the queue kind and what happens when it is full.
+	LokiBatchSize     int           `yaml:"loki_batch_size"`
+	LokiBatchInterval time.Duration `yaml:"loki_batch_interval"`
+	LokiQueueSize     int           `yaml:"loki_queue_size"`
+	LokiQueue         ExportQueue   `yaml:"loki_queue"`
+	LokiMaxRetries    int           `yaml:"loki_max_retries"`
+
+	// Loki authentication. LokiBearerToken takes precedence over basic auth;
+	// LokiTenantID is sent as X-Scope-OrgID for multi-tenant deployments.
+	LokiUsername    string            `yaml:"loki_username"`
+	LokiPassword    string            `yaml:"loki_password"`
+	LokiBearerToken string            `yaml:"loki_bearer_token"`
+	LokiTenantID    string            `yaml:"loki_tenant_id"`
+	LokiHeaders     map[string]string `yaml:"loki_headers"`
+
+	// LokiStaticLabels are added to every stream (e.g. env, region).
+	// LokiLabelFields lists the log fields promoted to stream labels; all
+	// other fields are written into the JSON log line.
+	LokiStaticLabels map[string]string `yaml:"loki_static_labels"`
+	LokiLabelFields  []string          `yaml:"loki_label_fields"`
+
+	// MetricsExporter is "otlp" (default) or "prometheus". The latter serves
+	// metrics for scraping through MetricsHandler and, when
+	// PrometheusListenAddr is set, on its own /metrics listener.
+	MetricsExporter      string `yaml:"metrics_exporter"`
+	PrometheusListenAddr string `yaml:"prometheus_listen_addr"`
+
+	// MetricExemplars is "trace_based" (default), "always_on" or
+	// "always_off". Prometheus exposes exemplars in the OpenMetrics format
+	// only, which MetricsHandler serves to scrapers that ask for it.
+	MetricExemplars string `yaml:"metric_exemplars"`
+
+	// MetricsPushGatewayURL pushes metrics to a Prometheus Pushgateway on
+	// PushMetrics and shutdown, for batch jobs that are never scraped.
+	MetricsPushGatewayURL string `yaml:"metrics_push_gateway_url"`
+
+	MaxSpansPerTrace int `yaml:"max_spans_per_trace"`
+
+	// ShortSpans drops or merges spans shorter than a threshold.
+	ShortSpans ShortSpans `yaml:"short_spans"`
+
+	// DevMode prints traces and metrics to stdout instead of exporting them
+	// over OTLP and logs through a colored console encoder, for running
+	// locally without a collector.
+	DevMode bool `yaml:"dev_mode"`
+
+	// SyncExport exports each span as soon as it ends instead of batching;
+	// meant for short-lived CLI tools, not for servers.
+	SyncExport bool `yaml:"sync_export"`
+
+	// StrictInit makes InitEOTEL fail when any enabled subsystem cannot
+	// start, including those that otherwise run degraded (Sentry), and
+	// turns on SetStrictInit.
+	StrictInit bool `yaml:"strict_init"`
+
+	// LegacyMetricNames additionally records the built-in log metrics under
+	// their old names (log_total, log_duration_ms).
+	LegacyMetricNames bool `yaml:"legacy_metric_names"`
+
+	// LegacyHTTPFieldNames keeps the pre-semconv request log fields (method,
+	// path, ip, ua, http.status) written by the HTTP middlewares and client.
+	LegacyHTTPFieldNames bool `yaml:"legacy_http_field_names"`
+
+	// EnableRuntimeMetrics collects Go runtime metrics (GC, goroutines, heap)
+	// through the OTel runtime instrumentation.
+	EnableRuntimeMetrics bool `yaml:"enable_runtime_metrics"`
+
+	// EnableSelfMetrics records spans per trace and attributes/events per
+	// span as histograms.
+	EnableSelfMetrics bool `yaml:"enable_self_metrics"`
+
+	// MaxLabelValues bounds the distinct values per label of eotel-recorded
+	// metrics; further values become "overflow". Zero uses the default of
+	// 500, a negative value disables the guard.
+	MaxLabelValues int `yaml:"max_label_values"`
+
+	// MaxValueBytes caps string field values, Loki lines and Sentry extras.
+	// Zero uses the 16 KiB default, a negative value disables truncation.
+	MaxValueBytes int `yaml:"max_value_bytes"`
+
+	// SpanLimits caps attribute values, attributes and events per span.
+	SpanLimits SpanLimits `yaml:"span_limits"`
+
+	// Sampler is one of always_on, always_off, traceidratio,
+	// parentbased_always_on (default), parentbased_always_off,
+	// parentbased_traceidratio, adaptive, parentbased_adaptive,
+	// jaeger_remote or parentbased_jaeger_remote. SamplerRatio applies to
+	// the ratio samplers and is the remote samplers' rate until the first
+	// strategy arrives.
+	Sampler      string  `yaml:"sampler"`
+	SamplerRatio float64 `yaml:"sampler_ratio"`
+
+	// SamplerTargetTPS is the traces per second per route that the adaptive
+	// and parentbased_adaptive samplers aim for (default 10).
+	SamplerTargetTPS float64 `yaml:"sampler_target_tps"`
+
+	// SamplerRemoteURL is the Jaeger remote sampling endpoint the remote
+	// samplers poll every SamplerRemoteInterval, e.g. the collector's
+	// jaegerremotesampling extension (default http://localhost:5778/sampling,
+	// every minute).
+	SamplerRemoteURL      string        `yaml:"sampler_remote_url"`
+	SamplerRemoteInterval time.Duration `yaml:"sampler_remote_interval"`
+
+	// SamplingPriority lets the server middlewares pick the sampling
+	// probability of a request's root span from its properties (tenant,
+	// plan, ...).
+	SamplingPriority func(r *http.Request) float64 `yaml:"-"`
+
+	// MinLevel is the initial minimum log level; change it at runtime with
+	// SetLevel or LevelHandler.
+	MinLevel string `yaml:"min_level"`
+
+	SpanLevelPolicy SpanLevelPolicy `yaml:"span_level_policy"`
+
+	// SpanAttributeNamespace prefixes custom fields on spans ("app" by
+	// default, "-" to disable) so they cannot collide with semconv keys.
+	// SpanAttributeMapping maps field names onto semconv attributes instead,
+	// e.g. {"user_id": "enduser.id"}.
+	SpanAttributeNamespace string            `yaml:"span_attribute_namespace"`
+	SpanAttributeMapping   map[string]string `yaml:"span_attribute_mapping"`
+
+	// KeySanitizer rewrites field keys outside [A-Za-z0-9_.-] so
+	// user-derived keys cannot break log or label ingestion.
+	KeySanitizer KeySanitizer `yaml:"key_sanitizer"`
+
+	// Redaction scrubs sensitive keys and value patterns before export.
+	Redaction Redaction `yaml:"redaction"`
+
+	// NativeSink additionally writes logs to the OS facility: "journald"
+	// (linux) or "eventlog" (windows).
+	NativeSink string `yaml:"native_sink"`
+
+	// ExporterTimeouts bounds each backend; LogExportBudget caps the time a
+	// single log call may spend in synchronous exporters.
+	ExporterTimeouts ExporterTimeouts `yaml:"exporter_timeouts"`
+	LogExportBudget  time.Duration    `yaml:"log_export_budget"`
+
+	// CircuitBreaker stops exports to the collector and Loki while they keep
+	// failing.
+	CircuitBreaker CircuitBreaker `yaml:"circuit_breaker"`
+
+	// DiskBuffer keeps Loki entries and spans on disk while their backend is
+	// down and replays them once it is back.
+	DiskBuffer DiskBuffer `yaml:"disk_buffer"`
+
+	// FlushOnError pushes Loki, span and Sentry buffers immediately after an
+	// error or fatal entry instead of waiting for the next batch.
+	FlushOnError bool `yaml:"flush_on_error"`
+
+	// CorrelateMetrics adds attributes of the request's server span to the
+	// metrics recorded through Count, Record, Counter, Histogram and Gauge,
+	// so they line up with the built-in ones. CorrelatedMetricAttributes
+	// picks the keys, DefaultCorrelatedMetricAttributes when empty.
+	CorrelateMetrics           bool     `yaml:"correlate_metrics"`
+	CorrelatedMetricAttributes []string `yaml:"correlated_metric_attributes"`
+
+	// PanicResponse writes the response of a request whose handler panicked,
+	// replacing the default 500 {"error":"internal server error"} body.
+	PanicResponse func(w http.ResponseWriter, r *http.Request, recovered any) `yaml:"-"`
+
+	// ProxyServerSpan makes the HTTP middlewares continue the server span of
+	// an instrumented ingress rather than start their own; see
+	// WithProxyServerSpan.
+	ProxyServerSpan bool `yaml:"proxy_server_span"`
+
+	// LastBreathFile receives fatal entries and recovered panics, appended
+	// and fsynced before any network export, so the last error survives a
+	// crash mid-flush. Empty disables it.
+	LastBreathFile string `yaml:"last_breath_file"`
+
+	// AuditSink receives every Audit event, alongside Loki. An error from it
+	// is returned by Audit unless Loki took the event.
+	AuditSink func(ctx context.Context, rec AuditRecord) error `yaml:"-"`
+
+	// FatalBehavior is what Fatal does once the entry is written and every
+	// pipeline flushed: "exit" (default) calls ExitFunc(1), "panic" panics,
+	// "log" returns to the caller. ExitFunc defaults to os.Exit.
+	FatalBehavior string    `yaml:"fatal_behavior"`
+	ExitFunc      func(int) `yaml:"-"`
+
+	// Exporters receive every log entry and captured error in addition to
+	// the built-in pipelines; see RegisterExporter for level filters.
+	Exporters []Exporter `yaml:"-"`
+
+	// LogHooks run in order on every entry before it is emitted, to enrich,
+	// rewrite or drop it (by returning nil).
+	LogHooks []func(*Record) *Record `yaml:"-"`
+
+	// SpanProcessors are registered on the tracer provider built by
+	// InitEOTEL, ahead of the exporter; OnSpanEnd is called for every ended
+	// span. Both are ignored when TracerProvider is injected.
+	SpanProcessors []sdktrace.SpanProcessor    `yaml:"-"`
+	OnSpanEnd      func(sdktrace.ReadOnlySpan) `yaml:"-"`
+
+	// Log configures the zap logger built by InitEOTEL when Logger is nil.
+	Log LoggerConfig `yaml:"log"`
+
+	// TraceAwareLogs holds debug and info entries of unsampled requests back
+	// from Loki, OTLP and registered exporters, exporting them only if the
+	// request fails (5xx or an error entry). Local zap output is unaffected.
+	TraceAwareLogs bool `yaml:"trace_aware_logs"`
+
+	// LogSampling drops a share of low-level entries and repeated messages
+	// before they reach any output.
+	LogSampling LogSampling `yaml:"log_sampling"`
+
+	// EnableUsageReporting counts the bytes sent to each backend, exported
+	// as eotel.export.bytes with UsageLabels (e.g. team) attached and
+	// available through Usage. UsageReportInterval, when set, also logs the
+	// daily totals periodically.
+	EnableUsageReporting bool              `yaml:"enable_usage_reporting"`
+	UsageLabels          map[string]string `yaml:"usage_labels"`
+	UsageReportInterval  time.Duration     `yaml:"usage_report_interval"`
+
+	// CaptureHeader enables capture mode: a request carrying this header
+	// (with the value CaptureToken, when set) has its logs, spans and metric
+	// records written to CaptureDir as <trace_id>.json, to be re-emitted later
+	// with LoadCapture and Capture.Replay.
+	CaptureHeader string `yaml:"capture_header"`
+	CaptureToken  string `yaml:"capture_token"`
+	CaptureDir    string `yaml:"capture_dir"`
+
+	// SignalToggles makes SIGUSR1 toggle debug logging and SIGUSR2 write a
+	// diagnostics dump (see WriteDiagnostics) to DiagnosticsDir, the temp
+	// dir by default. Unix only; SIGHUP is left to ReloadOnSignal.
+	SignalToggles  bool   `yaml:"signal_toggles"`
+	DiagnosticsDir string `yaml:"diagnostics_dir"`
+
+	// CollectorHealthURL enables polling of the collector's health_check
+	// extension every CollectorHealthInterval (default 30s), reported as
+	// eotel.collector.up; CollectorHealthFailures consecutive failures
+	// (default 3) log a warning.
+	CollectorHealthURL      string        `yaml:"collector_health_url"`
+	CollectorHealthInterval time.Duration `yaml:"collector_health_interval"`
+	CollectorHealthFailures int           `yaml:"collector_health_failures"`
+
+	// The gRPC collector connections are dialed in the background and
+	// reconnect on their own, so a collector that is down does not stop
+	// startup. StartupTimeout makes InitEOTEL wait that long for them to be
+	// ready; if they are not, it logs a warning and goes on (or fails under
+	// StrictInit). OnCollectorState is called on every connection state
+	// change. OTLPRetry tunes the exporters' retries of a refused batch.
+	StartupTimeout   time.Duration                    `yaml:"startup_timeout"`
+	OnCollectorState func(Signal, connectivity.State) `yaml:"-"`
+	OTLPRetry        OTLPRetry                        `yaml:"otlp_retry"`
+
+	// RouteSLAs sets latency objectives per route, keyed "GET /users/:id" or
+	// just "/users/:id" (ServeMux patterns for HTTPMiddleware). Slower
+	// requests get an sla.breached span event, a warning and a count in
+	// http_server_sla_breaches_total.
+	RouteSLAs map[string]time.Duration `yaml:"route_slas"`
+
+	// RequestIDHeader is read for the caller's request ID (one is generated
+	// otherwise) and echoed in the response; TraceIDHeader returns the trace
+	// ID. They default to X-Request-ID and X-Trace-ID; "-" disables either.
+	RequestIDHeader string `yaml:"request_id_header"`
+	TraceIDHeader   string `yaml:"trace_id_header"`
+
+	// IdempotencyHeader carries idempotency keys, Idempotency-Key by default
+	// ("-" disables it). A key repeated within IdempotencyWindow (default 10m)
+	// marks the request as a duplicate; see WithIdempotencyKey.
+	IdempotencyHeader string        `yaml:"idempotency_header"`
+	IdempotencyWindow time.Duration `yaml:"idempotency_window"`
+
+	// BodyCapture attaches request and response bodies to failed requests in
+	// both middlewares; WithBodyCapture overrides it for the router
+	// middleware.
+	BodyCapture BodyCapture `yaml:"body_capture"`
+
+	// SessionHashKey keys the HMAC behind session.id (see WithRouterSession). Use
+	// the same secret across services so their session IDs line up.
+	SessionHashKey string `yaml:"session_hash_key"`
+
+	// PromotedBaggage lists the baggage members added as log fields and as
+	// attributes on server, client, producer and consumer spans. Nil means
+	// DefaultPromotedBaggage; an empty list disables promotion.
+	PromotedBaggage []string `yaml:"promoted_baggage"`
+
+	// BaggageHeaders lists request headers (gRPC metadata keys, Kafka
+	// headers) such as X-Correlation-ID that are copied into baggage on
+	// ingress and sent again on every outgoing call.
+	BaggageHeaders []string `yaml:"baggage_headers"`
+
+	// TenantKey is the baggage member naming the tenant (default
+	// "tenant_id"). TenantRouter, or else TenantRoutes, sends a tenant's
+	// Loki lines and Sentry events to its own backends.
+	TenantKey    string       `yaml:"tenant_key"`
+	TenantRoutes TenantRoutes `yaml:"tenant_routes"`
+	TenantRouter TenantRouter `yaml:"-"`
+
+	// Pre-built providers and logger for apps that manage their own OTel SDK
+	// lifecycle. When set, eotel uses them as-is and never shuts them down.
+	TracerProvider trace.TracerProvider `yaml:"-"`
+	MeterProvider  metric.MeterProvider `yaml:"-"`
+	Logger         *zap.Logger          `yaml:"-"`
+}
+
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// globalCfg holds the Config in effect. InitEOTEL and Reload replace it
+// whole; read it through currentConfig, once per call.
+var globalCfg atomic.Pointer[Config]
+
+var zeroConfig Config
+
+func currentConfig() *Config {
+	if cfg := globalCfg.Load(); cfg != nil {
+		return cfg
+	}
+	return &zeroConfig
+}
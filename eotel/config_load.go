@@ -0,0 +1,338 @@
+package eotel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultOtelCollector = "localhost:4317"
+
+// LoadConfig builds a Config from defaults, the YAML/JSON file named by
+// EOTEL_CONFIG_FILE (if set) and EOTEL_* environment variables, in that order
+// of precedence, and validates the result.
+func LoadConfig() (Config, error) {
+	return LoadConfigFile(os.Getenv("EOTEL_CONFIG_FILE"))
+}
+
+// LoadConfigFile is LoadConfig with an explicit file path; an empty path skips
+// the file. JSON files are accepted since YAML is a superset of JSON.
+func LoadConfigFile(path string) (Config, error) {
+	return loadConfig(path, Config{})
+}
+
+// loadConfig layers the file and environment on top of base, which provides
+// caller-specific defaults.
+func loadConfig(path string, base Config) (Config, error) {
+	cfg := base
+	if cfg.OtelCollector == "" {
+		cfg.OtelCollector = defaultOtelCollector
+	}
+	if cfg.OtelProtocol == "" {
+		cfg.OtelProtocol = "grpc"
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.JobName == "" {
+		cfg.JobName = cfg.ServiceName
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	if c.ServiceName == "" {
+		errs = append(errs, errors.New("service name is required"))
+	}
+	if (c.EnableTracing || c.EnableMetrics) && c.OtelCollector == "" {
+		errs = append(errs, errors.New("otel collector endpoint is required when tracing or metrics are enabled"))
+	}
+	if c.OtelInsecure && c.OtelTLS != nil {
+		errs = append(errs, errors.New("otel insecure and otel tls are mutually exclusive"))
+	}
+	if c.OtelProtocol != "" && c.OtelProtocol != "grpc" && c.OtelProtocol != "http" {
+		errs = append(errs, fmt.Errorf("unsupported otel protocol %q", c.OtelProtocol))
+	}
+	if c.MetricsExporter != "" && c.MetricsExporter != metricsExporterOTLP && c.MetricsExporter != metricsExporterPrometheus {
+		errs = append(errs, fmt.Errorf("unsupported metrics exporter %q", c.MetricsExporter))
+	}
+	if _, err := exemplarFilter(c.MetricExemplars); err != nil {
+		errs = append(errs, err)
+	}
+	if c.EnableLoki && c.LokiURL == "" {
+		errs = append(errs, errors.New("loki url is required when loki is enabled"))
+	}
+	if c.EnableSentry && c.SentryDSN == "" {
+		errs = append(errs, errors.New("sentry dsn is required when sentry is enabled"))
+	}
+	if c.SentrySampleRate < 0 || c.SentrySampleRate > 1 {
+		errs = append(errs, fmt.Errorf("sentry sample rate %v out of [0, 1]", c.SentrySampleRate))
+	}
+	if r := c.SentryTracesSampleRate; r != nil && (*r < 0 || *r > 1) {
+		errs = append(errs, fmt.Errorf("sentry traces sample rate %v out of [0, 1]", *r))
+	}
+	if err := c.LokiQueue.validate("loki", true, c.DiskBuffer); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.SentryQueue.validate("sentry", false, c.DiskBuffer); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.ShortSpans.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.SpanLimits.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if !validFatalBehavior(c.FatalBehavior) {
+		errs = append(errs, fmt.Errorf("unsupported fatal behavior %q", c.FatalBehavior))
+	}
+	if _, err := newRedactor(c.Redaction); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := compileFingerprintRules(c.SentryFingerprintRules); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := newSampler(c); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func applyEnv(cfg *Config) error {
+	str := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok {
+			*dst = v
+		}
+	}
+	var errs []error
+	boolean := func(key string, dst *bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = b
+		}
+	}
+	integer := func(key string, dst *int) {
+		if v, ok := os.LookupEnv(key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = n
+		}
+	}
+	float := func(key string, dst *float64) {
+		if v, ok := os.LookupEnv(key); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = f
+		}
+	}
+	duration := func(key string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(key); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = d
+		}
+	}
+
+	str("EOTEL_SERVICE_NAME", &cfg.ServiceName)
+	str("EOTEL_JOB_NAME", &cfg.JobName)
+	str("EOTEL_SERVICE_VERSION", &cfg.ServiceVersion)
+	str("EOTEL_DEPLOYMENT_ENVIRONMENT", &cfg.DeploymentEnvironment)
+	str("EOTEL_OTLP_ENDPOINT", &cfg.OtelCollector)
+	str("EOTEL_OTLP_PROTOCOL", &cfg.OtelProtocol)
+	boolean("EOTEL_OTLP_INSECURE", &cfg.OtelInsecure)
+	str("EOTEL_OTLP_TRACES_PATH", &cfg.OtelTracesURLPath)
+	str("EOTEL_OTLP_METRICS_PATH", &cfg.OtelMetricsURLPath)
+	str("EOTEL_OTLP_LOGS_PATH", &cfg.OtelLogsURLPath)
+	str("EOTEL_METRICS_EXPORTER", &cfg.MetricsExporter)
+	str("EOTEL_METRIC_EXEMPLARS", &cfg.MetricExemplars)
+	boolean("EOTEL_STRICT_INIT", &cfg.StrictInit)
+	str("EOTEL_SPAN_ATTRIBUTE_NAMESPACE", &cfg.SpanAttributeNamespace)
+	str("EOTEL_MIN_LEVEL", &cfg.MinLevel)
+	boolean("EOTEL_TRACE_AWARE_LOGS", &cfg.TraceAwareLogs)
+	boolean("EOTEL_SIGNAL_TOGGLES", &cfg.SignalToggles)
+	str("EOTEL_DIAGNOSTICS_DIR", &cfg.DiagnosticsDir)
+	str("EOTEL_COLLECTOR_HEALTH_URL", &cfg.CollectorHealthURL)
+	duration("EOTEL_COLLECTOR_HEALTH_INTERVAL", &cfg.CollectorHealthInterval)
+	integer("EOTEL_COLLECTOR_HEALTH_FAILURES", &cfg.CollectorHealthFailures)
+	duration("EOTEL_STARTUP_TIMEOUT", &cfg.StartupTimeout)
+	boolean("EOTEL_OTLP_RETRY_DISABLED", &cfg.OTLPRetry.Disabled)
+	duration("EOTEL_OTLP_RETRY_INITIAL_INTERVAL", &cfg.OTLPRetry.InitialInterval)
+	duration("EOTEL_OTLP_RETRY_MAX_INTERVAL", &cfg.OTLPRetry.MaxInterval)
+	duration("EOTEL_OTLP_RETRY_MAX_ELAPSED_TIME", &cfg.OTLPRetry.MaxElapsedTime)
+	str("EOTEL_REQUEST_ID_HEADER", &cfg.RequestIDHeader)
+	str("EOTEL_TRACE_ID_HEADER", &cfg.TraceIDHeader)
+	str("EOTEL_IDEMPOTENCY_HEADER", &cfg.IdempotencyHeader)
+	str("EOTEL_TENANT_KEY", &cfg.TenantKey)
+	duration("EOTEL_IDEMPOTENCY_WINDOW", &cfg.IdempotencyWindow)
+	integer("EOTEL_BODY_CAPTURE_MAX_BYTES", &cfg.BodyCapture.MaxBytes)
+	str("EOTEL_BODY_CAPTURE_HEADER", &cfg.BodyCapture.Header)
+	boolean("EOTEL_BODY_CAPTURE_ALWAYS", &cfg.BodyCapture.Always)
+	str("EOTEL_SESSION_HASH_KEY", &cfg.SessionHashKey)
+	str("EOTEL_CAPTURE_HEADER", &cfg.CaptureHeader)
+	str("EOTEL_CAPTURE_TOKEN", &cfg.CaptureToken)
+	str("EOTEL_CAPTURE_DIR", &cfg.CaptureDir)
+	str("EOTEL_LOG_ENCODING", &cfg.Log.Encoding)
+	str("EOTEL_PROMETHEUS_LISTEN_ADDR", &cfg.PrometheusListenAddr)
+	if v, ok := os.LookupEnv("EOTEL_OTLP_HEADERS"); ok {
+		cfg.OtelHeaders = parseKeyValues(v)
+	}
+
+	boolean("EOTEL_ENABLE_TRACING", &cfg.EnableTracing)
+	boolean("EOTEL_ENABLE_METRICS", &cfg.EnableMetrics)
+	boolean("EOTEL_ENABLE_SENTRY", &cfg.EnableSentry)
+	boolean("EOTEL_ENABLE_LOKI", &cfg.EnableLoki)
+	boolean("EOTEL_ENABLE_OTLP_LOGS", &cfg.EnableOTLPLogs)
+	boolean("EOTEL_ENABLE_SELF_METRICS", &cfg.EnableSelfMetrics)
+	boolean("EOTEL_ENABLE_RUNTIME_METRICS", &cfg.EnableRuntimeMetrics)
+	boolean("EOTEL_DEV_MODE", &cfg.DevMode)
+	boolean("EOTEL_ENABLE_USAGE_REPORTING", &cfg.EnableUsageReporting)
+	duration("EOTEL_USAGE_REPORT_INTERVAL", &cfg.UsageReportInterval)
+	if v, ok := os.LookupEnv("EOTEL_USAGE_LABELS"); ok {
+		cfg.UsageLabels = parseKeyValues(v)
+	}
+	boolean("EOTEL_LEGACY_METRIC_NAMES", &cfg.LegacyMetricNames)
+	boolean("EOTEL_LEGACY_HTTP_FIELD_NAMES", &cfg.LegacyHTTPFieldNames)
+	boolean("EOTEL_FLUSH_ON_ERROR", &cfg.FlushOnError)
+	integer("EOTEL_CIRCUIT_BREAKER_FAILURES", &cfg.CircuitBreaker.Failures)
+	duration("EOTEL_CIRCUIT_BREAKER_OPEN_FOR", &cfg.CircuitBreaker.OpenFor)
+	str("EOTEL_DISK_BUFFER_DIR", &cfg.DiskBuffer.Dir)
+	integer("EOTEL_DISK_BUFFER_MAX_BYTES", &cfg.DiskBuffer.MaxBytes)
+	duration("EOTEL_DISK_BUFFER_MAX_AGE", &cfg.DiskBuffer.MaxAge)
+	str("EOTEL_FATAL_BEHAVIOR", &cfg.FatalBehavior)
+	str("EOTEL_LAST_BREATH_FILE", &cfg.LastBreathFile)
+	boolean("EOTEL_PROXY_SERVER_SPAN", &cfg.ProxyServerSpan)
+	boolean("EOTEL_CORRELATE_METRICS", &cfg.CorrelateMetrics)
+	if v, ok := os.LookupEnv("EOTEL_CORRELATED_METRIC_ATTRIBUTES"); ok {
+		cfg.CorrelatedMetricAttributes = strings.Split(v, ",")
+	}
+
+	str("EOTEL_NATIVE_SINK", &cfg.NativeSink)
+	str("EOTEL_SENTRY_DSN", &cfg.SentryDSN)
+	str("EOTEL_SENTRY_ENVIRONMENT", &cfg.SentryEnvironment)
+	str("EOTEL_SENTRY_RELEASE", &cfg.SentryRelease)
+	float("EOTEL_SENTRY_SAMPLE_RATE", &cfg.SentrySampleRate)
+	if _, ok := os.LookupEnv("EOTEL_SENTRY_TRACES_SAMPLE_RATE"); ok {
+		var rate float64
+		float("EOTEL_SENTRY_TRACES_SAMPLE_RATE", &rate)
+		cfg.SentryTracesSampleRate = &rate
+	}
+	boolean("EOTEL_SENTRY_DEBUG", &cfg.SentryDebug)
+	boolean("EOTEL_CAPTURE_EVERY_ERROR", &cfg.CaptureEveryError)
+	integer("EOTEL_SENTRY_QUEUE_SIZE", &cfg.SentryQueueSize)
+	str("EOTEL_SENTRY_QUEUE_KIND", &cfg.SentryQueue.Kind)
+	str("EOTEL_SENTRY_QUEUE_BACKPRESSURE", &cfg.SentryQueue.Backpressure)
+	duration("EOTEL_SENTRY_DEDUP_WINDOW", &cfg.SentryDedupWindow)
+	str("EOTEL_LOKI_URL", &cfg.LokiURL)
+	integer("EOTEL_LOKI_BATCH_SIZE", &cfg.LokiBatchSize)
+	duration("EOTEL_LOKI_BATCH_INTERVAL", &cfg.LokiBatchInterval)
+	integer("EOTEL_LOKI_QUEUE_SIZE", &cfg.LokiQueueSize)
+	str("EOTEL_LOKI_QUEUE_KIND", &cfg.LokiQueue.Kind)
+	str("EOTEL_LOKI_QUEUE_BACKPRESSURE", &cfg.LokiQueue.Backpressure)
+	duration("EOTEL_LOKI_QUEUE_BLOCK_TIMEOUT", &cfg.LokiQueue.BlockTimeout)
+	integer("EOTEL_LOKI_MAX_RETRIES", &cfg.LokiMaxRetries)
+	str("EOTEL_LOKI_USERNAME", &cfg.LokiUsername)
+	str("EOTEL_LOKI_PASSWORD", &cfg.LokiPassword)
+	str("EOTEL_LOKI_BEARER_TOKEN", &cfg.LokiBearerToken)
+	str("EOTEL_LOKI_TENANT_ID", &cfg.LokiTenantID)
+	if v, ok := os.LookupEnv("EOTEL_LOKI_HEADERS"); ok {
+		cfg.LokiHeaders = parseKeyValues(v)
+	}
+	if v, ok := os.LookupEnv("EOTEL_STATIC_FIELDS"); ok {
+		cfg.StaticFields = map[string]any{}
+		for k, val := range parseKeyValues(v) {
+			cfg.StaticFields[k] = val
+		}
+	}
+	if v, ok := os.LookupEnv("EOTEL_LOKI_STATIC_LABELS"); ok {
+		cfg.LokiStaticLabels = parseKeyValues(v)
+	}
+	if v, ok := os.LookupEnv("EOTEL_ROUTE_SLAS"); ok {
+		cfg.RouteSLAs = map[string]time.Duration{}
+		for route, d := range parseKeyValues(v) {
+			sla, err := time.ParseDuration(d)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("EOTEL_ROUTE_SLAS %s: %w", route, err))
+				continue
+			}
+			cfg.RouteSLAs[route] = sla
+		}
+	}
+	if v, ok := os.LookupEnv("EOTEL_PROMOTED_BAGGAGE"); ok {
+		cfg.PromotedBaggage = []string{}
+		if v != "" {
+			cfg.PromotedBaggage = strings.Split(v, ",")
+		}
+	}
+	if v, ok := os.LookupEnv("EOTEL_BAGGAGE_HEADERS"); ok {
+		cfg.BaggageHeaders = strings.Split(v, ",")
+	}
+	if v, ok := os.LookupEnv("EOTEL_LOKI_LABEL_FIELDS"); ok {
+		cfg.LokiLabelFields = strings.Split(v, ",")
+	}
+
+	str("EOTEL_METRICS_PUSHGATEWAY_URL", &cfg.MetricsPushGatewayURL)
+	integer("EOTEL_MAX_SPANS_PER_TRACE", &cfg.MaxSpansPerTrace)
+	duration("EOTEL_SHORT_SPAN_THRESHOLD", &cfg.ShortSpans.Threshold)
+	str("EOTEL_SHORT_SPAN_ACTION", &cfg.ShortSpans.Action)
+	integer("EOTEL_MAX_VALUE_BYTES", &cfg.MaxValueBytes)
+	integer("EOTEL_SPAN_ATTRIBUTE_VALUE_LENGTH", &cfg.SpanLimits.AttributeValueLength)
+	integer("EOTEL_SPAN_ATTRIBUTE_COUNT", &cfg.SpanLimits.AttributeCount)
+	integer("EOTEL_SPAN_EVENT_COUNT", &cfg.SpanLimits.EventCount)
+	integer("EOTEL_SPAN_ATTRIBUTE_PER_EVENT_COUNT", &cfg.SpanLimits.AttributePerEventCount)
+	integer("EOTEL_MAX_LABEL_VALUES", &cfg.MaxLabelValues)
+	str("EOTEL_SAMPLER", &cfg.Sampler)
+	float("EOTEL_SAMPLER_RATIO", &cfg.SamplerRatio)
+	float("EOTEL_SAMPLER_TARGET_TPS", &cfg.SamplerTargetTPS)
+	str("EOTEL_SAMPLER_REMOTE_URL", &cfg.SamplerRemoteURL)
+	duration("EOTEL_SAMPLER_REMOTE_INTERVAL", &cfg.SamplerRemoteInterval)
+
+	return errors.Join(errs...)
+}
+
+// parseKeyValues parses "k1=v1,k2=v2" as used by OTEL_EXPORTER_OTLP_HEADERS.
+func parseKeyValues(s string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			continue
+		}
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// sensitiveConfigKeys mark config keys whose values are never logged.
+var sensitiveConfigKeys = []string{"password", "token", "secret", "dsn", "key", "headers", "tenant_routes"}
+
+// ConfigChange is one changed key between two configs, named by its YAML
+// path (e.g. "log.encoding"). Sensitive values are redacted.
+type ConfigChange struct {
+	Key string
+	Old any
+	New any
+}
+
+// DiffConfig lists the keys that differ between old and new. Fields without
+// a YAML form (hooks, providers, loggers) are not compared.
+func DiffConfig(old, new Config) []ConfigChange {
+	var changes []ConfigChange
+	diffStruct("", reflect.ValueOf(old), reflect.ValueOf(new), &changes)
+	return changes
+}
+
+func diffStruct(prefix string, a, b reflect.Value, out *[]ConfigChange) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		key := prefix + name
+		av, bv := a.Field(i), b.Field(i)
+		if f.Type.Kind() == reflect.Struct {
+			diffStruct(key+".", av, bv, out)
+			continue
+		}
+		if reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			continue
+		}
+		c := ConfigChange{Key: key, Old: configValue(av), New: configValue(bv)}
+		if sensitiveConfigKey(key) {
+			c.Old, c.New = defaultRedactionReplacement, defaultRedactionReplacement
+		}
+		*out = append(*out, c)
+	}
+}
+
+func configValue(v reflect.Value) any {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func sensitiveConfigKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveConfigKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// logConfigChanges logs the diff between the previous and the new config
+// and counts each changed key in eotel.config.changes.
+func logConfigChanges(old, new Config) {
+	changes := DiffConfig(old, new)
+	if len(changes) == 0 {
+		return
+	}
+	keys := make([]string, len(changes))
+	fields := make([]zap.Field, 0, len(changes)+1)
+	for i, c := range changes {
+		keys[i] = c.Key
+		fields = append(fields, zap.String("config."+c.Key, fmt.Sprintf("%v -> %v", c.Old, c.New)))
+	}
+	getLogger().Info("eotel: configuration changed", append(fields, zap.Strings("changed_keys", keys))...)
+
+	counter, err := getMeter().Int64Counter("eotel.config.changes",
+		metric.WithUnit("{change}"),
+		metric.WithDescription("Configuration keys changed on reload."))
+	if err != nil {
+		return
+	}
+	for _, k := range keys {
+		counter.Add(context.Background(), 1, metric.WithAttributes(attribute.String("config.key", k)))
+	}
+}
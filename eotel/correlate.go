@@ -0,0 +1,75 @@
+package eotel
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultCorrelatedMetricAttributes are the request span attributes copied
+// onto custom metrics with Config.CorrelateMetrics, matching the labels of
+// the built-in HTTP and gRPC server metrics. http.status_class is derived
+// from http.response.status_code ("2xx", "5xx", ...).
+var DefaultCorrelatedMetricAttributes = []string{
+	"http.route",
+	"http.request.method",
+	"http.status_class",
+	"rpc.method",
+}
+
+const statusClassKey = "http.status_class"
+
+// correlatedAttrs appends to attrs the low-cardinality attributes of the
+// request's server span listed in Config.CorrelatedMetricAttributes. Keys
+// already present in attrs are left alone.
+func (l *Eotel) correlatedAttrs(attrs []attribute.KeyValue) []attribute.KeyValue {
+	cfg := currentConfig()
+	if !cfg.CorrelateMetrics {
+		return attrs
+	}
+	var span trace.Span
+	if rs := requestScopeFrom(l.ctx); rs != nil {
+		span = rs.span
+	} else {
+		span = l.activeSpan()
+	}
+	ro, ok := span.(sdktrace.ReadOnlySpan)
+	if !ok {
+		return attrs
+	}
+
+	keys := cfg.CorrelatedMetricAttributes
+	if len(keys) == 0 {
+		keys = DefaultCorrelatedMetricAttributes
+	}
+	have := make(map[attribute.Key]struct{}, len(attrs))
+	for _, kv := range attrs {
+		have[kv.Key] = struct{}{}
+	}
+	want := make(map[attribute.Key]struct{}, len(keys))
+	for _, k := range keys {
+		if _, ok := have[attribute.Key(k)]; !ok {
+			want[attribute.Key(k)] = struct{}{}
+		}
+	}
+	if len(want) == 0 {
+		return attrs
+	}
+
+	out := attrs[:len(attrs):len(attrs)]
+	for _, kv := range ro.Attributes() {
+		if kv.Key == "http.response.status_code" {
+			if _, ok := want[statusClassKey]; ok {
+				out = append(out, attribute.String(statusClassKey, strconv.Itoa(int(kv.Value.AsInt64()/100))+"xx"))
+				delete(want, statusClassKey)
+			}
+		}
+		if _, ok := want[kv.Key]; ok {
+			out = append(out, kv)
+			delete(want, kv.Key)
+		}
+	}
+	return out
+}
@@ -0,0 +1,198 @@
+package eotel
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Prometheus names of the built-in metrics as exposed by the "prometheus"
+// metrics exporter, which appends unit and _total suffixes.
+const (
+	promHTTPRequests     = "http_server_requests_total"
+	promHTTPDuration     = "http_server_duration_ms_milliseconds"
+	promHTTPActive       = "http_server_active_requests"
+	promHTTPResponseSize = "http_server_response_size_bytes"
+	promClientRequests   = "http_client_requests_total"
+	promClientDuration   = "http_client_duration_ms_milliseconds"
+	promPanics           = "panic_total"
+	promLogRecords       = "eotel_log_records__record__total"
+	promLogDropped       = "eotel_log_dropped__record__total"
+	promLokiSent         = "loki_entries_sent_total"
+	promLokiDropped      = "loki_entries_dropped_total"
+	promSentryDropped    = "sentry_events_dropped_total"
+	promQueueDropped     = "eotel_queue_dropped__entry__total"
+	promExportRejected   = "eotel_export_rejected__batch__total"
+	promCollectorUp      = "eotel_collector_up"
+	promSpanTruncated    = "eotel_span_truncated__item__total"
+	promSpanDenoised     = "eotel_span_denoised__span__total"
+	promSLOBurnRate      = "eotel_slo_burn_rate"
+)
+
+type dashboardPanel struct {
+	title  string
+	unit   string
+	exprs  []string
+	legend string
+}
+
+// dashboardRows groups the panels of the generated dashboard. $sel is
+// replaced by the job selector.
+var dashboardRows = []struct {
+	title  string
+	panels []dashboardPanel
+}{
+	{"HTTP server", []dashboardPanel{
+		{"Requests by route", "reqps", []string{`sum by (http_route) (rate(` + promHTTPRequests + `{$sel}[$__rate_interval]))`}, "{{http_route}}"},
+		{"5xx ratio", "percentunit", []string{`sum(rate(` + promHTTPRequests + `{$sel,http_response_status_code=~"5.."}[$__rate_interval])) / sum(rate(` + promHTTPRequests + `{$sel}[$__rate_interval]))`}, "5xx"},
+		{"p95 latency by route", "ms", []string{`histogram_quantile(0.95, sum by (le, http_route) (rate(` + promHTTPDuration + `_bucket{$sel}[$__rate_interval])))`}, "{{http_route}}"},
+		{"Active requests", "short", []string{`sum by (http_request_method) (` + promHTTPActive + `{$sel})`}, "{{http_request_method}}"},
+		{"Response size p95", "bytes", []string{`histogram_quantile(0.95, sum by (le) (rate(` + promHTTPResponseSize + `_bucket{$sel}[$__rate_interval])))`}, "p95"},
+		{"Panics by route", "short", []string{`sum by (http_route) (increase(` + promPanics + `{$sel}[$__rate_interval]))`}, "{{http_route}}"},
+	}},
+	{"HTTP client", []dashboardPanel{
+		{"Requests by host", "reqps", []string{`sum by (server_address, http_response_status_code) (rate(` + promClientRequests + `{$sel}[$__rate_interval]))`}, "{{server_address}} {{http_response_status_code}}"},
+		{"p95 latency by host", "ms", []string{`histogram_quantile(0.95, sum by (le, server_address) (rate(` + promClientDuration + `_bucket{$sel}[$__rate_interval])))`}, "{{server_address}}"},
+	}},
+	{"Logs", []dashboardPanel{
+		{"Log records by level", "short", []string{`sum by (level) (rate(` + promLogRecords + `{$sel}[$__rate_interval]))`}, "{{level}}"},
+		{"Log records dropped", "short", []string{`sum by (reason) (rate(` + promLogDropped + `{$sel}[$__rate_interval]))`}, "{{reason}}"},
+	}},
+	{"Pipeline", []dashboardPanel{
+		{"Loki entries", "short", []string{
+			`sum(rate(` + promLokiSent + `{$sel}[$__rate_interval]))`,
+			`sum by (reason) (rate(` + promLokiDropped + `{$sel}[$__rate_interval]))`,
+		}, ""},
+		{"Sentry events dropped", "short", []string{`sum by (reason) (rate(` + promSentryDropped + `{$sel}[$__rate_interval]))`}, "{{reason}}"},
+		{"Export queue drops", "short", []string{`sum by (queue, reason) (rate(` + promQueueDropped + `{$sel}[$__rate_interval]))`}, "{{queue}} {{reason}}"},
+		{"Batches rejected by open circuit", "short", []string{`sum by (backend) (rate(` + promExportRejected + `{$sel}[$__rate_interval]))`}, "{{backend}}"},
+		{"Collector up", "short", []string{`min(` + promCollectorUp + `{$sel})`}, "up"},
+	}},
+	{"Traces", []dashboardPanel{
+		{"Span items truncated", "short", []string{`sum by (kind) (rate(` + promSpanTruncated + `{$sel}[$__rate_interval]))`}, "{{kind}}"},
+		{"Short spans removed", "short", []string{`sum by (action) (rate(` + promSpanDenoised + `{$sel}[$__rate_interval]))`}, "{{action}}"},
+	}},
+	{"SLO", []dashboardPanel{
+		{"Error budget burn rate", "short", []string{`max by (slo_name) (` + promSLOBurnRate + `{$sel})`}, "{{slo_name}}"},
+	}},
+}
+
+type alertRule struct {
+	name    string
+	expr    string
+	wait    string
+	sev     string
+	summary string
+}
+
+var alertRules = []alertRule{
+	{"EotelHighErrorRate",
+		`sum by (job) (rate(` + promHTTPRequests + `{http_response_status_code=~"5.."}[5m])) / sum by (job) (rate(` + promHTTPRequests + `[5m])) > 0.05`,
+		"5m", "critical", "More than 5% of {{ $labels.job }} requests fail with 5xx."},
+	{"EotelHighLatency",
+		`histogram_quantile(0.95, sum by (job, le) (rate(` + promHTTPDuration + `_bucket[5m]))) > 1000`,
+		"10m", "warning", "p95 latency of {{ $labels.job }} is above 1s."},
+	{"EotelPanics",
+		`sum by (job) (increase(` + promPanics + `[5m])) > 0`,
+		"0m", "warning", "{{ $labels.job }} recovered from a panic."},
+	{"EotelCollectorDown",
+		`min by (job) (` + promCollectorUp + `) == 0`,
+		"5m", "critical", "{{ $labels.job }} cannot reach the OpenTelemetry collector."},
+	{"EotelTelemetryDropped",
+		`sum by (job) (rate({__name__=~"` + promLokiDropped + `|` + promQueueDropped + `|` + promSentryDropped + `"}[10m])) > 0`,
+		"10m", "warning", "{{ $labels.job }} is dropping telemetry."},
+	{"EotelSLOBurnRate",
+		`max by (job, slo_name) (` + promSLOBurnRate + `) > 14.4`,
+		"5m", "critical", "SLO {{ $labels.slo_name }} of {{ $labels.job }} is burning its error budget too fast."},
+}
+
+// ExportDashboards writes a Grafana dashboard and Prometheus alert rules for
+// the metrics eotel records, with the names and labels the "prometheus"
+// metrics exporter exposes, as one JSON object:
+//
+//	{"dashboard": <Grafana dashboard model>, "alert_rules": <rule file>}
+//
+// "dashboard" can be imported as is; "alert_rules" is a valid Prometheus
+// rule file, since JSON is YAML. The dashboard selects services by the job
+// label.
+func ExportDashboards(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(map[string]any{
+		"dashboard":   grafanaDashboard(),
+		"alert_rules": prometheusRules(),
+	})
+}
+
+func grafanaDashboard() map[string]any {
+	const width, height = 8, 8
+	ds := map[string]any{"type": "prometheus", "uid": "${datasource}"}
+	var panels []map[string]any
+	id, y := 1, 0
+	for _, row := range dashboardRows {
+		panels = append(panels, map[string]any{
+			"id": id, "type": "row", "title": row.title, "collapsed": false,
+			"gridPos": map[string]int{"x": 0, "y": y, "w": 24, "h": 1},
+		})
+		id++
+		y++
+		for i, p := range row.panels {
+			var targets []map[string]any
+			for j, expr := range p.exprs {
+				t := map[string]any{
+					"refId":      string(rune('A' + j)),
+					"datasource": ds,
+					"expr":       strings.ReplaceAll(expr, "$sel", `job=~"$job"`),
+				}
+				if p.legend != "" {
+					t["legendFormat"] = p.legend
+				}
+				if strings.Contains(expr, "_bucket") {
+					// Exemplars link latency points to traces.
+					t["exemplar"] = true
+				}
+				targets = append(targets, t)
+			}
+			panels = append(panels, map[string]any{
+				"id": id, "type": "timeseries", "title": p.title, "datasource": ds,
+				"gridPos":     map[string]int{"x": (i % 3) * width, "y": y + (i/3)*height, "w": width, "h": height},
+				"fieldConfig": map[string]any{"defaults": map[string]any{"unit": p.unit}, "overrides": []any{}},
+				"targets":     targets,
+			})
+			id++
+		}
+		y += (len(row.panels) + 2) / 3 * height
+	}
+	return map[string]any{
+		"uid":           "eotel-overview",
+		"title":         "eotel overview",
+		"tags":          []string{"eotel"},
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"refresh":       "30s",
+		"panels":        panels,
+		"templating": map[string]any{"list": []map[string]any{
+			{"name": "datasource", "type": "datasource", "query": "prometheus", "label": "Data source"},
+			{
+				"name": "job", "type": "query", "label": "Service", "datasource": ds,
+				"query": "label_values(" + promHTTPRequests + ", job)", "refresh": 2,
+				"multi": true, "includeAll": true, "allValue": ".*",
+			},
+		}},
+	}
+}
+
+func prometheusRules() map[string]any {
+	rules := make([]map[string]any, 0, len(alertRules))
+	for _, r := range alertRules {
+		rules = append(rules, map[string]any{
+			"alert":       r.name,
+			"expr":        r.expr,
+			"for":         r.wait,
+			"labels":      map[string]string{"severity": r.sev},
+			"annotations": map[string]string{"summary": r.summary},
+		})
+	}
+	return map[string]any{"groups": []map[string]any{{"name": "eotel", "rules": rules}}}
+}
@@ -0,0 +1,38 @@
+package eotel
+
+import (
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// devConfig adjusts cfg for Config.DevMode: traces and metrics are printed
+// to stdout instead of being sent to a collector, spans are exported as
+// they end, and logs use a colored console encoder.
+func devConfig(cfg Config) (Config, error) {
+	cfg.EnableTracing = true
+	cfg.EnableMetrics = true
+	cfg.SyncExport = true
+	cfg.MetricsExporter = metricsExporterOTLP
+	if cfg.Logger == nil {
+		zc := zap.NewDevelopmentConfig()
+		zc.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		logger, err := zc.Build()
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Logger = logger
+	}
+	return cfg, nil
+}
+
+func newDevTraceExporter() (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithPrettyPrint())
+}
+
+func newDevMetricExporter() (sdkmetric.Exporter, error) {
+	return stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+}
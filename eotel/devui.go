@@ -0,0 +1,235 @@
+package eotel
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// devUILimit is how many spans and log entries the dev UI keeps.
+const devUILimit = 2000
+
+type devSpan struct {
+	TraceID string
+	CapturedSpan
+}
+
+type devLog struct {
+	TraceID string
+	CapturedLog
+}
+
+// devStore keeps the most recent spans and logs in memory for DevUIHandler.
+var devStore struct {
+	mu    sync.Mutex
+	spans []devSpan
+	logs  []devLog
+}
+
+func devRecordLog(id trace.TraceID, level, msg string, fields []zap.Field) {
+	l := devLog{CapturedLog: capturedLog(level, msg, fields)}
+	if id.IsValid() {
+		l.TraceID = id.String()
+	}
+	devStore.mu.Lock()
+	devStore.logs = appendBounded(devStore.logs, l)
+	devStore.mu.Unlock()
+}
+
+func appendBounded[T any](s []T, v T) []T {
+	if len(s) >= devUILimit {
+		s = append(s[:0], s[len(s)-devUILimit+1:]...)
+	}
+	return append(s, v)
+}
+
+// devUIProcessor feeds ended spans to the dev UI.
+type devUIProcessor struct{}
+
+func (devUIProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (devUIProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	ds := devSpan{TraceID: s.SpanContext().TraceID().String(), CapturedSpan: capturedSpan(s)}
+	devStore.mu.Lock()
+	devStore.spans = appendBounded(devStore.spans, ds)
+	devStore.mu.Unlock()
+}
+
+func (devUIProcessor) Shutdown(context.Context) error { return nil }
+
+func (devUIProcessor) ForceFlush(context.Context) error { return nil }
+
+type devTrace struct {
+	ID       string
+	Root     string
+	Start    time.Time
+	Duration time.Duration
+	Spans    int
+	Error    bool
+}
+
+type devSpanRow struct {
+	devSpan
+	Depth    int
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+type devPage struct {
+	Traces  []devTrace
+	TraceID string
+	Spans   []devSpanRow
+	Logs    []devLog
+}
+
+// DevUIHandler serves a page listing the recent traces with their spans and
+// logs, kept in memory in Config.DevMode, so local development needs no
+// Jaeger or Grafana. Outside DevMode it responds 404.
+func DevUIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !currentConfig().DevMode {
+			http.Error(w, "eotel: the dev UI requires Config.DevMode", http.StatusNotFound)
+			return
+		}
+		devStore.mu.Lock()
+		spans := append([]devSpan(nil), devStore.spans...)
+		logs := append([]devLog(nil), devStore.logs...)
+		devStore.mu.Unlock()
+
+		page := devPage{TraceID: r.URL.Query().Get("trace")}
+		if page.TraceID == "" {
+			page.Traces = devTraces(spans)
+			for i := len(logs) - 1; i >= 0 && len(page.Logs) < 200; i-- {
+				page.Logs = append(page.Logs, logs[i])
+			}
+		} else {
+			page.Spans = devSpanTree(page.TraceID, spans)
+			for _, l := range logs {
+				if l.TraceID == page.TraceID {
+					page.Logs = append(page.Logs, l)
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := devUITemplate.Execute(w, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// devTraces summarises spans per trace, most recent first.
+func devTraces(spans []devSpan) []devTrace {
+	byID := map[string]*devTrace{}
+	var order []*devTrace
+	for _, s := range spans {
+		t, ok := byID[s.TraceID]
+		if !ok {
+			t = &devTrace{ID: s.TraceID, Start: s.Start}
+			byID[s.TraceID] = t
+			order = append(order, t)
+		}
+		t.Spans++
+		if s.Status != "" {
+			t.Error = true
+		}
+		if s.Start.Before(t.Start) {
+			t.Start = s.Start
+		}
+		if s.ParentID == "" || t.Root == "" {
+			t.Root = s.Name
+		}
+		if d := s.End.Sub(t.Start); d > t.Duration {
+			t.Duration = d
+		}
+	}
+	out := make([]devTrace, len(order))
+	for i, t := range order {
+		out[len(order)-1-i] = *t
+	}
+	return out
+}
+
+// devSpanTree orders the spans of a trace depth-first under their parents.
+func devSpanTree(traceID string, spans []devSpan) []devSpanRow {
+	children := map[string][]devSpan{}
+	ids := map[string]bool{}
+	var start time.Time
+	for _, s := range spans {
+		if s.TraceID != traceID {
+			continue
+		}
+		ids[s.SpanID] = true
+		if start.IsZero() || s.Start.Before(start) {
+			start = s.Start
+		}
+	}
+	for _, s := range spans {
+		if s.TraceID != traceID {
+			continue
+		}
+		parent := s.ParentID
+		if !ids[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], s)
+	}
+
+	var rows []devSpanRow
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		kids := children[parent]
+		sort.Slice(kids, func(i, j int) bool { return kids[i].Start.Before(kids[j].Start) })
+		for _, s := range kids {
+			rows = append(rows, devSpanRow{devSpan: s, Depth: depth, Offset: s.Start.Sub(start), Duration: s.End.Sub(s.Start)})
+			walk(s.SpanID, depth+1)
+		}
+	}
+	walk("", 0)
+	return rows
+}
+
+var devUITemplate = template.Must(template.New("devui").Funcs(template.FuncMap{
+	"indent": func(depth int) int { return depth * 20 },
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>eotel dev UI</title>
+<style>
+body{font:14px system-ui,sans-serif;margin:1.5em}
+table{border-collapse:collapse;width:100%}
+td,th{padding:4px 8px;border-bottom:1px solid #ddd;text-align:left;vertical-align:top}
+.err{color:#b00}
+code,pre{font:12px ui-monospace,monospace;margin:0;white-space:pre-wrap}
+</style></head><body>
+{{if .TraceID}}
+<p><a href="?">&larr; all traces</a></p>
+<h2>Trace {{.TraceID}}</h2>
+<table><tr><th>Span</th><th>Kind</th><th>Start</th><th>Duration</th><th>Attributes</th></tr>
+{{range .Spans}}<tr{{if .Status}} class="err"{{end}}>
+<td style="padding-left:{{indent .Depth}}px">{{.Name}}{{if .Status}} ({{.Status}}){{end}}</td>
+<td>{{.Kind}}</td><td>+{{.Offset}}</td><td>{{.Duration}}</td>
+<td><pre>{{range $k, $v := .Attributes}}{{$k}}={{$v}}
+{{end}}</pre></td></tr>{{end}}
+</table>
+{{else}}
+<h2>Recent traces</h2>
+<table><tr><th>Root span</th><th>Started</th><th>Duration</th><th>Spans</th></tr>
+{{range .Traces}}<tr{{if .Error}} class="err"{{end}}>
+<td><a href="?trace={{.ID}}">{{.Root}}</a></td><td>{{.Start.Format "15:04:05.000"}}</td><td>{{.Duration}}</td><td>{{.Spans}}</td></tr>{{end}}
+</table>
+{{end}}
+<h2>Logs</h2>
+<table><tr><th>Time</th><th>Level</th><th>Message</th><th>Fields</th></tr>
+{{range .Logs}}<tr{{if or (eq .Level "error") (eq .Level "fatal")}} class="err"{{end}}>
+<td>{{.Time.Format "15:04:05.000"}}</td><td>{{.Level}}</td>
+<td>{{.Message}}{{if and (not $.TraceID) .TraceID}} <a href="?trace={{.TraceID}}">trace</a>{{end}}</td>
+<td><pre>{{range $k, $v := .Fields}}{{$k}}={{$v}}
+{{end}}</pre></td></tr>{{end}}
+</table>
+</body></html>
+`))
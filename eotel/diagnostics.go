@@ -0,0 +1,135 @@
+package eotel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteDiagnostics writes a plain-text snapshot for troubleshooting a live
+// process: the log level, the pipeline health with queue depths, the
+// effective configuration with secrets masked, and every goroutine's stack.
+func WriteDiagnostics(w io.Writer) error {
+	fmt.Fprintf(w, "eotel diagnostics %s\n", time.Now().Format(time.RFC3339Nano))
+	fmt.Fprintf(w, "service: %s\nlevel: %s\ngoroutines: %d\n", currentConfig().ServiceName, Level(), runtime.NumGoroutine())
+
+	fmt.Fprintf(w, "\n== health ==\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	health, err := json.MarshalIndent(Health(ctx), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\n", health)
+
+	fmt.Fprintf(w, "\n== config ==\n")
+	cfg, err := yaml.Marshal(CurrentConfig())
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n== goroutines ==\n")
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// dumpDiagnostics writes WriteDiagnostics to a new file in
+// Config.DiagnosticsDir (the temp dir by default) and logs its path.
+func dumpDiagnostics() {
+	cfg := currentConfig()
+	dir := cfg.DiagnosticsDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	name := fmt.Sprintf("eotel-diagnostics-%s-%d.txt", sanitizeLokiLabel(cfg.ServiceName), time.Now().UnixNano())
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		getLogger().Warn("eotel: diagnostics dump failed", zap.Error(err))
+		return
+	}
+	err = WriteDiagnostics(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		getLogger().Warn("eotel: diagnostics dump failed", zap.String("path", path), zap.Error(err))
+		return
+	}
+	getLogger().Warn("eotel: diagnostics written", zap.String("path", path))
+}
+
+// debugToggle remembers the level debug logging was switched on from.
+var debugToggle struct {
+	mu    sync.Mutex
+	on    bool
+	saved zapcore.Level
+}
+
+// toggleDebug switches the runtime level to debug, or back to the level it
+// had before the previous toggle.
+func toggleDebug() {
+	debugToggle.mu.Lock()
+	defer debugToggle.mu.Unlock()
+	if debugToggle.on && minLevel.Level() == zapcore.DebugLevel {
+		minLevel.SetLevel(debugToggle.saved)
+		debugToggle.on = false
+		getLogger().Warn("eotel: debug logging disabled", zap.String("level", Level()))
+		return
+	}
+	debugToggle.saved = minLevel.Level()
+	debugToggle.on = true
+	minLevel.SetLevel(zapcore.DebugLevel)
+	getLogger().Warn("eotel: debug logging enabled", zap.String("previous_level", debugToggle.saved.String()))
+}
+
+var signalToggles atomic.Bool
+
+// startSignalToggles toggles debug logging on debugSignal and dumps
+// diagnostics on dumpSignal (SIGUSR1 and SIGUSR2 on Unix). It does nothing
+// where those signals do not exist.
+func startSignalToggles() func() {
+	if debugSignal == nil || !signalToggles.CompareAndSwap(false, true) {
+		return func() {}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, debugSignal, dumpSignal)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				if sig == debugSignal {
+					toggleDebug()
+				} else {
+					dumpDiagnostics()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			signalToggles.Store(false)
+		})
+	}
+}
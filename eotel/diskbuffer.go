@@ -0,0 +1,600 @@
+package eotel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDiskBufferMaxBytes = 100 << 20
+	defaultDiskBufferMaxAge   = 24 * time.Hour
+
+	spillSegmentSize = 4 << 20
+	spillReplayBatch = 512
+	spillSuffix      = ".spill"
+)
+
+// DiskBuffer spills Loki entries and spans to files under Dir when their
+// backend is unreachable or its circuit is open, and replays them after the
+// next successful export. The oldest data is evicted past MaxBytes and
+// discarded on replay once older than MaxAge. Replay is at-least-once: a
+// segment that fails part way is sent again in full. Empty Dir disables it.
+type DiskBuffer struct {
+	Dir      string        `yaml:"dir"`
+	MaxBytes int           `yaml:"max_bytes"`
+	MaxAge   time.Duration `yaml:"max_age"`
+}
+
+type spillSegment struct {
+	path    string
+	size    int64
+	records int
+	created time.Time
+}
+
+// spillQueue is a bounded on-disk queue of newline-delimited records, split
+// into segments so replay and eviction work a file at a time. Only the last
+// segment is ever appended to.
+type spillQueue struct {
+	backend  string
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	mu       sync.Mutex
+	segments []*spillSegment
+	cur      *os.File
+	size     int64
+
+	replaying atomic.Bool
+	entries   metric.Int64Counter
+}
+
+// openSpill returns nil when cfg disables the buffer. Segments left by a
+// previous run are picked up and replayed with the rest.
+func openSpill(backend string, cfg DiskBuffer, meter metric.Meter) (*spillQueue, error) {
+	if cfg.Dir == "" {
+		return nil, nil
+	}
+	q := &spillQueue{
+		backend:  backend,
+		dir:      filepath.Join(cfg.Dir, backend),
+		maxBytes: int64(cfg.MaxBytes),
+		maxAge:   cfg.MaxAge,
+	}
+	if q.maxBytes <= 0 {
+		q.maxBytes = defaultDiskBufferMaxBytes
+	}
+	if q.maxAge <= 0 {
+		q.maxAge = defaultDiskBufferMaxAge
+	}
+	if err := os.MkdirAll(q.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("disk buffer: %w", err)
+	}
+	names, err := filepath.Glob(filepath.Join(q.dir, "*"+spillSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("disk buffer: %w", err)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("disk buffer: %w", err)
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, fmt.Errorf("disk buffer: %w", err)
+		}
+		q.segments = append(q.segments, &spillSegment{
+			path:    name,
+			size:    int64(len(data)),
+			records: bytes.Count(data, []byte{'\n'}),
+			created: info.ModTime(),
+		})
+		q.size += int64(len(data))
+	}
+	q.entries, _ = meter.Int64Counter("eotel.spill.entries",
+		metric.WithUnit("{entry}"),
+		metric.WithDescription("Entries written to, replayed from or discarded by the disk buffer."))
+	return q, nil
+}
+
+func (q *spillQueue) count(n int, outcome string) {
+	if n == 0 {
+		return
+	}
+	q.entries.Add(context.Background(), int64(n), metric.WithAttributes(
+		attribute.String("backend", q.backend),
+		attribute.String("outcome", outcome)))
+}
+
+// push appends records and evicts whole segments, oldest first, until the
+// queue fits in maxBytes again. It reports false when nothing was written,
+// in which case the caller drops the data as it would without a buffer.
+func (q *spillQueue) push(records [][]byte) bool {
+	if q == nil || len(records) == 0 {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, r := range records {
+		buf.Write(r)
+		buf.WriteByte('\n')
+	}
+	last := q.last()
+	if last == nil || last.size >= spillSegmentSize {
+		if err := q.rotate(); err != nil {
+			getLogger().Warn("eotel: disk buffer unavailable", zap.String("backend", q.backend), zap.Error(err))
+			return false
+		}
+		last = q.last()
+	}
+	if _, err := q.cur.Write(buf.Bytes()); err != nil {
+		getLogger().Warn("eotel: disk buffer write failed", zap.String("backend", q.backend), zap.Error(err))
+		return false
+	}
+	last.size += int64(buf.Len())
+	last.records += len(records)
+	q.size += int64(buf.Len())
+	q.count(len(records), "spilled")
+
+	for q.size > q.maxBytes && len(q.segments) > 1 {
+		q.count(q.segments[0].records, "evicted")
+		q.removeLocked(q.segments[0])
+	}
+	return true
+}
+
+func (q *spillQueue) last() *spillSegment {
+	if q.cur == nil || len(q.segments) == 0 {
+		return nil
+	}
+	return q.segments[len(q.segments)-1]
+}
+
+func (q *spillQueue) rotate() error {
+	q.closeLocked()
+	now := time.Now()
+	path := filepath.Join(q.dir, fmt.Sprintf("%020d%s", now.UnixNano(), spillSuffix))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	q.cur = f
+	q.segments = append(q.segments, &spillSegment{path: path, created: now})
+	return nil
+}
+
+func (q *spillQueue) closeLocked() {
+	if q.cur != nil {
+		_ = q.cur.Close()
+		q.cur = nil
+	}
+}
+
+func (q *spillQueue) removeLocked(seg *spillSegment) {
+	for i, s := range q.segments {
+		if s == seg {
+			q.segments = append(q.segments[:i], q.segments[i+1:]...)
+			q.size -= seg.size
+			_ = os.Remove(seg.path)
+			return
+		}
+	}
+}
+
+// pending reports whether there is anything to replay.
+func (q *spillQueue) pending() bool {
+	return q != nil && !q.replaying.Load() && q.buffered()
+}
+
+// buffered reports whether anything is on disk.
+func (q *spillQueue) buffered() bool {
+	if q == nil {
+		return false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.segments) > 0
+}
+
+// records is the number of records on disk.
+func (q *spillQueue) records() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, s := range q.segments {
+		n += s.records
+	}
+	return n
+}
+
+// shift removes the oldest segment and returns its records, discarding
+// expired and unreadable segments on the way. It returns nil when the queue
+// is empty.
+func (q *spillQueue) shift() [][]byte {
+	if q == nil {
+		return nil
+	}
+	for {
+		q.mu.Lock()
+		if len(q.segments) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		seg := q.segments[0]
+		if len(q.segments) == 1 {
+			q.closeLocked()
+		}
+		q.mu.Unlock()
+
+		if time.Since(seg.created) > q.maxAge {
+			q.discard(seg, "expired")
+			continue
+		}
+		records, err := readSpillSegment(seg.path)
+		if err != nil {
+			getLogger().Warn("eotel: disk buffer segment unreadable", zap.String("path", seg.path), zap.Error(err))
+			q.discard(seg, "corrupt")
+			continue
+		}
+		q.discard(seg, "replayed")
+		if len(records) > 0 {
+			return records
+		}
+	}
+}
+
+func (q *spillQueue) close() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.closeLocked()
+	q.mu.Unlock()
+}
+
+// replay sends the buffered segments through send in batches, oldest first,
+// stopping at the first failure. Only one replay runs at a time; calls made
+// meanwhile return immediately.
+func (q *spillQueue) replay(send func(records [][]byte) error) {
+	if q == nil || !q.replaying.CompareAndSwap(false, true) {
+		return
+	}
+	defer q.replaying.Store(false)
+
+	q.mu.Lock()
+	if len(q.segments) == 0 {
+		q.mu.Unlock()
+		return
+	}
+	q.closeLocked()
+	segments := append([]*spillSegment(nil), q.segments...)
+	q.mu.Unlock()
+
+	for _, seg := range segments {
+		if time.Since(seg.created) > q.maxAge {
+			q.discard(seg, "expired")
+			continue
+		}
+		records, err := readSpillSegment(seg.path)
+		if err != nil {
+			getLogger().Warn("eotel: disk buffer segment unreadable", zap.String("path", seg.path), zap.Error(err))
+			q.discard(seg, "corrupt")
+			continue
+		}
+		for len(records) > 0 {
+			n := min(len(records), spillReplayBatch)
+			if err := send(records[:n]); err != nil {
+				return
+			}
+			records = records[n:]
+		}
+		q.discard(seg, "replayed")
+	}
+}
+
+func (q *spillQueue) discard(seg *spillSegment, outcome string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.count(seg.records, outcome)
+	q.removeLocked(seg)
+}
+
+func readSpillSegment(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records [][]byte
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), spillSegmentSize)
+	for sc.Scan() {
+		if len(sc.Bytes()) > 0 {
+			records = append(records, bytes.Clone(sc.Bytes()))
+		}
+	}
+	return records, sc.Err()
+}
+
+// spillLoki buffers a batch Loki could not take.
+func (q *spillQueue) spillLoki(batch []LokiEntry) bool {
+	if q == nil {
+		return false
+	}
+	return q.push(lokiQueueCodec.encode(batch))
+}
+
+func decodeLokiRecords(records [][]byte) []LokiEntry {
+	batch := make([]LokiEntry, 0, len(records))
+	for _, r := range records {
+		var e LokiEntry
+		if json.Unmarshal(r, &e) == nil {
+			batch = append(batch, e)
+		}
+	}
+	return batch
+}
+
+// spillSpans buffers spans the collector could not take.
+func (q *spillQueue) spillSpans(spans []sdktrace.ReadOnlySpan) bool {
+	if q == nil {
+		return false
+	}
+	records := make([][]byte, 0, len(spans))
+	for _, s := range spans {
+		b, err := json.Marshal(encodeSpillSpan(s))
+		if err != nil {
+			continue
+		}
+		records = append(records, b)
+	}
+	return q.push(records)
+}
+
+// replaySpans exports the buffered spans through exp, in the background.
+func (q *spillQueue) replaySpans(exp sdktrace.SpanExporter) {
+	if !q.pending() {
+		return
+	}
+	go q.replay(func(records [][]byte) error {
+		spans := make(tracetest.SpanStubs, 0, len(records))
+		for _, r := range records {
+			var s spillSpan
+			if json.Unmarshal(r, &s) == nil {
+				spans = append(spans, s.stub())
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeoutOr(currentConfig().ExporterTimeouts.OTLP, defaultOTLPTimeout))
+		defer cancel()
+		return exp.ExportSpans(ctx, spans.Snapshots())
+	})
+}
+
+// spillSpan is the on-disk form of a finished span. Dropped counts and the
+// child count are not kept.
+type spillSpan struct {
+	Name          string         `json:"name"`
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	TraceFlags    byte           `json:"trace_flags,omitempty"`
+	TraceState    string         `json:"trace_state,omitempty"`
+	Parent        spillLink      `json:"parent,omitzero"`
+	Kind          int            `json:"kind"`
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+	Attrs         []spillAttr    `json:"attrs,omitempty"`
+	Events        []spillEvent   `json:"events,omitempty"`
+	Links         []spillLink    `json:"links,omitempty"`
+	StatusCode    uint32         `json:"status_code,omitempty"`
+	StatusMessage string         `json:"status_message,omitempty"`
+	Resource      []spillAttr    `json:"resource,omitempty"`
+	ResourceURL   string         `json:"resource_schema_url,omitempty"`
+	Scope         spillScopeInfo `json:"scope"`
+}
+
+type spillScopeInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	SchemaURL string `json:"schema_url,omitempty"`
+}
+
+type spillEvent struct {
+	Name  string      `json:"name"`
+	Time  time.Time   `json:"time"`
+	Attrs []spillAttr `json:"attrs,omitempty"`
+}
+
+type spillLink struct {
+	TraceID    string      `json:"trace_id,omitempty"`
+	SpanID     string      `json:"span_id,omitempty"`
+	TraceFlags byte        `json:"trace_flags,omitempty"`
+	TraceState string      `json:"trace_state,omitempty"`
+	Remote     bool        `json:"remote,omitempty"`
+	Attrs      []spillAttr `json:"attrs,omitempty"`
+}
+
+// spillAttr keeps the attribute type next to the value so int64 survives
+// the round trip through JSON numbers.
+type spillAttr struct {
+	Key   string          `json:"k"`
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v"`
+}
+
+func encodeSpillSpan(s sdktrace.ReadOnlySpan) spillSpan {
+	sc := s.SpanContext()
+	out := spillSpan{
+		Name:          s.Name(),
+		TraceID:       sc.TraceID().String(),
+		SpanID:        sc.SpanID().String(),
+		TraceFlags:    byte(sc.TraceFlags()),
+		TraceState:    sc.TraceState().String(),
+		Kind:          int(s.SpanKind()),
+		Start:         s.StartTime(),
+		End:           s.EndTime(),
+		Attrs:         encodeSpillAttrs(s.Attributes()),
+		StatusCode:    uint32(s.Status().Code),
+		StatusMessage: s.Status().Description,
+		Scope: spillScopeInfo{
+			Name:      s.InstrumentationScope().Name,
+			Version:   s.InstrumentationScope().Version,
+			SchemaURL: s.InstrumentationScope().SchemaURL,
+		},
+	}
+	if p := s.Parent(); p.IsValid() {
+		out.Parent = encodeSpillLink(p, nil)
+	}
+	for _, e := range s.Events() {
+		out.Events = append(out.Events, spillEvent{Name: e.Name, Time: e.Time, Attrs: encodeSpillAttrs(e.Attributes)})
+	}
+	for _, l := range s.Links() {
+		out.Links = append(out.Links, encodeSpillLink(l.SpanContext, l.Attributes))
+	}
+	if r := s.Resource(); r != nil {
+		out.Resource = encodeSpillAttrs(r.Attributes())
+		out.ResourceURL = r.SchemaURL()
+	}
+	return out
+}
+
+func encodeSpillLink(sc trace.SpanContext, attrs []attribute.KeyValue) spillLink {
+	return spillLink{
+		TraceID:    sc.TraceID().String(),
+		SpanID:     sc.SpanID().String(),
+		TraceFlags: byte(sc.TraceFlags()),
+		TraceState: sc.TraceState().String(),
+		Remote:     sc.IsRemote(),
+		Attrs:      encodeSpillAttrs(attrs),
+	}
+}
+
+func encodeSpillAttrs(attrs []attribute.KeyValue) []spillAttr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]spillAttr, 0, len(attrs))
+	for _, kv := range attrs {
+		v, err := json.Marshal(kv.Value.AsInterface())
+		if err != nil {
+			continue
+		}
+		out = append(out, spillAttr{Key: string(kv.Key), Type: kv.Value.Type().String(), Value: v})
+	}
+	return out
+}
+
+func (s spillSpan) stub() tracetest.SpanStub {
+	stub := tracetest.SpanStub{
+		Name:        s.Name,
+		SpanContext: spillLink{TraceID: s.TraceID, SpanID: s.SpanID, TraceFlags: s.TraceFlags, TraceState: s.TraceState}.spanContext(),
+		Parent:      s.Parent.spanContext(),
+		SpanKind:    trace.SpanKind(s.Kind),
+		StartTime:   s.Start,
+		EndTime:     s.End,
+		Attributes:  decodeSpillAttrs(s.Attrs),
+		Status:      sdktrace.Status{Code: codes.Code(s.StatusCode), Description: s.StatusMessage},
+		Resource:    resource.NewWithAttributes(s.ResourceURL, decodeSpillAttrs(s.Resource)...),
+		InstrumentationScope: instrumentation.Scope{
+			Name:      s.Scope.Name,
+			Version:   s.Scope.Version,
+			SchemaURL: s.Scope.SchemaURL,
+		},
+	}
+	for _, e := range s.Events {
+		stub.Events = append(stub.Events, sdktrace.Event{Name: e.Name, Time: e.Time, Attributes: decodeSpillAttrs(e.Attrs)})
+	}
+	for _, l := range s.Links {
+		stub.Links = append(stub.Links, sdktrace.Link{SpanContext: l.spanContext(), Attributes: decodeSpillAttrs(l.Attrs)})
+	}
+	return stub
+}
+
+func (l spillLink) spanContext() trace.SpanContext {
+	cfg := trace.SpanContextConfig{TraceFlags: trace.TraceFlags(l.TraceFlags), Remote: l.Remote}
+	cfg.TraceID, _ = trace.TraceIDFromHex(l.TraceID)
+	cfg.SpanID, _ = trace.SpanIDFromHex(l.SpanID)
+	cfg.TraceState, _ = trace.ParseTraceState(l.TraceState)
+	return trace.NewSpanContext(cfg)
+}
+
+func decodeSpillAttrs(in []spillAttr) []attribute.KeyValue {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]attribute.KeyValue, 0, len(in))
+	for _, a := range in {
+		if kv, ok := decodeSpillAttr(a); ok {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+func decodeSpillAttr(a spillAttr) (attribute.KeyValue, bool) {
+	var kv attribute.KeyValue
+	var err error
+	switch a.Type {
+	case "BOOL":
+		var v bool
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.Bool(a.Key, v)
+	case "INT64":
+		var v int64
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.Int64(a.Key, v)
+	case "FLOAT64":
+		var v float64
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.Float64(a.Key, v)
+	case "STRING":
+		var v string
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.String(a.Key, v)
+	case "BOOLSLICE":
+		var v []bool
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.BoolSlice(a.Key, v)
+	case "INT64SLICE":
+		var v []int64
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.Int64Slice(a.Key, v)
+	case "FLOAT64SLICE":
+		var v []float64
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.Float64Slice(a.Key, v)
+	case "STRINGSLICE":
+		var v []string
+		err = json.Unmarshal(a.Value, &v)
+		kv = attribute.StringSlice(a.Key, v)
+	default:
+		return kv, false
+	}
+	return kv, err == nil
+}
@@ -0,0 +1,63 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// inflight tracks export work running in the background (budgeted exporter
+// calls, flushes triggered by error entries) so shutdown and Drain can wait
+// for it.
+var inflight struct {
+	wg sync.WaitGroup
+	n  atomic.Int64
+}
+
+func goTracked(fn func()) {
+	inflight.wg.Add(1)
+	inflight.n.Add(1)
+	go func() {
+		defer func() {
+			inflight.n.Add(-1)
+			inflight.wg.Done()
+		}()
+		fn()
+	}()
+}
+
+func waitInflight(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		inflight.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d export operations in flight: %w", inflight.n.Load(), ctx.Err())
+	}
+}
+
+// Drain blocks until every background export operation has finished and all
+// buffered logs, spans, metrics and Sentry events have been sent, or until
+// ctx is done. Unlike the shutdown function it leaves the pipelines running,
+// which makes it suitable for tests and between batch job steps.
+func Drain(ctx context.Context) error {
+	var errs []error
+	if err := waitInflight(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := flushAll(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if mp := globalMeterProvider; mp != nil {
+		if err := mp.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
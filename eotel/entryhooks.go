@@ -0,0 +1,74 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Entry is a log entry as seen by hooks registered with RegisterHook. Level,
+// Message and Logger are informational; fields added through AddField end
+// up on the log line and on the active span.
+type Entry struct {
+	Level   string
+	Message string
+	Logger  string
+
+	fields []Field
+}
+
+// AddField attaches key=value to the entry.
+func (e *Entry) AddField(key string, value any) {
+	if key != "" {
+		e.fields = append(e.fields, anyField(key, value))
+	}
+}
+
+// AddFields attaches pre-built fields to the entry.
+func (e *Entry) AddFields(fields ...Field) {
+	for _, f := range fields {
+		if f.zap.Key != "" {
+			e.fields = append(e.fields, f)
+		}
+	}
+}
+
+var entryHooks struct {
+	mu   sync.RWMutex
+	list []func(ctx context.Context, e *Entry)
+}
+
+// RegisterHook adds h to the hooks run on every log entry before it is
+// exported, so per-request data (tenant, region, flag variants) found in ctx
+// reaches every log line and span without WithField at each call site.
+func RegisterHook(h func(ctx context.Context, e *Entry)) {
+	if h == nil {
+		return
+	}
+	entryHooks.mu.Lock()
+	entryHooks.list = append(entryHooks.list, h)
+	entryHooks.mu.Unlock()
+}
+
+// runEntryHooks returns the fields added by the registered hooks, passed
+// through appendField so redaction, truncation and key policies apply.
+func runEntryHooks(ctx context.Context, level, msg, logger string) ([]zap.Field, []attribute.KeyValue) {
+	entryHooks.mu.RLock()
+	hooks := entryHooks.list
+	entryHooks.mu.RUnlock()
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+
+	e := &Entry{Level: level, Message: msg, Logger: logger}
+	for _, h := range hooks {
+		h(ctx, e)
+	}
+	var acc Eotel
+	for _, f := range e.fields {
+		acc.appendField(f)
+	}
+	return acc.fields, acc.attrs
+}
@@ -0,0 +1,592 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/codes"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type loggerCtxKey struct{}
+
+type Exporter interface {
+	Send(level string, msg string, traceID string, spanID string)
+	CaptureError(err error, tags map[string]string, extras map[string]any)
+}
+
+// Eotel is safe for concurrent use: the With* methods never mutate the
+// receiver and instead return a copy carrying the extra state, zap-style.
+// Always use the returned logger.
+type Eotel struct {
+	ctx          context.Context
+	logger       *zap.Logger
+	tracer       trace.Tracer
+	meter        metric.Meter
+	span         trace.Span
+	logCounter   metric.Int64Counter
+	durationHist metric.Float64Histogram
+	fields       []zap.Field
+	attrs        []attribute.KeyValue
+	err          error
+	name         string
+	start        time.Time
+	exporter     Exporter
+	aggs         *aggregator
+	aggregate    *spanAggregate
+	service      string
+	requestID    string
+	budget       *latencyBudget
+	scope        *spanScope
+	scoped       []scopedField
+	provider     *Provider
+}
+
+func New(ctx context.Context, name string) *Eotel {
+	ensureInit("New")
+	meter := getMeter()
+	logCounter, durationHist := logMetrics(meter)
+	return &Eotel{
+		ctx:          ctx,
+		logger:       getLogger(),
+		tracer:       getTracer(),
+		meter:        meter,
+		logCounter:   logCounter,
+		durationHist: durationHist,
+		start:        time.Now(),
+		exporter:     globalExporter,
+		name:         name,
+		aggs:         &aggregator{},
+	}
+}
+
+func Inject(ctx context.Context, logger *Eotel) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+func FromContext(ctx context.Context, name string) *Eotel {
+	if val := ctx.Value(loggerCtxKey{}); val != nil {
+		if lg, ok := val.(*Eotel); ok && lg != nil {
+			return lg
+		}
+	}
+	return Noop(name) // fallback safe
+}
+
+func Safe(l *Eotel) *Eotel {
+	if l == nil {
+		return Noop("safe")
+	}
+	return l
+}
+
+func (l *Eotel) Info(msg string)  { l.log("info", msg) }
+func (l *Eotel) Error(msg string) { l.log("error", msg) }
+func (l *Eotel) Debug(msg string) { l.log("debug", msg) }
+func (l *Eotel) Warn(msg string)  { l.log("warn", msg) }
+func (l *Eotel) Fatal(msg string) {
+	l.log("fatal", msg)
+	l.End()
+	fatal(msg)
+}
+
+// Infof, Errorf, Debugf and Warnf format the message only when the level is
+// enabled.
+func (l *Eotel) Infof(format string, args ...any)  { l.logf("info", format, args) }
+func (l *Eotel) Errorf(format string, args ...any) { l.logf("error", format, args) }
+func (l *Eotel) Debugf(format string, args ...any) { l.logf("debug", format, args) }
+func (l *Eotel) Warnf(format string, args ...any)  { l.logf("warn", format, args) }
+
+// InfoFn, ErrorFn, DebugFn and WarnFn call msg only when the level is
+// enabled, so hot paths don't build messages that are thrown away.
+func (l *Eotel) InfoFn(msg func() string)  { l.logFn("info", msg) }
+func (l *Eotel) ErrorFn(msg func() string) { l.logFn("error", msg) }
+func (l *Eotel) DebugFn(msg func() string) { l.logFn("debug", msg) }
+func (l *Eotel) WarnFn(msg func() string)  { l.logFn("warn", msg) }
+
+func (l *Eotel) logf(level, format string, args []any) {
+	if levelAllowed(level) {
+		l.log(level, fmt.Sprintf(format, args...))
+	}
+}
+
+func (l *Eotel) logFn(level string, msg func() string) {
+	if levelAllowed(level) {
+		l.log(level, msg())
+	}
+}
+
+// log emits the entry to zap and the exporter and attaches it as an event to
+// the active span. It never starts or ends spans; use StartSpan/End for that.
+func (l *Eotel) log(level, msg string) {
+	if l == nil {
+		fmt.Printf("[%s] %s\n", level, msg)
+		return
+	}
+	if !levelAllowed(level) || !activeLogSampler.Load().keep(level, msg, l.err) {
+		return
+	}
+
+	cfg := currentConfig()
+	span := l.activeSpan()
+	sc := span.SpanContext()
+
+	traceID := sc.TraceID().String()
+	extra := providedFields(l.ctx, l.logFields())
+	if hooked, attrs := runEntryHooks(l.ctx, level, msg, l.name); len(hooked) > 0 {
+		extra = append(extra[:len(extra):len(extra)], hooked...)
+		span.SetAttributes(attrs...)
+	}
+	if hooks := cfg.LogHooks; len(hooks) > 0 {
+		rec := runLogHooks(hooks, &Record{
+			Time:    time.Now(),
+			Level:   level,
+			Message: msg,
+			Logger:  l.name,
+			TraceID: traceID,
+			SpanID:  sc.SpanID().String(),
+			Err:     l.err,
+			Fields:  extra[:len(extra):len(extra)],
+		})
+		if rec == nil {
+			return
+		}
+		level, msg, extra = rec.Level, rec.Message, rec.Fields
+	}
+	if rd := activeRedactor.Load(); rd != nil {
+		msg = rd.scrub(msg)
+	}
+
+	// fields is not pooled: the export closure below keeps it and may run
+	// after log returns when the export is buffered with its trace.
+	fields := make([]zap.Field, 0, 5+len(extra))
+	fields = append(fields,
+		zap.String("trace_id", traceID),
+		zap.String("span_id", sc.SpanID().String()),
+		zap.String("job", l.jobName()),
+		zap.String("service", l.serviceName()),
+		zap.String("level", level),
+	)
+	fields = append(fields, extra...)
+
+	if level == "fatal" {
+		writeLastBreath(level, msg, fields)
+	}
+
+	if isShutdown.Load() {
+		logAfterShutdown(level, msg, fields)
+		return
+	}
+
+	captureLog(sc.TraceID(), level, msg, extra)
+	if cfg.DevMode {
+		devRecordLog(sc.TraceID(), level, msg, extra)
+	}
+	if cfg.EnableSentry {
+		addBreadcrumb(l.ctx, l.name, level, msg, extra)
+	}
+
+	if l.logger != nil {
+		switch level {
+		case "info":
+			l.logger.Info(msg, fields...)
+		case "error":
+			l.logger.Error(msg, fields...)
+		case "debug":
+			l.logger.Debug(msg, fields...)
+		case "warn":
+			l.logger.Warn(msg, fields...)
+		case "fatal":
+			// zap's own fatal hook would exit before the pipelines are
+			// flushed; Fatal applies Config.FatalBehavior afterwards.
+			if ce := l.logger.WithOptions(zap.WithFatalHook(noExitHook{})).Check(zapcore.FatalLevel, msg); ce != nil {
+				ce.Write(fields...)
+			}
+		}
+	}
+
+	// Exports may run after the request ends (buffered, or past the
+	// budget), so they must not inherit its cancellation.
+	export := func() {
+		ctx := l.metricCtx()
+		if cfg.EnableOTLPLogs && outputAllows(LogOutputOTLP, level) {
+			emitOTLPLog(ctx, span, level, msg, fields)
+		}
+		if exporterActive(l.exporter) {
+			line, _ := truncateValue(msg)
+			withinBudget(func() {
+				sendWith(ctx, l.exporter, level, line, traceID, sc.SpanID().String(), fieldMap(extra))
+			})
+		}
+	}
+
+	switch {
+	case deferExport(sc, level):
+		bufferExport(sc.TraceID(), export)
+	case cfg.TraceAwareLogs && (level == "error" || level == "fatal"):
+		failLogBuffer(sc.TraceID())
+		export()
+	default:
+		export()
+	}
+
+	if span.IsRecording() {
+		l.applyLevelPolicy(span, level, msg)
+	}
+
+	l.recordLog(level)
+	flushForLevel(level)
+}
+
+func (l *Eotel) TraceName(name string) *Eotel {
+	cp := l.clone()
+	cp.name = name
+	return cp
+}
+
+func (l *Eotel) WithField(key string, value any) *Eotel {
+	if l == nil {
+		return Noop("WithField")
+	}
+	if key == "" {
+		return l
+	}
+	cp := l.clone()
+	cp.addField(key, value)
+	return cp
+}
+
+func (l *Eotel) WithFields(m map[string]any) *Eotel {
+	if l == nil {
+		return Noop("WithFields")
+	}
+	cp := l.clone()
+	for k, v := range m {
+		if k != "" {
+			cp.addField(k, v)
+		}
+	}
+	return cp
+}
+
+// addField keeps the value's type on both the zap field and the span
+// attribute; see FieldValue for the types kept.
+func (l *Eotel) addField(key string, value any) {
+	l.appendField(anyField(key, value))
+}
+
+func (l *Eotel) WithError(err error) *Eotel {
+	if l == nil {
+		return Noop("WithError")
+	}
+	if err == nil {
+		return l
+	}
+	cp := l.withErrorFields(err)
+	if exp := cp.exporter; exporterActive(exp) && firstErrorOfRequest(cp.ctx, err) {
+		ctx := cp.ctx
+		withinBudget(func() {
+			captureErrorWith(ctx, exp, err, map[string]string{}, map[string]any{"error": err.Error()})
+		})
+	}
+	return cp
+}
+
+// withErrorFields is WithError without the capture.
+func (l *Eotel) withErrorFields(err error) *Eotel {
+	cp := l.clone()
+	cp.err = err
+	if rd := activeRedactor.Load(); rd != nil {
+		msg := rd.scrub(err.Error())
+		cp.fields = append(cp.fields, zap.String("error", msg))
+		cp.attrs = append(cp.attrs, attribute.String("error", msg))
+	} else {
+		cp.fields = append(cp.fields, zap.Error(err))
+		cp.attrs = append(cp.attrs, attribute.String("error", err.Error()))
+	}
+	if code := errorCode(err); code != "" {
+		cp.fields = append(cp.fields, zap.String("error.code", code))
+		cp.attrs = append(cp.attrs, attribute.String("error.code", code))
+	}
+	if kind := ErrorKind(err); kind != "" {
+		cp.fields = append(cp.fields, zap.String("error.kind", kind))
+		cp.attrs = append(cp.attrs, attribute.String("error.kind", kind))
+	}
+	return cp
+}
+
+func (l *Eotel) Ctx() context.Context {
+	return l.ctx
+}
+
+func (l *Eotel) activeSpan() trace.Span {
+	if l.span != nil {
+		return l.span
+	}
+	return trace.SpanFromContext(l.ctx)
+}
+
+func (l *Eotel) Span() trace.Span {
+	if l == nil {
+		return nil
+	}
+	return l.activeSpan()
+}
+
+// StartSpan starts a span covering a logical operation. Logs written through
+// the returned logger become events on that span until End is called.
+func (l *Eotel) StartSpan(name string, opts ...ChildOption) *Eotel {
+	return l.Child(name, opts...)
+}
+
+// End finishes the span owned by this logger, if any. Calling End on a logger
+// that did not start a span (New, FromContext) is a no-op.
+func (l *Eotel) End() {
+	if l == nil {
+		return
+	}
+	durationMs := time.Since(l.start).Seconds() * 1000
+
+	if l.aggregate != nil {
+		l.aggregate.record(l.start, time.Now())
+	}
+
+	l.FlushAggregates()
+
+	if l.span == nil {
+		if l.scope != nil {
+			l.scope.ended.Store(true)
+		}
+		return
+	}
+
+	// SetAttributes keeps the last value of a repeated key, so the fields
+	// need no sorting.
+	l.span.SetAttributes(l.spanAttrs()...)
+	if l.scope != nil {
+		l.scope.ended.Store(true)
+	}
+	l.span.SetAttributes(attribute.Float64("duration_ms", durationMs))
+	if l.budget != nil {
+		l.span.SetAttributes(l.budget.endAttrs(time.Since(l.start))...)
+	}
+	if l.err != nil {
+		if !markCanceled(l.span, l.err) {
+			l.span.SetStatus(codes.Error, l.err.Error())
+		}
+		l.span.RecordError(l.err)
+	}
+	l.span.End()
+}
+
+func (l *Eotel) recordLog(level string) {
+	if l.meter == nil {
+		return
+	}
+	durationMs := time.Since(l.start).Seconds() * 1000
+	code, kind := errorCode(l.err), ErrorKind(l.err)
+	opts, ok := levelAttrSets[level]
+	if !ok || l.service != "" || code != "" || kind != "" {
+		metricAttrs := []attribute.KeyValue{attribute.String("level", level)}
+		if l.service != "" {
+			metricAttrs = append(metricAttrs, attribute.String("service", l.service))
+		}
+		if code != "" {
+			metricAttrs = append(metricAttrs, attribute.String("error.code", code))
+		}
+		if kind != "" {
+			metricAttrs = append(metricAttrs, attribute.String("error.kind", kind))
+		}
+		opts = newMeasurementOpts(attribute.NewSet(metricAttrs...))
+	}
+	ctx := l.metricCtx()
+	l.logCounter.Add(ctx, 1, opts.add...)
+	l.durationHist.Record(ctx, durationMs, opts.record...)
+}
+
+// measurementOpts holds an attribute set as ready-made option slices, so
+// passing it to Add and Record does not allocate a variadic slice each time.
+type measurementOpts struct {
+	add    []metric.AddOption
+	record []metric.RecordOption
+}
+
+func newMeasurementOpts(set attribute.Set) measurementOpts {
+	opt := metric.WithAttributeSet(set)
+	return measurementOpts{add: []metric.AddOption{opt}, record: []metric.RecordOption{opt}}
+}
+
+// levelAttrSets are the log metric attributes of the common case, a level
+// alone, built once instead of on every entry.
+var levelAttrSets = func() map[string]measurementOpts {
+	m := map[string]measurementOpts{}
+	for _, level := range []string{"debug", "info", "warn", "error", "fatal"} {
+		m[level] = newMeasurementOpts(attribute.NewSet(attribute.String("level", level)))
+	}
+	return m
+}()
+
+// WithTracer runs fn inside a child span named name. The context passed to fn
+// carries the child logger, so FromContext picks it up.
+func (l *Eotel) WithTracer(name string, fn func(ctx context.Context) error) error {
+	return l.Trace(name, func(child *Eotel) error {
+		return fn(child.ctx)
+	})
+}
+
+// Trace runs fn with a child logger owning a span named name. An error
+// returned by fn is recorded on the span and sets its status; the duration
+// lands in eotel.operation.duration by outcome.
+func (l *Eotel) Trace(name string, fn func(l *Eotel) error) error {
+	if l == nil {
+		return fn(Noop(name))
+	}
+	child := l.Child(name)
+	child.ctx = Inject(child.ctx, child)
+
+	err := fn(child)
+	child.err = err
+	child.End()
+
+	operationMetricsOnce.Do(func() {
+		operationDuration, _ = getMeter().Float64Histogram("eotel.operation.duration",
+			metric.WithUnit("ms"),
+			metric.WithDescription("Duration of operations run through Trace and WithTracer."))
+	})
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	if operationDuration != nil {
+		operationDuration.Record(context.WithoutCancel(child.ctx), time.Since(child.start).Seconds()*1000,
+			metric.WithAttributes(
+				attribute.String("operation.name", name),
+				attribute.String("operation.outcome", outcome),
+			))
+	}
+	return err
+}
+
+var (
+	operationMetricsOnce sync.Once
+	operationDuration    metric.Float64Histogram
+)
+
+func (l *Eotel) SpanEvent(name string, attrs ...attribute.KeyValue) {
+	l.activeSpan().AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// SpanEventWith records a span event whose attributes are flattened from
+// payload, a struct (tagged as for WithStruct) or a map with string keys.
+// Values go through the same redaction and truncation as fields.
+func (l *Eotel) SpanEventWith(name string, payload any) {
+	if l == nil {
+		return
+	}
+	var acc Eotel
+	flattenValue(reflect.ValueOf(payload), "", acc.addField)
+	l.activeSpan().AddEvent(name, trace.WithAttributes(acc.attrs...))
+}
+
+func (l *Eotel) SetSpanAttr(key string, value any) {
+	l.activeSpan().SetAttributes(attribute.String(key, fmt.Sprintf("%v", value)))
+}
+
+func (l *Eotel) SetSpanError(err error) {
+	if err != nil {
+		l.activeSpan().RecordError(err)
+	}
+}
+
+func (l *Eotel) Child(name string, opts ...ChildOption) *Eotel {
+	if l == nil {
+		return Noop(name)
+	}
+	cfg := childConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx := l.ctx
+	tracer := l.tracer
+	if tracer == nil {
+		tracer = getTracer()
+	}
+
+	if cfg.aggregateAfter > 0 {
+		if agg := l.aggs.admit(name, cfg.aggregateAfter); agg != nil {
+			return &Eotel{
+				ctx:          ctx,
+				logger:       l.logger,
+				tracer:       tracer,
+				meter:        l.meter,
+				logCounter:   l.logCounter,
+				durationHist: l.durationHist,
+				fields:       l.fields[:len(l.fields):len(l.fields)],
+				exporter:     l.exporter,
+				name:         name,
+				start:        time.Now(),
+				aggs:         &aggregator{},
+				aggregate:    agg,
+				service:      l.service,
+				requestID:    l.requestID,
+				budget:       l.budget,
+				scope:        &spanScope{},
+				scoped:       l.scoped[:len(l.scoped):len(l.scoped)],
+				provider:     l.provider,
+			}
+		}
+	}
+
+	startOpts := []trace.SpanStartOption{trace.WithAttributes(cfg.attrs...)}
+	if l.budget != nil {
+		startOpts = append(startOpts, trace.WithAttributes(l.budget.startAttrs()...))
+	}
+	if cfg.kind != trace.SpanKindUnspecified {
+		startOpts = append(startOpts, trace.WithSpanKind(cfg.kind))
+	}
+	if l.service != "" {
+		startOpts = append(startOpts, trace.WithAttributes(attribute.String("peer.service", l.service)))
+	}
+	ctx, span := tracer.Start(ctx, name, startOpts...)
+
+	// The capped slices make the first append on either side copy, so the
+	// child inherits the parent's fields without sharing later additions.
+	return &Eotel{
+		ctx:          ctx,
+		span:         span,
+		logger:       l.logger,
+		tracer:       tracer,
+		meter:        l.meter,
+		logCounter:   l.logCounter,
+		durationHist: l.durationHist,
+		fields:       l.fields[:len(l.fields):len(l.fields)],
+		attrs:        l.attrs[:len(l.attrs):len(l.attrs)],
+		exporter:     l.exporter,
+		name:         name,
+		start:        time.Now(),
+		aggs:         &aggregator{},
+		service:      l.service,
+		requestID:    l.requestID,
+		budget:       l.budget,
+		scope:        &spanScope{},
+		scoped:       l.scoped[:len(l.scoped):len(l.scoped)],
+		provider:     l.provider,
+	}
+}
+
+func Noop(name string) *Eotel {
+	return &Eotel{
+		ctx:    context.Background(),
+		logger: zap.NewNop(),
+		name:   name,
+		start:  time.Now(),
+	}
+}
@@ -0,0 +1,180 @@
+package eotel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error kinds set by the built-in matchers as error.kind.
+const (
+	ErrorKindTimeout          = "timeout"
+	ErrorKindCanceled         = "canceled"
+	ErrorKindNotFound         = "not_found"
+	ErrorKindNetwork          = "network"
+	ErrorKindUnavailable      = "unavailable"
+	ErrorKindInvalidArgument  = "invalid_argument"
+	ErrorKindUnauthenticated  = "unauthenticated"
+	ErrorKindPermissionDenied = "permission_denied"
+	ErrorKindConflict         = "conflict"
+	ErrorKindRateLimited      = "rate_limited"
+	ErrorKindInternal         = "internal"
+)
+
+// ErrorMatcher classifies err, reporting false when it does not recognise
+// it.
+type ErrorMatcher func(err error) (kind string, ok bool)
+
+var errorMatchers struct {
+	mu   sync.RWMutex
+	list []ErrorMatcher
+}
+
+// RegisterErrorMatcher adds m to the matchers behind ErrorKind. Registered
+// matchers run in order before the built-in ones, so they can override them.
+func RegisterErrorMatcher(m ErrorMatcher) {
+	if m == nil {
+		return
+	}
+	errorMatchers.mu.Lock()
+	errorMatchers.list = append(errorMatchers.list, m)
+	errorMatchers.mu.Unlock()
+}
+
+// ErrorKind classifies err for the error.kind span attribute, log field and
+// log metric label: context and net timeouts, cancellation, sql.ErrNoRows,
+// gRPC status codes and HTTP statuses (CodedError or any error with a
+// StatusCode() int method) are recognised out of the box. It returns "" for
+// nil and unrecognised errors.
+func ErrorKind(err error) string {
+	if err == nil {
+		return ""
+	}
+	errorMatchers.mu.RLock()
+	matchers := errorMatchers.list
+	errorMatchers.mu.RUnlock()
+	for _, m := range matchers {
+		if kind, ok := m(err); ok {
+			return kind
+		}
+	}
+	for _, m := range builtinErrorMatchers {
+		if kind, ok := m(err); ok {
+			return kind
+		}
+	}
+	return ""
+}
+
+var builtinErrorMatchers = []ErrorMatcher{
+	matchContextError,
+	matchHTTPStatusError,
+	matchGRPCError,
+	matchNotFoundError,
+	matchNetError,
+}
+
+func matchContextError(err error) (string, bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return ErrorKindTimeout, true
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled, true
+	}
+	return "", false
+}
+
+func matchNotFoundError(err error) (string, bool) {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, os.ErrNotExist) {
+		return ErrorKindNotFound, true
+	}
+	return "", false
+}
+
+func matchNetError(err error) (string, bool) {
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return ErrorKindTimeout, true
+		}
+		return ErrorKindNetwork, true
+	}
+	var oe *net.OpError
+	if errors.As(err, &oe) {
+		return ErrorKindNetwork, true
+	}
+	return "", false
+}
+
+func matchGRPCError(err error) (string, bool) {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return "", false
+	}
+	switch se.GRPCStatus().Code() {
+	case codes.OK:
+		return "", false
+	case codes.DeadlineExceeded:
+		return ErrorKindTimeout, true
+	case codes.Canceled:
+		return ErrorKindCanceled, true
+	case codes.NotFound:
+		return ErrorKindNotFound, true
+	case codes.Unavailable:
+		return ErrorKindUnavailable, true
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return ErrorKindInvalidArgument, true
+	case codes.Unauthenticated:
+		return ErrorKindUnauthenticated, true
+	case codes.PermissionDenied:
+		return ErrorKindPermissionDenied, true
+	case codes.AlreadyExists, codes.Aborted:
+		return ErrorKindConflict, true
+	case codes.ResourceExhausted:
+		return ErrorKindRateLimited, true
+	}
+	return ErrorKindInternal, true
+}
+
+func matchHTTPStatusError(err error) (string, bool) {
+	code := 0
+	var sc interface{ StatusCode() int }
+	if ce, ok := AsCodedError(err); ok {
+		code = ce.Status
+	} else if errors.As(err, &sc) {
+		code = sc.StatusCode()
+	}
+	return httpStatusKind(code)
+}
+
+func httpStatusKind(code int) (string, bool) {
+	switch code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrorKindInvalidArgument, true
+	case http.StatusUnauthorized:
+		return ErrorKindUnauthenticated, true
+	case http.StatusForbidden:
+		return ErrorKindPermissionDenied, true
+	case http.StatusNotFound:
+		return ErrorKindNotFound, true
+	case http.StatusConflict:
+		return ErrorKindConflict, true
+	case http.StatusTooManyRequests:
+		return ErrorKindRateLimited, true
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrorKindTimeout, true
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ErrorKindUnavailable, true
+	}
+	if code >= 500 {
+		return ErrorKindInternal, true
+	}
+	return "", false
+}
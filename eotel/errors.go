@@ -0,0 +1,63 @@
+package eotel
+
+import (
+	"errors"
+)
+
+// CodedError ties a stable, user-facing error code to the internal cause so
+// API responses and telemetry classification come from the same definition.
+type CodedError struct {
+	Code    string
+	Status  int
+	Message string
+	Cause   error
+}
+
+func NewCodedError(code string, status int, message string) *CodedError {
+	return &CodedError{Code: code, Status: status, Message: message}
+}
+
+func (e *CodedError) Error() string {
+	if e.Cause != nil {
+		return e.Code + ": " + e.Cause.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches any CodedError carrying the same code, so errors.Is works against
+// the sentinel definition after Wrap.
+func (e *CodedError) Is(target error) bool {
+	var t *CodedError
+	if errors.As(target, &t) {
+		return t.Code == e.Code
+	}
+	return false
+}
+
+func (e *CodedError) Wrap(cause error) *CodedError {
+	c := *e
+	c.Cause = cause
+	return &c
+}
+
+func AsCodedError(err error) (*CodedError, bool) {
+	var ce *CodedError
+	if errors.As(err, &ce) {
+		return ce, true
+	}
+	return nil, false
+}
+
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	if ce, ok := AsCodedError(err); ok {
+		return ce.Code
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package eotel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+// Config.MetricExemplars values.
+const (
+	ExemplarsTraceBased = "trace_based"
+	ExemplarsAlwaysOn   = "always_on"
+	ExemplarsAlwaysOff  = "always_off"
+)
+
+// exemplarFilter maps Config.MetricExemplars to the SDK filter. The default,
+// trace_based, attaches the trace and span IDs of sampled spans to the
+// measurements recorded under them, such as the middleware and timer
+// duration histograms, so a latency bucket links to an example trace.
+func exemplarFilter(mode string) (exemplar.Filter, error) {
+	switch mode {
+	case "", ExemplarsTraceBased:
+		return exemplar.TraceBasedFilter, nil
+	case ExemplarsAlwaysOn:
+		return exemplar.AlwaysOnFilter, nil
+	case ExemplarsAlwaysOff:
+		return exemplar.AlwaysOffFilter, nil
+	}
+	return nil, fmt.Errorf("unsupported metric exemplars %q", mode)
+}
@@ -0,0 +1,235 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type ExporterOption func(*registeredExporter)
+
+// WithExporterLevel only forwards entries at or above level to the exporter.
+func WithExporterLevel(level string) ExporterOption {
+	return func(r *registeredExporter) {
+		r.minLevel = level
+	}
+}
+
+// WithExporterTimeout bounds each call into the exporter to d instead of
+// ExporterTimeouts.Custom.
+func WithExporterTimeout(d time.Duration) ExporterOption {
+	return func(r *registeredExporter) {
+		r.timeout = d
+	}
+}
+
+// registeredExporter bounds every call into exp by its timeout, so a stuck
+// exporter cannot hold up the logger or the exporters after it.
+type registeredExporter struct {
+	exp      Exporter
+	minLevel string
+	timeout  time.Duration
+	builtin  bool
+}
+
+// call runs fn with a context expiring after the exporter's timeout and
+// returns when fn does or the timeout passes, whichever is first; fn then
+// completes in the background. Built-in exporters bound themselves and are
+// called inline.
+func (r registeredExporter) call(ctx context.Context, fn func(ctx context.Context)) {
+	if r.builtin {
+		fn(ctx)
+		return
+	}
+	timeout := r.timeout
+	if timeout <= 0 {
+		timeout = timeoutOr(currentConfig().ExporterTimeouts.Custom, defaultCustomTimeout)
+	}
+	ctx, cancel := exportContext(ctx, timeout)
+	done := make(chan struct{})
+	goTracked(func() {
+		defer close(done)
+		defer cancel()
+		fn(ctx)
+	})
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (r registeredExporter) Send(level, msg, traceID, spanID string) {
+	r.SendFields(context.Background(), level, msg, traceID, spanID, nil)
+}
+
+func (r registeredExporter) SendFields(ctx context.Context, level, msg, traceID, spanID string, fields map[string]any) {
+	r.call(ctx, func(ctx context.Context) { sendWith(ctx, r.exp, level, msg, traceID, spanID, fields) })
+}
+
+func (r registeredExporter) CaptureError(err error, tags map[string]string, extras map[string]any) {
+	r.CaptureErrorContext(context.Background(), err, tags, extras)
+}
+
+func (r registeredExporter) CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	r.call(ctx, func(ctx context.Context) { captureErrorWith(ctx, r.exp, err, tags, extras) })
+}
+
+var exporterRegistry struct {
+	mu   sync.RWMutex
+	list []registeredExporter
+}
+
+// RegisterExporter adds exp to the exporters every logger fans out to.
+// Exporters listed in Config.Exporters are registered by InitEOTEL.
+func RegisterExporter(exp Exporter, opts ...ExporterOption) {
+	if exp == nil {
+		return
+	}
+	r := registeredExporter{exp: exp}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	exporterRegistry.mu.Lock()
+	exporterRegistry.list = append(exporterRegistry.list, r)
+	exporterRegistry.mu.Unlock()
+}
+
+// setBuiltinExporters replaces the exporters InitEOTEL derives from Config,
+// leaving user-registered ones in place, so re-initialising does not
+// duplicate them.
+func setBuiltinExporters(exps ...Exporter) {
+	exporterRegistry.mu.Lock()
+	defer exporterRegistry.mu.Unlock()
+	list := make([]registeredExporter, 0, len(exporterRegistry.list)+len(exps))
+	for _, r := range exporterRegistry.list {
+		if !r.builtin {
+			list = append(list, r)
+		}
+	}
+	for _, exp := range exps {
+		list = append(list, registeredExporter{exp: exp, builtin: true})
+	}
+	exporterRegistry.list = list
+}
+
+func registeredExporters() []registeredExporter {
+	exporterRegistry.mu.RLock()
+	defer exporterRegistry.mu.RUnlock()
+	return exporterRegistry.list
+}
+
+// fanoutExporter forwards to every registered exporter whose level filter
+// accepts the entry. It reads the registry on each call, so exporters
+// registered after a logger was created still receive its entries.
+type fanoutExporter struct{}
+
+var globalExporter Exporter = fanoutExporter{}
+
+// exporterActive reports whether exp would forward anywhere, so callers can
+// skip preparing the entry.
+func exporterActive(exp Exporter) bool {
+	if _, ok := exp.(fanoutExporter); ok {
+		return len(registeredExporters()) > 0
+	}
+	return exp != nil
+}
+
+func (f fanoutExporter) Send(level, msg, traceID, spanID string) {
+	f.SendContext(context.Background(), level, msg, traceID, spanID)
+}
+
+func (f fanoutExporter) SendContext(ctx context.Context, level, msg, traceID, spanID string) {
+	f.SendFields(ctx, level, msg, traceID, spanID, nil)
+}
+
+func (fanoutExporter) SendFields(ctx context.Context, level, msg, traceID, spanID string, fields map[string]any) {
+	for _, r := range registeredExporters() {
+		if levelEnabled(level, r.minLevel) {
+			r.SendFields(ctx, level, msg, traceID, spanID, fields)
+		}
+	}
+}
+
+func (f fanoutExporter) CaptureError(err error, tags map[string]string, extras map[string]any) {
+	f.CaptureErrorContext(context.Background(), err, tags, extras)
+}
+
+func (fanoutExporter) CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	for _, r := range registeredExporters() {
+		if levelEnabled("error", r.minLevel) {
+			r.CaptureErrorContext(ctx, err, tags, extras)
+		}
+	}
+}
+
+// ContextExporter is implemented by exporters that use the logger's context
+// when capturing errors, e.g. to attach request data.
+type ContextExporter interface {
+	CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any)
+}
+
+func captureErrorWith(ctx context.Context, exp Exporter, err error, tags map[string]string, extras map[string]any) {
+	if ce, ok := exp.(ContextExporter); ok {
+		ce.CaptureErrorContext(ctx, err, tags, extras)
+		return
+	}
+	exp.CaptureError(err, tags, extras)
+}
+
+// ContextSender is implemented by exporters that use the logger's context
+// when sending log lines, e.g. to route them per tenant.
+type ContextSender interface {
+	SendContext(ctx context.Context, level, msg, traceID, spanID string)
+}
+
+// FieldSender is implemented by exporters that use the entry's structured
+// fields, e.g. to promote some of them to labels.
+type FieldSender interface {
+	SendFields(ctx context.Context, level, msg, traceID, spanID string, fields map[string]any)
+}
+
+func sendWith(ctx context.Context, exp Exporter, level, msg, traceID, spanID string, fields map[string]any) {
+	if fs, ok := exp.(FieldSender); ok {
+		fs.SendFields(ctx, level, msg, traceID, spanID, fields)
+		return
+	}
+	if cs, ok := exp.(ContextSender); ok {
+		cs.SendContext(ctx, level, msg, traceID, spanID)
+		return
+	}
+	exp.Send(level, msg, traceID, spanID)
+}
+
+// LokiExporter ships log lines to Loki through the batching pusher started by
+// InitEOTEL. It ignores CaptureError; errors reach Loki as error-level lines.
+type LokiExporter struct{}
+
+func (LokiExporter) Send(level, msg, traceID, spanID string) {
+	SendLokiAsync(level, msg, traceID, spanID)
+}
+
+// SendContext routes the line to the Loki tenant of ctx's tenant, if any.
+func (LokiExporter) SendContext(ctx context.Context, level, msg, traceID, spanID string) {
+	sendLoki(ctx, level, msg, traceID, spanID, nil)
+}
+
+// SendFields is SendContext with the entry's fields, which go into the line
+// or, when listed in Config.LokiLabelFields, the stream labels.
+func (LokiExporter) SendFields(ctx context.Context, level, msg, traceID, spanID string, fields map[string]any) {
+	sendLoki(ctx, level, msg, traceID, spanID, fields)
+}
+
+func (LokiExporter) CaptureError(error, map[string]string, map[string]any) {}
+
+// SentryExporter reports errors to Sentry. Plain log lines are not sent.
+type SentryExporter struct{}
+
+func (SentryExporter) Send(string, string, string, string) {}
+
+func (SentryExporter) CaptureError(err error, tags map[string]string, extras map[string]any) {
+	CaptureError(err, tags, extras)
+}
+
+func (SentryExporter) CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	CaptureErrorContext(ctx, err, tags, extras)
+}
@@ -0,0 +1,72 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stuckExporter blocks every call until release is closed, like a webhook
+// whose server stopped answering.
+type stuckExporter struct {
+	release chan struct{}
+	sawCtx  chan bool
+}
+
+func (e stuckExporter) Send(string, string, string, string) { <-e.release }
+
+func (e stuckExporter) SendContext(ctx context.Context, level, msg, traceID, spanID string) {
+	_, ok := ctx.Deadline()
+	e.sawCtx <- ok
+	<-e.release
+}
+
+func (e stuckExporter) CaptureError(error, map[string]string, map[string]any) { <-e.release }
+
+func TestScopeExporterTimeout(t *testing.T) {
+	exp := stuckExporter{release: make(chan struct{}), sawCtx: make(chan bool, 1)}
+	defer close(exp.release)
+
+	saved := globalCfg.Load()
+	globalCfg.Store(&Config{
+		ServiceName:      "test",
+		EnableLoki:       true,
+		ExporterTimeouts: ExporterTimeouts{Custom: 20 * time.Millisecond},
+	})
+	t.Cleanup(func() { globalCfg.Store(saved) })
+
+	log := NewScope("webhook", WithScopeExporter(exp)).New(context.Background(), "handler")
+	start := time.Now()
+	log.Info("msg")
+	log.WithError(errors.New("boom"))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("logging took %s, want it bounded by the exporter timeout", elapsed)
+	}
+}
+
+func TestRegisteredExporterTimeout(t *testing.T) {
+	exp := stuckExporter{release: make(chan struct{}), sawCtx: make(chan bool, 1)}
+	defer close(exp.release)
+
+	exporterRegistry.mu.Lock()
+	saved := exporterRegistry.list
+	exporterRegistry.list = nil
+	exporterRegistry.mu.Unlock()
+	t.Cleanup(func() {
+		exporterRegistry.mu.Lock()
+		exporterRegistry.list = saved
+		exporterRegistry.mu.Unlock()
+	})
+	RegisterExporter(exp, WithExporterTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	fanoutExporter{}.SendContext(context.Background(), "info", "msg", "", "")
+	fanoutExporter{}.CaptureError(errors.New("boom"), nil, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("fanout took %s, want it bounded by the exporter timeout", elapsed)
+	}
+	if !<-exp.sawCtx {
+		t.Error("exporter context has no deadline")
+	}
+}
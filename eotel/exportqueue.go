@@ -0,0 +1,356 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ExportQueue kinds.
+const (
+	QueueChannel = "channel"
+	QueueRing    = "ring"
+	QueueDisk    = "disk"
+)
+
+// ExportQueue backpressure policies.
+const (
+	BackpressureDropNewest = "drop_newest"
+	BackpressureDropOldest = "drop_oldest"
+	BackpressureBlock      = "block"
+)
+
+const defaultQueueBlockTimeout = 100 * time.Millisecond
+
+// ExportQueue configures the queue between callers and an export worker.
+// Kind is QueueChannel (the default), QueueRing, or QueueDisk, which keeps
+// Size entries in memory and overflows to DiskBuffer.Dir (Loki only).
+// Backpressure decides what a full in-memory queue does with a new entry:
+// drop it (the default), evict the oldest entry, or wait up to BlockTimeout
+// for room before dropping it.
+type ExportQueue struct {
+	Kind         string        `yaml:"kind"`
+	Backpressure string        `yaml:"backpressure"`
+	BlockTimeout time.Duration `yaml:"block_timeout"`
+}
+
+func (q ExportQueue) validate(name string, diskOK bool, disk DiskBuffer) error {
+	switch q.Kind {
+	case "", QueueChannel, QueueRing:
+	case QueueDisk:
+		if !diskOK {
+			return fmt.Errorf("%s queue: kind %q is not supported", name, q.Kind)
+		}
+		if disk.Dir == "" {
+			return fmt.Errorf("%s queue: kind %q needs disk_buffer.dir", name, q.Kind)
+		}
+	default:
+		return fmt.Errorf("%s queue: unknown kind %q", name, q.Kind)
+	}
+	switch q.Backpressure {
+	case "", BackpressureDropNewest, BackpressureDropOldest, BackpressureBlock:
+	default:
+		return fmt.Errorf("%s queue: unknown backpressure %q", name, q.Backpressure)
+	}
+	return nil
+}
+
+// queueBuffer is the storage behind an exportQueue. Calls are serialised by
+// the queue's mutex.
+type queueBuffer[T any] interface {
+	offer(v T) bool
+	evictOldest() bool
+	take(max int) []T
+	len() int
+	cap() int
+}
+
+// queueCodec lets a disk queue persist entries; a nil codec rules it out.
+type queueCodec[T any] struct {
+	encode func([]T) [][]byte
+	decode func([][]byte) []T
+}
+
+// exportQueue is the bounded queue shared by the Loki and Sentry workers.
+// Producers never wait longer than the backpressure policy allows; the
+// worker is woken through ready and takes entries in batches.
+type exportQueue[T any] struct {
+	name     string
+	policy   string
+	blockFor time.Duration
+
+	mu     sync.Mutex
+	buf    queueBuffer[T]
+	closed bool
+	ready  chan struct{}
+	space  chan struct{}
+
+	enqueued metric.Int64Counter
+	dropped  metric.Int64Counter
+}
+
+func newExportQueue[T any](name string, size int, cfg ExportQueue, disk DiskBuffer, codec *queueCodec[T], m metric.Meter) (*exportQueue[T], error) {
+	q := &exportQueue[T]{
+		name:     name,
+		policy:   cfg.Backpressure,
+		blockFor: cfg.BlockTimeout,
+		ready:    make(chan struct{}, 1),
+		space:    make(chan struct{}, 1),
+	}
+	if q.blockFor <= 0 {
+		q.blockFor = defaultQueueBlockTimeout
+	}
+	switch cfg.Kind {
+	case QueueRing:
+		q.buf = newRingBuffer[T](size)
+	case QueueDisk:
+		if codec == nil {
+			return nil, fmt.Errorf("%s queue: kind %q is not supported", name, cfg.Kind)
+		}
+		spill, err := openSpill(name+"-queue", disk, m)
+		if err != nil {
+			return nil, err
+		}
+		q.buf = &diskQueueBuffer[T]{mem: newRingBuffer[T](size), spill: spill, codec: codec}
+	default:
+		q.buf = chanBuffer[T](make(chan T, size))
+	}
+	q.enqueued, _ = m.Int64Counter("eotel.queue.enqueued",
+		metric.WithUnit("{entry}"),
+		metric.WithDescription("Entries accepted by an export queue."))
+	q.dropped, _ = m.Int64Counter("eotel.queue.dropped",
+		metric.WithUnit("{entry}"),
+		metric.WithDescription("Entries an export queue could not keep, by reason."))
+	if q.buf.len() > 0 {
+		q.signal(q.ready)
+	}
+	return q, nil
+}
+
+// push adds v under the backpressure policy. It reports whether v was kept
+// and how many older entries were evicted to make room for it.
+func (q *exportQueue[T]) push(v T) (bool, int) {
+	var deadline time.Time
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			q.drop(1, "closed")
+			return false, 0
+		}
+		evicted := 0
+		ok := q.buf.offer(v)
+		if !ok && q.policy == BackpressureDropOldest && q.buf.evictOldest() {
+			evicted = 1
+			ok = q.buf.offer(v)
+		}
+		q.mu.Unlock()
+
+		if ok {
+			q.enqueued.Add(context.Background(), 1, q.attrs())
+			q.drop(evicted, "evicted")
+			q.signal(q.ready)
+			return true, evicted
+		}
+		if q.policy != BackpressureBlock {
+			break
+		}
+		if deadline.IsZero() {
+			deadline = time.Now().Add(q.blockFor)
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-q.space:
+		case <-t.C:
+		}
+		t.Stop()
+	}
+	q.drop(1, "queue_full")
+	return false, 0
+}
+
+// take removes up to max entries, oldest first.
+func (q *exportQueue[T]) take(max int) []T {
+	q.mu.Lock()
+	out := q.buf.take(max)
+	q.mu.Unlock()
+	if len(out) > 0 {
+		q.signal(q.space)
+	}
+	return out
+}
+
+func (q *exportQueue[T]) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.buf.len()
+}
+
+func (q *exportQueue[T]) cap() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.buf.cap()
+}
+
+// close rejects further pushes; entries already queued can still be taken.
+func (q *exportQueue[T]) close() {
+	q.mu.Lock()
+	q.closed = true
+	if d, ok := q.buf.(*diskQueueBuffer[T]); ok {
+		d.spill.close()
+	}
+	q.mu.Unlock()
+}
+
+func (q *exportQueue[T]) signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+func (q *exportQueue[T]) attrs() metric.MeasurementOption {
+	return metric.WithAttributes(attribute.String("queue", q.name))
+}
+
+func (q *exportQueue[T]) drop(n int, reason string) {
+	if n == 0 {
+		return
+	}
+	q.dropped.Add(context.Background(), int64(n), metric.WithAttributes(
+		attribute.String("queue", q.name),
+		attribute.String("reason", reason)))
+}
+
+// chanBuffer is a plain buffered channel.
+type chanBuffer[T any] chan T
+
+func (b chanBuffer[T]) offer(v T) bool {
+	select {
+	case b <- v:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b chanBuffer[T]) evictOldest() bool {
+	select {
+	case <-b:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b chanBuffer[T]) take(max int) []T {
+	var out []T
+	for len(out) < max {
+		select {
+		case v := <-b:
+			out = append(out, v)
+		default:
+			return out
+		}
+	}
+	return out
+}
+
+func (b chanBuffer[T]) len() int { return len(b) }
+func (b chanBuffer[T]) cap() int { return cap(b) }
+
+// ringBuffer is a fixed-size circular buffer; unlike a channel it can drop
+// its oldest entry without a receive racing the worker.
+type ringBuffer[T any] struct {
+	items []T
+	head  int
+	n     int
+}
+
+func newRingBuffer[T any](size int) *ringBuffer[T] {
+	return &ringBuffer[T]{items: make([]T, size)}
+}
+
+func (b *ringBuffer[T]) offer(v T) bool {
+	if b.n == len(b.items) {
+		return false
+	}
+	b.items[(b.head+b.n)%len(b.items)] = v
+	b.n++
+	return true
+}
+
+func (b *ringBuffer[T]) evictOldest() bool {
+	if b.n == 0 {
+		return false
+	}
+	var zero T
+	b.items[b.head] = zero
+	b.head = (b.head + 1) % len(b.items)
+	b.n--
+	return true
+}
+
+func (b *ringBuffer[T]) take(max int) []T {
+	n := min(max, b.n)
+	if n <= 0 {
+		return nil
+	}
+	out := make([]T, n)
+	for i := range out {
+		out[i] = b.items[b.head]
+		b.evictOldest()
+	}
+	return out
+}
+
+func (b *ringBuffer[T]) len() int { return b.n }
+func (b *ringBuffer[T]) cap() int { return len(b.items) }
+
+// diskQueueBuffer keeps entries in memory and overflows to a spill queue.
+// Once anything is on disk new entries follow it there, so order holds: the
+// memory ring is always older than the disk.
+type diskQueueBuffer[T any] struct {
+	mem    *ringBuffer[T]
+	spill  *spillQueue
+	codec  *queueCodec[T]
+	loaded []T
+}
+
+func (b *diskQueueBuffer[T]) offer(v T) bool {
+	if len(b.loaded) == 0 && !b.spill.buffered() && b.mem.offer(v) {
+		return true
+	}
+	return b.spill.push(b.codec.encode([]T{v}))
+}
+
+func (b *diskQueueBuffer[T]) evictOldest() bool { return false }
+
+func (b *diskQueueBuffer[T]) take(max int) []T {
+	out := b.mem.take(max)
+	for len(out) < max {
+		if len(b.loaded) == 0 {
+			records := b.spill.shift()
+			if records == nil {
+				break
+			}
+			b.loaded = b.codec.decode(records)
+			continue
+		}
+		n := min(max-len(out), len(b.loaded))
+		out = append(out, b.loaded[:n]...)
+		b.loaded = b.loaded[n:]
+	}
+	return out
+}
+
+func (b *diskQueueBuffer[T]) len() int { return b.mem.len() + len(b.loaded) + b.spill.records() }
+func (b *diskQueueBuffer[T]) cap() int { return b.mem.cap() }
@@ -0,0 +1,52 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// FatalBehavior values.
+const (
+	FatalExit  = "exit"
+	FatalPanic = "panic"
+	FatalLog   = "log"
+)
+
+func validFatalBehavior(b string) bool {
+	switch b {
+	case "", FatalExit, FatalPanic, FatalLog:
+		return true
+	}
+	return false
+}
+
+// fatal runs after a Fatal entry has been written: every pipeline is flushed
+// so the entry and the spans leading to it survive, then Config.FatalBehavior
+// decides whether the process exits, panics or carries on.
+func fatal(msg string) {
+	ctx, cancel := context.WithTimeout(context.Background(), flushOnErrorTimeout)
+	_ = flushAll(ctx)
+	cancel()
+
+	cfg := currentConfig()
+	switch cfg.FatalBehavior {
+	case FatalLog:
+	case FatalPanic:
+		panic(fmt.Sprintf("eotel: fatal: %s", msg))
+	default:
+		exit := cfg.ExitFunc
+		if exit == nil {
+			exit = os.Exit
+		}
+		exit(1)
+	}
+}
+
+// noExitHook replaces zap's fatal hook; zap substitutes os.Exit for
+// zapcore.WriteThenNoop, so a hook of our own is needed to keep it out.
+type noExitHook struct{}
+
+func (noExitHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {}
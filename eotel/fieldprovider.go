@@ -0,0 +1,53 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// FieldProvider returns fields describing the current state of ctx (feature
+// flag variants, deployment slot, ...). It is called on every log entry, so
+// it should be cheap.
+type FieldProvider func(ctx context.Context) []Field
+
+var fieldProviders struct {
+	mu   sync.RWMutex
+	list []FieldProvider
+}
+
+// RegisterFieldProvider adds p to the providers evaluated at emission time;
+// their fields are appended to every log entry of the logger's context.
+func RegisterFieldProvider(p FieldProvider) {
+	if p == nil {
+		return
+	}
+	fieldProviders.mu.Lock()
+	fieldProviders.list = append(fieldProviders.list, p)
+	fieldProviders.mu.Unlock()
+}
+
+// providedFields appends the global static fields, the promoted baggage
+// members and the output of the registered providers to fields.
+func providedFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	fields = staticFieldsOf(fields)
+	fields = baggageFields(ctx, fields)
+
+	fieldProviders.mu.RLock()
+	providers := fieldProviders.list
+	fieldProviders.mu.RUnlock()
+	if len(providers) == 0 {
+		return fields
+	}
+
+	fields = fields[:len(fields):len(fields)]
+	for _, p := range providers {
+		for _, f := range p(ctx) {
+			if f.zap.Key != "" {
+				fields = append(fields, f.zap)
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,277 @@
+package eotel
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// zapFieldAttr converts a zap field into a span attribute, keeping the
+// primitive types and stringifying everything else.
+func zapFieldAttr(f zap.Field) attribute.KeyValue {
+	switch f.Type {
+	case zapcore.StringType:
+		return attribute.String(f.Key, f.String)
+	case zapcore.BoolType:
+		return attribute.Bool(f.Key, f.Integer == 1)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return attribute.Int64(f.Key, f.Integer)
+	case zapcore.Float64Type:
+		return attribute.Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case zapcore.Float32Type:
+		return attribute.Float64(f.Key, float64(math.Float32frombits(uint32(f.Integer))))
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	if v, ok := enc.Fields[f.Key]; ok {
+		return attribute.String(f.Key, fmt.Sprintf("%v", v))
+	}
+	return attribute.String(f.Key, "")
+}
+
+func (l *Eotel) clone() *Eotel {
+	cp := *l
+	// Cap the slices so appends on the clone never write into the parent.
+	cp.fields = l.fields[:len(l.fields):len(l.fields)]
+	cp.attrs = l.attrs[:len(l.attrs):len(l.attrs)]
+	cp.scoped = l.scoped[:len(l.scoped):len(l.scoped)]
+	return &cp
+}
+
+// appendField is the single entry point for adding fields, applying
+// redaction and the large-value policy uniformly to the zap field and the
+// span attribute.
+// Keys are sanitized, and the span attribute key goes through spanAttrKey.
+func (l *Eotel) appendField(f Field) {
+	f.zap.Key = sanitizeKey(f.zap.Key)
+	if rd := activeRedactor.Load(); rd != nil {
+		if rd.sensitiveKey(f.zap.Key) {
+			f = F(f.zap.Key, rd.replacement)
+		} else if f.zap.Type == zapcore.StringType {
+			if v := rd.scrub(f.zap.String); v != f.zap.String {
+				f = F(f.zap.Key, v)
+			}
+		}
+	}
+	truncated := false
+	if f.zap.Type == zapcore.StringType {
+		if v, cut := truncateValue(f.zap.String); cut {
+			f = F(f.zap.Key, v)
+			truncated = true
+		}
+	}
+	f.attr.Key = attribute.Key(spanAttrKey(f.zap.Key))
+	attr, attrCut := limitAttr(f.attr)
+	l.fields = append(l.fields, f.zap)
+	l.attrs = append(l.attrs, attr)
+	if truncated {
+		l.fields = append(l.fields, zap.Bool(f.zap.Key+"_truncated", true))
+	}
+	if truncated || attrCut {
+		l.attrs = append(l.attrs, attribute.Bool(string(attr.Key)+"_truncated", true))
+	}
+}
+
+func (l *Eotel) withZapFields(fields ...zap.Field) *Eotel {
+	for _, f := range fields {
+		if f.Key == "" {
+			continue
+		}
+		l.appendField(Field{f, zapFieldAttr(f)})
+	}
+	return l
+}
+
+// Field is a pre-built log field carrying both its zap and span attribute
+// representation, so WithFieldsTyped does no reflection at call time.
+type Field struct {
+	zap  zap.Field
+	attr attribute.KeyValue
+}
+
+func (f Field) Key() string {
+	return f.zap.Key
+}
+
+// FieldValue lists the types F accepts, so a field of any other type fails
+// to compile instead of being logged through reflection. Errors and
+// fmt.Stringers have their own constructors, ErrField and StringerField.
+type FieldValue interface {
+	string | bool |
+		int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 |
+		time.Duration | time.Time |
+		[]string | []int | []int64 | []float64 | []bool
+}
+
+func F[T FieldValue](key string, v T) Field {
+	switch x := any(v).(type) {
+	case string:
+		return Field{zap.String(key, x), attribute.String(key, x)}
+	case bool:
+		return Field{zap.Bool(key, x), attribute.Bool(key, x)}
+	case int:
+		return Field{zap.Int(key, x), attribute.Int(key, x)}
+	case int8:
+		return Field{zap.Int8(key, x), attribute.Int64(key, int64(x))}
+	case int16:
+		return Field{zap.Int16(key, x), attribute.Int64(key, int64(x))}
+	case int32:
+		return Field{zap.Int32(key, x), attribute.Int64(key, int64(x))}
+	case int64:
+		return Field{zap.Int64(key, x), attribute.Int64(key, x)}
+	case uint:
+		return Field{zap.Uint(key, x), uintAttr(key, uint64(x))}
+	case uint8:
+		return Field{zap.Uint8(key, x), attribute.Int64(key, int64(x))}
+	case uint16:
+		return Field{zap.Uint16(key, x), attribute.Int64(key, int64(x))}
+	case uint32:
+		return Field{zap.Uint32(key, x), attribute.Int64(key, int64(x))}
+	case uint64:
+		return Field{zap.Uint64(key, x), uintAttr(key, x)}
+	case float32:
+		return Field{zap.Float32(key, x), attribute.Float64(key, float64(x))}
+	case float64:
+		return Field{zap.Float64(key, x), attribute.Float64(key, x)}
+	case time.Duration:
+		return Field{zap.Duration(key, x), attribute.Float64(key, float64(x)/float64(time.Millisecond))}
+	case time.Time:
+		return Field{zap.Time(key, x), attribute.String(key, x.Format(time.RFC3339Nano))}
+	case []string:
+		return Field{zap.Strings(key, x), attribute.StringSlice(key, x)}
+	case []int:
+		return Field{zap.Ints(key, x), attribute.IntSlice(key, x)}
+	case []int64:
+		return Field{zap.Int64s(key, x), attribute.Int64Slice(key, x)}
+	case []float64:
+		return Field{zap.Float64s(key, x), attribute.Float64Slice(key, x)}
+	case []bool:
+		return Field{zap.Bools(key, x), attribute.BoolSlice(key, x)}
+	}
+	// FieldValue has a case above for each of its types.
+	panic(fmt.Sprintf("eotel: F: unhandled field type %T", v))
+}
+
+// uintAttr keeps unsigned values past the int64 range exact by sending them
+// as strings.
+func uintAttr(key string, v uint64) attribute.KeyValue {
+	if v > math.MaxInt64 {
+		return attribute.String(key, strconv.FormatUint(v, 10))
+	}
+	return attribute.Int64(key, int64(v))
+}
+
+// ErrField is the Field of err, logged under key.
+func ErrField(key string, err error) Field {
+	if err == nil {
+		return Field{zap.Skip(), attribute.KeyValue{}}
+	}
+	return Field{zap.NamedError(key, err), attribute.String(key, err.Error())}
+}
+
+// StringerField is the Field of v's String method.
+func StringerField(key string, v fmt.Stringer) Field {
+	s := v.String()
+	return Field{zap.String(key, s), attribute.String(key, s)}
+}
+
+// anyField is F for values only known at run time, as in WithField: types
+// outside FieldValue are logged through zap.Any and stringified on the span.
+func anyField(key string, v any) Field {
+	switch x := v.(type) {
+	case string:
+		return F(key, x)
+	case bool:
+		return F(key, x)
+	case int:
+		return F(key, x)
+	case int8:
+		return F(key, x)
+	case int16:
+		return F(key, x)
+	case int32:
+		return F(key, x)
+	case int64:
+		return F(key, x)
+	case uint:
+		return F(key, x)
+	case uint8:
+		return F(key, x)
+	case uint16:
+		return F(key, x)
+	case uint32:
+		return F(key, x)
+	case uint64:
+		return F(key, x)
+	case float32:
+		return F(key, x)
+	case float64:
+		return F(key, x)
+	case time.Duration:
+		return F(key, x)
+	case time.Time:
+		return F(key, x)
+	case []string:
+		return F(key, x)
+	case []int:
+		return F(key, x)
+	case []int64:
+		return F(key, x)
+	case []float64:
+		return F(key, x)
+	case []bool:
+		return F(key, x)
+	case error:
+		return ErrField(key, x)
+	case fmt.Stringer:
+		return StringerField(key, x)
+	}
+	return Field{zap.Any(key, v), attribute.String(key, fmt.Sprintf("%v", v))}
+}
+
+func (l *Eotel) WithFieldsTyped(fields ...Field) *Eotel {
+	if l == nil {
+		return Noop("WithFieldsTyped")
+	}
+	cp := l.clone()
+	for _, f := range fields {
+		if f.zap.Key == "" {
+			continue
+		}
+		cp.appendField(f)
+	}
+	return cp
+}
+
+func (l *Eotel) WithInt(key string, v int) *Eotel {
+	return l.WithFieldsTyped(F(key, v))
+}
+
+func (l *Eotel) WithInt64(key string, v int64) *Eotel {
+	return l.WithFieldsTyped(F(key, v))
+}
+
+func (l *Eotel) WithFloat(key string, v float64) *Eotel {
+	return l.WithFieldsTyped(F(key, v))
+}
+
+func (l *Eotel) WithBool(key string, v bool) *Eotel {
+	return l.WithFieldsTyped(F(key, v))
+}
+
+func (l *Eotel) WithDuration(key string, v time.Duration) *Eotel {
+	return l.WithFieldsTyped(F(key, v))
+}
+
+func (l *Eotel) WithTime(key string, v time.Time) *Eotel {
+	return l.WithFieldsTyped(F(key, v))
+}
@@ -0,0 +1,69 @@
+package eotel
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+type stringerID int
+
+func (id stringerID) String() string { return "id-7" }
+
+func TestTypedFields(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		field    Field
+		zapType  zapcore.FieldType
+		attrType attribute.Type
+	}{
+		{"string", F("k", "v"), zapcore.StringType, attribute.STRING},
+		{"bool", F("k", true), zapcore.BoolType, attribute.BOOL},
+		{"int", F("k", 1), zapcore.Int64Type, attribute.INT64},
+		{"int8", F("k", int8(1)), zapcore.Int8Type, attribute.INT64},
+		{"int16", F("k", int16(1)), zapcore.Int16Type, attribute.INT64},
+		{"int32", F("k", int32(1)), zapcore.Int32Type, attribute.INT64},
+		{"int64", F("k", int64(1)), zapcore.Int64Type, attribute.INT64},
+		{"uint", F("k", uint(1)), zapcore.Uint64Type, attribute.INT64},
+		{"uint8", F("k", uint8(1)), zapcore.Uint8Type, attribute.INT64},
+		{"uint16", F("k", uint16(1)), zapcore.Uint16Type, attribute.INT64},
+		{"uint32", F("k", uint32(1)), zapcore.Uint32Type, attribute.INT64},
+		{"uint64", F("k", uint64(1)), zapcore.Uint64Type, attribute.INT64},
+		{"uint64 past int64", F("k", uint64(math.MaxUint64)), zapcore.Uint64Type, attribute.STRING},
+		{"float32", F("k", float32(1.5)), zapcore.Float32Type, attribute.FLOAT64},
+		{"float64", F("k", 1.5), zapcore.Float64Type, attribute.FLOAT64},
+		{"duration", F("k", time.Second), zapcore.DurationType, attribute.FLOAT64},
+		{"time", F("k", now), zapcore.TimeType, attribute.STRING},
+		{"strings", F("k", []string{"a"}), zapcore.ArrayMarshalerType, attribute.STRINGSLICE},
+		{"ints", F("k", []int{1}), zapcore.ArrayMarshalerType, attribute.INT64SLICE},
+		{"int64s", F("k", []int64{1}), zapcore.ArrayMarshalerType, attribute.INT64SLICE},
+		{"float64s", F("k", []float64{1}), zapcore.ArrayMarshalerType, attribute.FLOAT64SLICE},
+		{"bools", F("k", []bool{true}), zapcore.ArrayMarshalerType, attribute.BOOLSLICE},
+		{"error", ErrField("k", errors.New("boom")), zapcore.ErrorType, attribute.STRING},
+		{"stringer", StringerField("k", stringerID(7)), zapcore.StringType, attribute.STRING},
+		{"any stringer", anyField("k", stringerID(7)), zapcore.StringType, attribute.STRING},
+		{"any other", anyField("k", struct{ A int }{1}), zapcore.ReflectType, attribute.STRING},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.field.Key() != "k" {
+				t.Errorf("key = %q, want k", tt.field.Key())
+			}
+			if tt.field.zap.Type != tt.zapType {
+				t.Errorf("zap type = %v, want %v", tt.field.zap.Type, tt.zapType)
+			}
+			if got := tt.field.attr.Value.Type(); got != tt.attrType {
+				t.Errorf("attribute type = %v, want %v", got, tt.attrType)
+			}
+		})
+	}
+
+	if f := ErrField("k", nil); f.Key() != "" {
+		t.Errorf("nil error field has key %q, want it skipped", f.Key())
+	}
+}
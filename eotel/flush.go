@@ -0,0 +1,56 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const flushOnErrorTimeout = 5 * time.Second
+
+// flushForLevel pushes pending telemetry right away for error entries when
+// Config.FlushOnError is set, in the background. Fatal always flushes
+// synchronously, see fatal.
+func flushForLevel(level string) {
+	if !currentConfig().FlushOnError {
+		return
+	}
+	switch level {
+	case "error":
+		goTracked(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), flushOnErrorTimeout)
+			defer cancel()
+			_ = flushAll(ctx)
+		})
+	}
+}
+
+// flushAll forces every active pipeline to export what it has buffered,
+// without shutting anything down.
+func flushAll(ctx context.Context) error {
+	var errs []error
+	if err := drainLoki(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if lp := globalLoggerProvider; lp != nil {
+		if err := lp.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if tp := globalTracerProvider; tp != nil {
+		if err := tp.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if Enabled(SignalSentry) {
+		if err := drainSentry(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		timeout := timeoutOr(currentConfig().ExporterTimeouts.Sentry, defaultSentryTimeout)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+			timeout = time.Until(deadline)
+		}
+		flushSentry(timeout)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,38 @@
+package eotel
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Package-level logging for code that only carries a context.Context. The
+// logger injected by the server middlewares (or Inject) is resolved from
+// ctx, falling back to a new logger named "eotel" on ctx, so entries from
+// background work are still written; extra fields apply to this entry only.
+
+func Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	fromContextWith(ctx, fields).Debug(msg)
+}
+
+func Info(ctx context.Context, msg string, fields ...zap.Field) {
+	fromContextWith(ctx, fields).Info(msg)
+}
+
+func Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	fromContextWith(ctx, fields).Warn(msg)
+}
+
+func Error(ctx context.Context, err error, msg string, fields ...zap.Field) {
+	fromContextWith(ctx, fields).WithError(err).Error(msg)
+}
+
+func fromContextWith(ctx context.Context, fields []zap.Field) *Eotel {
+	l, ok := ctx.Value(loggerCtxKey{}).(*Eotel)
+	if ok && l != nil {
+		l = l.clone()
+	} else {
+		l = New(ctx, "eotel")
+	}
+	return l.withZapFields(fields...)
+}
@@ -0,0 +1,98 @@
+package eotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// Counter is a cached handle on a counter bound to the logger's context.
+type Counter struct {
+	ctx  context.Context
+	c    metric.Int64Counter
+	l    *Eotel
+	name string
+}
+
+func (c Counter) Add(n int64, attrs ...attribute.KeyValue) {
+	c.c.Add(c.ctx, n, metric.WithAttributes(labelGuard.guard(c.name, c.l.serviceAttrs(attrs))...))
+	captureMetric(c.ctx, c.name, "counter", float64(n), attrs)
+}
+
+// Histogram is a cached handle on a histogram bound to the logger's context.
+type Histogram struct {
+	ctx  context.Context
+	h    metric.Float64Histogram
+	l    *Eotel
+	name string
+}
+
+func (h Histogram) Record(v float64, attrs ...attribute.KeyValue) {
+	h.h.Record(h.ctx, v, metric.WithAttributes(labelGuard.guard(h.name, h.l.serviceAttrs(attrs))...))
+	captureMetric(h.ctx, h.name, "histogram", v, attrs)
+}
+
+// Gauge is a cached handle on a gauge bound to the logger's context.
+type Gauge struct {
+	ctx  context.Context
+	g    metric.Float64Gauge
+	l    *Eotel
+	name string
+}
+
+func (g Gauge) Set(v float64, attrs ...attribute.KeyValue) {
+	g.g.Record(g.ctx, v, metric.WithAttributes(labelGuard.guard(g.name, g.l.serviceAttrs(attrs))...))
+	captureMetric(g.ctx, g.name, "gauge", v, attrs)
+}
+
+// metricCtx keeps metric recording alive after the request context is
+// cancelled while preserving its values (baggage, span for exemplars).
+func (l *Eotel) metricCtx() context.Context {
+	if l.ctx == nil {
+		return context.Background()
+	}
+	// A context that is never cancelled needs no wrapping.
+	if l.ctx.Done() == nil {
+		return l.ctx
+	}
+	return context.WithoutCancel(l.ctx)
+}
+
+// Counter returns the counter called name, creating it on first use. Declare
+// it with Int64Counter beforehand to give it a unit and description.
+func (l *Eotel) Counter(name string) Counter {
+	if l == nil || l.meter == nil {
+		return Counter{ctx: context.Background(), c: noop.Int64Counter{}, l: Noop(name), name: name}
+	}
+	c, err := cachedCounter(l.registry(), l.meter, name)
+	if err != nil {
+		c = noop.Int64Counter{}
+	}
+	return Counter{ctx: l.metricCtx(), c: c, l: l, name: name}
+}
+
+// Histogram returns the histogram called name; see Counter.
+func (l *Eotel) Histogram(name string) Histogram {
+	if l == nil || l.meter == nil {
+		return Histogram{ctx: context.Background(), h: noop.Float64Histogram{}, l: Noop(name), name: name}
+	}
+	h, err := cachedHistogram(l.registry(), l.meter, name)
+	if err != nil {
+		h = noop.Float64Histogram{}
+	}
+	return Histogram{ctx: l.metricCtx(), h: h, l: l, name: name}
+}
+
+// Gauge returns the gauge called name; see Counter.
+func (l *Eotel) Gauge(name string) Gauge {
+	if l == nil || l.meter == nil {
+		return Gauge{ctx: context.Background(), g: noop.Float64Gauge{}, l: Noop(name), name: name}
+	}
+	g, err := cachedGauge(l.registry(), l.meter, name)
+	if err != nil {
+		g = noop.Float64Gauge{}
+	}
+	return Gauge{ctx: l.metricCtx(), g: g, l: l, name: name}
+}
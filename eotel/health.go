@@ -0,0 +1,170 @@
+package eotel
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// HealthReport is the state of each started telemetry pipeline, as returned
+// by Health.
+type HealthReport struct {
+	Healthy   bool             `json:"healthy"`
+	Pipelines []PipelineHealth `json:"pipelines"`
+}
+
+// PipelineHealth describes one pipeline. It is unhealthy when its last
+// export failed, or when its queue is full.
+type PipelineHealth struct {
+	Name          string    `json:"name"`
+	Healthy       bool      `json:"healthy"`
+	QueueDepth    int       `json:"queue_depth,omitempty"`
+	QueueCapacity int       `json:"queue_capacity,omitempty"`
+	Dropped       int64     `json:"dropped"`
+	CircuitOpen   bool      `json:"circuit_open,omitempty"`
+	LastSuccess   time.Time `json:"last_success,omitzero"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorAt   time.Time `json:"last_error_at,omitzero"`
+}
+
+// pipelineStatus tracks the outcome of a pipeline's exports for Health.
+type pipelineStatus struct {
+	lastSuccess atomic.Int64
+	lastError   atomic.Pointer[statusError]
+	dropped     atomic.Int64
+	circuit     atomic.Pointer[breaker]
+}
+
+type statusError struct {
+	msg string
+	at  time.Time
+}
+
+func (s *pipelineStatus) record(err error) {
+	if err != nil {
+		s.lastError.Store(&statusError{msg: err.Error(), at: time.Now()})
+		return
+	}
+	s.lastSuccess.Store(time.Now().UnixNano())
+}
+
+func (s *pipelineStatus) drop(n int) {
+	s.dropped.Add(int64(n))
+}
+
+func (s *pipelineStatus) health(name string) PipelineHealth {
+	h := PipelineHealth{Name: name, Healthy: true, Dropped: s.dropped.Load()}
+	if ns := s.lastSuccess.Load(); ns > 0 {
+		h.LastSuccess = time.Unix(0, ns)
+	}
+	if e := s.lastError.Load(); e != nil {
+		h.LastError, h.LastErrorAt = e.msg, e.at
+		h.Healthy = e.at.Before(h.LastSuccess)
+	}
+	if s.circuit.Load().open() {
+		h.CircuitOpen, h.Healthy = true, false
+	}
+	return h
+}
+
+var (
+	otlpTraceStatus  pipelineStatus
+	otlpMetricStatus pipelineStatus
+	otlpLogStatus    pipelineStatus
+	lokiStatus       pipelineStatus
+	sentryStatus     pipelineStatus
+	collectorStatus  pipelineStatus
+)
+
+// Health reports the state of every pipeline started by InitEOTEL: the last
+// OTLP export results, the Loki queue and last push error, Sentry client
+// availability and queue, the collector health check, and how many items
+// each dropped. Counters are cumulative over the process lifetime.
+func Health(ctx context.Context) HealthReport {
+	var pipelines []PipelineHealth
+	if Enabled(SignalTracing) && globalTracerProvider != nil {
+		pipelines = append(pipelines, otlpTraceStatus.health("otlp_traces"))
+	}
+	if Enabled(SignalMetrics) && globalMeterProvider != nil {
+		pipelines = append(pipelines, otlpMetricStatus.health("otlp_metrics"))
+	}
+	if Enabled(SignalOTLPLogs) {
+		pipelines = append(pipelines, otlpLogStatus.health("otlp_logs"))
+	}
+	if Enabled(SignalLoki) {
+		h := lokiStatus.health("loki")
+		if p := lokiClient.Load(); p != nil {
+			h.QueueDepth, h.QueueCapacity = p.queue.len(), p.queue.cap()
+			h.Healthy = h.Healthy && h.QueueDepth < h.QueueCapacity
+		}
+		pipelines = append(pipelines, h)
+	}
+	if Enabled(SignalSentry) {
+		h := sentryStatus.health("sentry")
+		if w := sentryClient.Load(); w != nil {
+			h.QueueDepth, h.QueueCapacity = w.queue.len(), w.queue.cap()
+			h.Healthy = h.Healthy && h.QueueDepth < h.QueueCapacity
+		} else {
+			h.Healthy = false
+			h.LastError = "sentry client not initialised"
+		}
+		pipelines = append(pipelines, h)
+	}
+	if currentConfig().CollectorHealthURL != "" {
+		pipelines = append(pipelines, collectorStatus.health("collector"))
+	}
+
+	report := HealthReport{Healthy: true, Pipelines: pipelines}
+	for _, p := range pipelines {
+		report.Healthy = report.Healthy && p.Healthy
+	}
+	return report
+}
+
+// statusSpanExporter records the outcome of each export in status.
+type statusSpanExporter struct {
+	sdktrace.SpanExporter
+	status *pipelineStatus
+}
+
+func (e statusSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.status.record(err)
+	if err != nil {
+		e.status.drop(len(spans))
+	}
+	return err
+}
+
+type statusMetricExporter struct {
+	sdkmetric.Exporter
+	status *pipelineStatus
+}
+
+func (e statusMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.status.record(err)
+	if err != nil {
+		e.status.drop(1)
+	}
+	return err
+}
+
+type statusLogExporter struct {
+	sdklog.Exporter
+	status *pipelineStatus
+}
+
+func (e statusLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.status.record(err)
+	if err != nil {
+		e.status.drop(len(records))
+	}
+	return err
+}
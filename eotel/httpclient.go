@@ -0,0 +1,114 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPClient returns a copy of base (or of http.DefaultClient when nil) whose
+// transport is wrapped by Transport.
+func HTTPClient(base *http.Client) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	c := *base
+	c.Transport = Transport(base.Transport)
+	return &c
+}
+
+// Transport wraps an http.RoundTripper with a client span per request, trace
+// context injection into the outgoing headers, duration/count metrics and
+// logging through the Eotel found in the request context.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+
+	once     sync.Once
+	requests metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		m := getMeter()
+		t.requests, _ = m.Int64Counter("http_client_requests_total")
+		t.duration, _ = m.Float64Histogram("http_client_duration_ms", metric.WithUnit("ms"))
+	})
+
+	start := time.Now()
+	ctx, span := getTracer().Start(req.Context(), fmt.Sprintf("HTTP %s", req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	injectIdempotencyKey(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	durationMs := time.Since(start).Seconds() * 1000
+	attrs := metric.WithAttributes(labelGuard.guard("http_client_requests_total", []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Hostname()),
+		attribute.Int("http.response.status_code", status),
+	})...)
+	mctx := context.WithoutCancel(ctx)
+	t.requests.Add(mctx, 1, attrs)
+	t.duration.Record(mctx, durationMs, attrs)
+
+	log := FromContext(ctx, "http.client")
+	if currentConfig().LegacyHTTPFieldNames {
+		log = log.WithField("http.method", req.Method).
+			WithField("http.url", req.URL.Redacted()).
+			WithField("http.status", status)
+	} else {
+		log = log.WithField("http.request.method", req.Method).
+			WithField("url.full", req.URL.Redacted()).
+			WithField("http.response.status_code", status)
+	}
+	log = log.WithField("duration_ms", durationMs)
+
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		if !markCanceled(span, err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		log.WithError(err).Error("outbound request failed")
+	case status >= http.StatusInternalServerError:
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		span.SetStatus(codes.Error, http.StatusText(status))
+		log.Warn("outbound request returned server error")
+	default:
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		log.Debug("outbound request completed")
+	}
+	return resp, err
+}
@@ -0,0 +1,164 @@
+package eotel
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPMiddleware is the net/http counterpart of Middleware, usable with chi,
+// gorilla/mux, echo (via echo.WrapMiddleware) or a plain ServeMux.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return NewHTTPMiddleware(currentConfig().ServiceName)(next)
+}
+
+// NewHTTPMiddleware returns a middleware whose request loggers are named name,
+// in the func(http.Handler) http.Handler shape expected by chi's Use.
+func NewHTTPMiddleware(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ensureInit("HTTPMiddleware")
+			start := time.Now()
+			reqCtx := r.Context()
+			conf := currentConfig()
+			ctx := otel.GetTextMapPropagator().Extract(reqCtx, propagation.HeaderCarrier(r.Header))
+			if conf.SamplingPriority != nil {
+				ctx = WithSamplingPriority(ctx, conf.SamplingPriority(r))
+			}
+
+			var captureID trace.TraceID
+			defer func() {
+				if captureID.IsValid() {
+					finishCapture(captureID)
+				}
+			}()
+
+			// Named after the method alone until the route is known: raw
+			// paths would give every URL its own span name.
+			ctx, span := startServerSpan(ctx, false, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(httpServerAttrs(r, remoteHost(r))...))
+			defer span.End()
+
+			recordQueueTime(ctx, span, r.Header, start)
+
+			if conf.CaptureHeader != "" && captureRequested(r.Header.Get(conf.CaptureHeader)) {
+				captureID = span.SpanContext().TraceID()
+				startCapture(captureID)
+			}
+
+			ctx = withSentryRequest(ctx, r, remoteHost(r))
+			ctx = WithRequestScope(ctx)
+
+			logger := Safe(New(ctx, name)).
+				TraceName(name).
+				WithHTTPRequest(r, remoteHost(r))
+			logger = bindRequestID(w.Header(), span, logger, requestIDFor(r.Header))
+			ctx, logger = bindIdempotencyKey(ctx, r.Header, span, logger, r.Pattern)
+			// Runs before span.End; the request's loggers all share this
+			// logger's aggregator.
+			defer logger.FlushAggregates()
+
+			r = r.WithContext(Inject(ctx, logger))
+			bodies := tapRequest(nil, r)
+			if bodies != nil {
+				w = &tapResponseWriter{ResponseWriter: w, tap: bodies.resp}
+			}
+			rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			// Deferred before the panic handler so it runs after it.
+			if sc := span.SpanContext(); conf.TraceAwareLogs && sc.IsValid() && !sc.IsSampled() {
+				bufferID := span.SpanContext().TraceID()
+				startLogBuffer(bufferID)
+				defer func() {
+					finishLogBuffer(bufferID, rw.status >= http.StatusInternalServerError)
+				}()
+			}
+
+			// ServeMux records the matched pattern on the request.
+			setRoute := func() {
+				if r.Pattern != "" {
+					span.SetName(r.Pattern)
+					span.SetAttributes(attribute.String("http.route", r.Pattern))
+				}
+			}
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					setRoute()
+					route := r.Pattern
+					if route == "" {
+						route = "unmatched"
+					}
+					err := recoverRequest(r.Context(), rec, route)
+					if !rw.wroteHeader {
+						writePanicResponse(rw, r, rec)
+					}
+					span.SetAttributes(attribute.Int("http.response.status_code", rw.status))
+					span.SetStatus(codes.Error, err.Error())
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+
+			setRoute()
+			span.SetAttributes(attribute.Int("http.response.status_code", rw.status))
+			if rw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rw.status))
+			}
+			logger = tagAborted(span, logger, abortedBy(reqCtx, r.Context()))
+			checkSLA(span, logger, r.Method, r.Pattern, time.Since(start))
+			observeRouteSLO(r.Context(), r.Method, r.Pattern, time.Since(start), rw.status)
+			logger = bodies.attach(span, logger, r, rw.Header(), rw.status)
+
+			logger.Info("request completed")
+		})
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Flush and Hijack let handlers type-assert http.Flusher and http.Hijacker,
+// as streaming and WebSocket handlers do; both reach the original writer
+// through any wrappers in between.
+func (w *statusRecorder) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the original writer's other
+// optional methods.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,42 @@
+package eotel
+
+import (
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// httpServerAttrs are the semconv request attributes set on server spans.
+func httpServerAttrs(r *http.Request, clientAddress string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("http.request.method", r.Method),
+		attribute.String("url.path", r.URL.Path),
+		attribute.String("client.address", clientAddress),
+		attribute.String("user_agent.original", r.UserAgent()),
+	}
+}
+
+// WithHTTPRequest adds the request method, path, client address and user
+// agent as fields named after the OTel HTTP semantic conventions, or after
+// the pre-semconv names (method, path, ip, ua) with Config.LegacyHTTPFieldNames.
+func (l *Eotel) WithHTTPRequest(r *http.Request, clientAddress string) *Eotel {
+	if currentConfig().LegacyHTTPFieldNames {
+		return l.WithField("method", r.Method).
+			WithField("path", r.URL.Path).
+			WithField("ip", clientAddress).
+			WithField("ua", r.UserAgent())
+	}
+	return l.WithField("http.request.method", r.Method).
+		WithField("url.path", r.URL.Path).
+		WithField("client.address", clientAddress).
+		WithField("user_agent.original", r.UserAgent())
+}
+
+// remoteHost strips the port from r.RemoteAddr.
+func remoteHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,139 @@
+package eotel
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultIdempotencyHeader = "Idempotency-Key"
+	defaultIdempotencyWindow = 10 * time.Minute
+	idempotencyBaggageKey    = "idempotency_key"
+	maxIdempotencyKeys       = 10000
+)
+
+// WithIdempotencyKey groups an operation's retries: the key is a field on
+// every log line, travels as baggage so every span started under Ctx() -
+// here and in downstream services - carries idempotency.key, and Transport
+// sends it in the Idempotency-Key header. The server middlewares read that
+// header back and flag repeated keys as duplicates.
+func (l *Eotel) WithIdempotencyKey(key string) *Eotel {
+	if l == nil {
+		return Noop("WithIdempotencyKey")
+	}
+	if key == "" || len(key) > maxRequestIDLength {
+		return l
+	}
+	cp := l.WithBaggage(idempotencyBaggageKey, key)
+	if cp == l {
+		cp = l.clone()
+	}
+	cp.addField("idempotency.key", key)
+	return cp
+}
+
+func idempotencyKey(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	return baggage.FromContext(ctx).Member(idempotencyBaggageKey).Value()
+}
+
+// injectIdempotencyKey sets the header on an outgoing request unless the
+// caller already did.
+func injectIdempotencyKey(ctx context.Context, h http.Header) {
+	name := headerName(currentConfig().IdempotencyHeader, defaultIdempotencyHeader)
+	if name == "" || h.Get(name) != "" {
+		return
+	}
+	if key := idempotencyKey(ctx); key != "" {
+		h.Set(name, key)
+	}
+}
+
+// bindIdempotencyKey tags the server span and logger with the request's
+// idempotency key, from the header or incoming baggage, and marks keys seen
+// within Config.IdempotencyWindow as duplicates.
+func bindIdempotencyKey(ctx context.Context, h http.Header, span trace.Span, logger *Eotel, route string) (context.Context, *Eotel) {
+	key := idempotencyKey(ctx)
+	if name := headerName(currentConfig().IdempotencyHeader, defaultIdempotencyHeader); name != "" {
+		if v := h.Get(name); v != "" {
+			key = v
+		}
+	}
+	if key == "" || len(key) > maxRequestIDLength {
+		return ctx, logger
+	}
+	logger = logger.WithIdempotencyKey(key)
+	span.SetAttributes(attribute.String("idempotency.key", key))
+	if n := idempotencySeen.observe(key); n > 1 {
+		span.SetAttributes(attribute.Bool("idempotency.duplicate", true), attribute.Int("idempotency.attempt", n))
+		logger = logger.WithField("idempotency.duplicate", true).WithField("idempotency.attempt", n)
+		idempotencyDuplicate(ctx, route)
+	}
+	return logger.ctx, logger
+}
+
+var (
+	idempotencyOnce       sync.Once
+	idempotencyDuplicates metric.Int64Counter
+)
+
+func idempotencyDuplicate(ctx context.Context, route string) {
+	idempotencyOnce.Do(func() {
+		idempotencyDuplicates, _ = getMeter().Int64Counter("eotel.idempotency.duplicates",
+			metric.WithUnit("{request}"),
+			metric.WithDescription("Requests repeating an idempotency key seen within the window."))
+	})
+	if idempotencyDuplicates != nil {
+		idempotencyDuplicates.Add(context.WithoutCancel(ctx), 1,
+			metric.WithAttributes(attribute.String("http.route", route)))
+	}
+}
+
+var idempotencySeen = &keyWindow{keys: map[string]*keySeen{}}
+
+type keySeen struct {
+	until time.Time
+	count int
+}
+
+// keyWindow counts how often each key arrived within the window, holding at
+// most maxIdempotencyKeys keys.
+type keyWindow struct {
+	mu   sync.Mutex
+	keys map[string]*keySeen
+}
+
+func (w *keyWindow) observe(key string) int {
+	window := currentConfig().IdempotencyWindow
+	if window <= 0 {
+		window = defaultIdempotencyWindow
+	}
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if s, ok := w.keys[key]; ok && now.Before(s.until) {
+		s.count++
+		return s.count
+	}
+	if len(w.keys) >= maxIdempotencyKeys {
+		for k, s := range w.keys {
+			if now.After(s.until) {
+				delete(w.keys, k)
+			}
+		}
+		if len(w.keys) >= maxIdempotencyKeys {
+			return 1
+		}
+	}
+	w.keys[key] = &keySeen{until: now.Add(window), count: 1}
+	return 1
+}
@@ -0,0 +1,373 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var globalTracer trace.Tracer
+var globalMeter metric.Meter
+var globalLogger *zap.Logger
+var globalTracerProvider *sdktrace.TracerProvider
+var globalMeterProvider *sdkmetric.MeterProvider
+
+func getTracer() trace.Tracer {
+	if globalTracer != nil {
+		return globalTracer
+	}
+	return otel.Tracer(currentConfig().ServiceName)
+}
+
+func getMeter() metric.Meter {
+	if globalMeter != nil {
+		return globalMeter
+	}
+	return otel.Meter(currentConfig().ServiceName)
+}
+
+// TracerProvider returns the provider eotel traces through: Config.TracerProvider
+// when one was injected, otherwise the global provider that InitEOTEL installs.
+// Integrations default to it so their spans follow an injected provider.
+func TracerProvider() trace.TracerProvider {
+	cfg := currentConfig()
+	if cfg.TracerProvider != nil {
+		return cfg.TracerProvider
+	}
+	return otel.GetTracerProvider()
+}
+
+// MeterProvider is the metric counterpart of TracerProvider.
+func MeterProvider() metric.MeterProvider {
+	cfg := currentConfig()
+	if cfg.MeterProvider != nil {
+		return cfg.MeterProvider
+	}
+	return otel.GetMeterProvider()
+}
+
+func getLogger() *zap.Logger {
+	if globalLogger != nil {
+		return globalLogger
+	}
+	return zap.L()
+}
+
+// InitEOTEL sets up the pipelines enabled in cfg and returns the function
+// that flushes and stops them. A subsystem that fails to start is reported as
+// a *SubsystemError.
+func InitEOTEL(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	return initEOTEL(ctx, cfg, &InitResult{})
+}
+
+func initEOTEL(ctx context.Context, cfg Config, result *InitResult) (_ func(context.Context) error, err error) {
+	if cfg.Logger == nil && cfg.Log.enabled() {
+		logger, err := newLogger(cfg.Log)
+		if err != nil {
+			return nil, subsystemErr(SubsystemLogger, fmt.Errorf("logger: %w", err))
+		}
+		cfg.Logger = logger
+	}
+	if cfg.DevMode {
+		var err error
+		if cfg, err = devConfig(cfg); err != nil {
+			return nil, subsystemErr(SubsystemConfig, fmt.Errorf("dev mode: %w", err))
+		}
+	}
+
+	rd, err := newRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, subsystemErr(SubsystemConfig, fmt.Errorf("redaction: %w", err))
+	}
+	if cfg.MinLevel != "" {
+		if _, err := zapcore.ParseLevel(cfg.MinLevel); err != nil {
+			return nil, subsystemErr(SubsystemConfig, fmt.Errorf("min level: %w", err))
+		}
+	}
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, subsystemErr(SubsystemConfig, fmt.Errorf("resource.New: %w", err))
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+	sink, err := newNativeSink(cfg)
+	if err != nil {
+		return nil, subsystemErr(SubsystemLogger, fmt.Errorf("native sink: %w", err))
+	}
+	if sink != nil {
+		logger = teeNativeSink(logger, sink)
+	}
+
+	// Everything is built into p and only installed once every step has
+	// succeeded; until then a failure stops what was started and leaves the
+	// running configuration untouched.
+	p := newPipelines()
+	defer func() {
+		if err != nil {
+			p.discard(context.WithoutCancel(ctx))
+		}
+	}()
+	if p.lastBreath, err = openLastBreath(cfg.LastBreathFile); err != nil {
+		return nil, subsystemErr(SubsystemLogger, fmt.Errorf("last breath file: %w", err))
+	}
+
+	active := map[Signal]bool{}
+
+	// Init metrics
+	meter := otel.GetMeterProvider().Meter(cfg.ServiceName)
+	if cfg.MeterProvider != nil {
+		meter = cfg.MeterProvider.Meter(cfg.ServiceName)
+		active[SignalMetrics] = true
+	} else if cfg.EnableMetrics || cfg.MetricsPushGatewayURL != "" {
+		if err := p.buildMetrics(ctx, cfg, res); err != nil {
+			return nil, err
+		}
+		meter = p.mp.Meter(cfg.ServiceName)
+		active[SignalMetrics] = true
+	}
+
+	var extra []Subsystem
+	if cfg.EnableRuntimeMetrics && active[SignalMetrics] {
+		mp := cfg.MeterProvider
+		if mp == nil {
+			mp = p.mp
+		}
+		if err := runtime.Start(runtime.WithMeterProvider(mp)); err != nil {
+			return nil, subsystemErr(SubsystemRuntimeMetrics, fmt.Errorf("runtime metrics: %w", err))
+		}
+		extra = append(extra, SubsystemRuntimeMetrics)
+	}
+
+	// Init tracing
+	tracer := otel.GetTracerProvider().Tracer(cfg.ServiceName)
+	if cfg.TracerProvider != nil {
+		tracer = cfg.TracerProvider.Tracer(cfg.ServiceName)
+		active[SignalTracing] = true
+	} else if cfg.EnableTracing {
+		if err := p.buildTracing(ctx, cfg, res, meter); err != nil {
+			return nil, err
+		}
+		if cfg.DevMode {
+			p.tp.RegisterSpanProcessor(devUIProcessor{})
+		}
+		if cfg.CaptureHeader != "" {
+			p.tp.RegisterSpanProcessor(captureProcessor{})
+		}
+		if cfg.EnableSelfMetrics {
+			p.tp.RegisterSpanProcessor(newSelfMetricsProcessor(meter))
+		}
+		tracer = p.tp.Tracer(cfg.ServiceName)
+		active[SignalTracing] = true
+	}
+
+	// Init OTLP logs
+	if cfg.EnableOTLPLogs {
+		if err := p.buildLogs(ctx, cfg, res, meter); err != nil {
+			return nil, err
+		}
+		active[SignalOTLPLogs] = true
+	}
+
+	// Init loki
+	if cfg.EnableLoki {
+		if p.loki, err = newLokiPusher(cfg, meter); err != nil {
+			return nil, subsystemErr(SubsystemLoki, err)
+		}
+		active[SignalLoki] = true
+	}
+
+	// Init sentry
+	if cfg.EnableSentry {
+		var backend SentryBackend
+		backend, err = newSentryBackend(cfg)
+		if err == nil {
+			p.sentry, err = newSentryWorker(cfg, backend, meter)
+		}
+		switch {
+		case err != nil && cfg.StrictInit:
+			return nil, subsystemErr(SubsystemSentry, fmt.Errorf("sentry: %w", err))
+		case err != nil:
+			result.fail(SubsystemSentry, err)
+			log.Printf("init Sentry error: %v", err)
+			err = nil
+		default:
+			active[SignalSentry] = true
+		}
+	}
+
+	if cfg.StartupTimeout > 0 {
+		err := waitCollector(ctx, cfg.StartupTimeout, p.collectorConns())
+		switch {
+		case err != nil && cfg.StrictInit:
+			return nil, subsystemErr(SubsystemCollector, err)
+		case err != nil:
+			result.fail(SubsystemCollector, err)
+			log.Printf("init collector: %v, continuing while it reconnects", err)
+		}
+	}
+
+	// initInstruments changes nothing when it fails, so it goes first.
+	if err := initInstruments(meter, cfg.LegacyMetricNames); err != nil {
+		return nil, subsystemErr(SubsystemMetrics, fmt.Errorf("instruments: %w", err))
+	}
+
+	// Every step succeeded: install the new configuration.
+	if cfg.StrictInit {
+		SetStrictInit(true)
+	}
+	prevCfg, reinit := *currentConfig(), initialized.Load()
+	snapshot := cfg
+	globalCfg.Store(&snapshot)
+	initialized.Store(true)
+	activeRedactor.Store(rd)
+	setLastBreath(p.lastBreath)
+	setStaticFields(cfg.StaticFields)
+	if cfg.MinLevel != "" {
+		_ = SetLevel(cfg.MinLevel)
+	}
+	globalLogger = logger
+
+	propagators := []propagation.TextMapPropagator{traceContext{}, propagation.Baggage{}}
+	if len(cfg.BaggageHeaders) > 0 {
+		propagators = append(propagators, newHeaderBaggage(cfg.BaggageHeaders))
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagators...))
+
+	reloadTraces, reloadMetrics, reloadLogs = p.traces, p.metrics, p.logs
+	if p.tp != nil {
+		closeSampler(traceSampler.set(p.sampler))
+		otel.SetTracerProvider(p.tp)
+		globalTracerProvider = p.tp
+		if active[SignalMetrics] {
+			registerAdaptiveMetrics(meter)
+		}
+	}
+	globalTracer = tracer
+	if p.mp != nil {
+		otel.SetMeterProvider(p.mp)
+		globalMeterProvider = p.mp
+	}
+	globalMeter = meter
+	if p.promHandler != nil {
+		promHandler.Store(&p.promHandler)
+	}
+	globalPusher = p.pusher
+	if p.lp != nil {
+		globalLoggerProvider = p.lp
+		otlpLogger = p.lp.Logger(cfg.ServiceName)
+	}
+	activeLogSampler.Store(newLogSampler(cfg.LogSampling))
+	if p.loki != nil {
+		p.loki.start()
+	}
+	if p.sentry != nil {
+		p.sentry.start()
+	}
+
+	var builtin []Exporter
+	if active[SignalLoki] {
+		builtin = append(builtin, LokiExporter{})
+	}
+	if active[SignalSentry] {
+		builtin = append(builtin, SentryExporter{})
+	}
+	setBuiltinExporters(builtin...)
+
+	for _, exp := range cfg.Exporters {
+		RegisterExporter(exp)
+	}
+
+	setActiveSignals(active)
+	result.started(active, extra...)
+
+	if reinit {
+		logConfigChanges(prevCfg, cfg)
+	}
+
+	stopUsageReport := func() {}
+	if cfg.EnableUsageReporting && cfg.UsageReportInterval > 0 {
+		stopUsageReport = startUsageReport(cfg.UsageReportInterval)
+	}
+
+	stopCollectorHealth := func() {}
+	if cfg.CollectorHealthURL != "" {
+		stopCollectorHealth = startCollectorHealth(cfg)
+	}
+
+	stopSignalToggles := func() {}
+	if cfg.SignalToggles {
+		stopSignalToggles = startSignalToggles()
+	}
+
+	isShutdown.Store(false)
+
+	tp, mp, stopPrometheus := p.tp, p.mp, p.stopPrometheus
+	if stopPrometheus == nil {
+		stopPrometheus = func(context.Context) error { return nil }
+	}
+
+	// Graceful shutdown function
+	return func(ctx context.Context) error {
+		defer isShutdown.Store(true)
+		stopUsageReport()
+		stopCollectorHealth()
+		stopSignalToggles()
+		var errs []error
+		if err := waitInflight(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		if err := drainLoki(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("loki drain: %w", err))
+		}
+		if lp := globalLoggerProvider; lp != nil {
+			if err := lp.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("logger provider: %w", err))
+			}
+		}
+		if tp != nil {
+			if err := tp.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("tracer provider: %w", err))
+			}
+			closeSampler(traceSampler.get())
+		}
+		if err := pushGateway(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("pushgateway: %w", err))
+		}
+		if err := stopPrometheus(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("prometheus server: %w", err))
+		}
+		if mp != nil {
+			if err := mp.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("meter provider: %w", err))
+			}
+		}
+		if cfg.EnableSentry {
+			if err := drainSentry(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("sentry drain: %w", err))
+			}
+			timeout := 2 * time.Second
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = time.Until(deadline)
+			}
+			if !flushSentry(timeout) {
+				errs = append(errs, errors.New("sentry: flush timed out"))
+			}
+		}
+		return errors.Join(errs...)
+	}, nil
+}
@@ -0,0 +1,74 @@
+package eotel
+
+import (
+	"context"
+	"sort"
+)
+
+// Subsystem names a part of the pipeline set up by InitEOTEL.
+type Subsystem string
+
+const (
+	SubsystemConfig         Subsystem = "config"
+	SubsystemLogger         Subsystem = "logger"
+	SubsystemTracing        Subsystem = Subsystem(SignalTracing)
+	SubsystemMetrics        Subsystem = Subsystem(SignalMetrics)
+	SubsystemRuntimeMetrics Subsystem = "runtime_metrics"
+	SubsystemOTLPLogs       Subsystem = Subsystem(SignalOTLPLogs)
+	SubsystemLoki           Subsystem = Subsystem(SignalLoki)
+	SubsystemSentry         Subsystem = Subsystem(SignalSentry)
+	SubsystemCollector      Subsystem = "collector"
+)
+
+// SubsystemError is the error InitEOTEL returns when a subsystem fails to
+// start; match it with errors.As to tell which one.
+type SubsystemError struct {
+	Subsystem Subsystem
+	Err       error
+}
+
+func (e *SubsystemError) Error() string { return e.Err.Error() }
+
+func (e *SubsystemError) Unwrap() error { return e.Err }
+
+func subsystemErr(s Subsystem, err error) error {
+	return &SubsystemError{Subsystem: s, Err: err}
+}
+
+// InitResult reports what InitWithResult set up. Without Config.StrictInit
+// a subsystem that can run degraded (Sentry) is recorded in Failed and init
+// carries on; with it, the failure is returned instead.
+type InitResult struct {
+	Started []Subsystem
+	Failed  map[Subsystem]error
+}
+
+// OK reports whether every enabled subsystem started.
+func (r InitResult) OK() bool {
+	return len(r.Failed) == 0
+}
+
+func (r *InitResult) fail(s Subsystem, err error) {
+	if r.Failed == nil {
+		r.Failed = map[Subsystem]error{}
+	}
+	r.Failed[s] = err
+}
+
+func (r *InitResult) started(active map[Signal]bool, extra ...Subsystem) {
+	for sig, on := range active {
+		if on {
+			r.Started = append(r.Started, Subsystem(sig))
+		}
+	}
+	r.Started = append(r.Started, extra...)
+	sort.Slice(r.Started, func(i, j int) bool { return r.Started[i] < r.Started[j] })
+}
+
+// InitWithResult is InitEOTEL also reporting which subsystems started, so a
+// deploy check can fail on a degraded pipeline even without StrictInit.
+func InitWithResult(ctx context.Context, cfg Config) (func(context.Context) error, InitResult, error) {
+	var result InitResult
+	shutdown, err := initEOTEL(ctx, cfg, &result)
+	return shutdown, result, err
+}
@@ -0,0 +1,216 @@
+package eotel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type instrumentKind string
+
+const (
+	kindInt64Counter     instrumentKind = "int64_counter"
+	kindFloat64Histogram instrumentKind = "float64_histogram"
+	kindFloat64Gauge     instrumentKind = "float64_gauge"
+)
+
+type instrumentSpec struct {
+	kind        instrumentKind
+	name        string
+	unit        string
+	description string
+}
+
+type cachedInstrument struct {
+	spec       instrumentSpec
+	instrument any
+}
+
+// instrumentRegistry caches instruments by name for one meter: the global
+// one, or a Provider's. A name can only be bound to one kind/unit/description;
+// redefining it differently is reported as an error instead of silently
+// creating a second stream.
+type instrumentRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]cachedInstrument
+}
+
+var instruments = newInstrumentRegistry()
+
+func newInstrumentRegistry() *instrumentRegistry {
+	return &instrumentRegistry{byName: map[string]cachedInstrument{}}
+}
+
+// reset drops the cached instruments, which belong to the previous meter.
+func (r *instrumentRegistry) reset() {
+	r.mu.Lock()
+	r.byName = map[string]cachedInstrument{}
+	r.mu.Unlock()
+}
+
+type InstrumentConflictError struct {
+	Name     string
+	Existing string
+	Wanted   string
+}
+
+func (e *InstrumentConflictError) Error() string {
+	return fmt.Sprintf("instrument %q already registered as %s, cannot redefine as %s", e.Name, e.Existing, e.Wanted)
+}
+
+func (s instrumentSpec) String() string {
+	return fmt.Sprintf("%s(unit=%q, description=%q)", s.kind, s.unit, s.description)
+}
+
+// lookup returns the instrument registered under name. When strict is false
+// only the kind has to match, so ad-hoc callers reuse declared instruments.
+func (r *instrumentRegistry) lookup(spec instrumentSpec, strict bool) (any, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	existing, ok := r.byName[spec.name]
+	if !ok {
+		return nil, false, nil
+	}
+	if existing.spec.kind != spec.kind || (strict && existing.spec != spec) {
+		return nil, true, &InstrumentConflictError{Name: spec.name, Existing: existing.spec.String(), Wanted: spec.String()}
+	}
+	return existing.instrument, true, nil
+}
+
+func (r *instrumentRegistry) get(m metric.Meter, spec instrumentSpec, strict bool) (any, error) {
+	inst, err := r.getOrCreate(m, spec, strict)
+	if err != nil {
+		reason := "create"
+		var conflict *InstrumentConflictError
+		if errors.As(err, &conflict) {
+			reason = "conflict"
+		}
+		instrumentFailed(spec.name, reason)
+	}
+	return inst, err
+}
+
+func (r *instrumentRegistry) getOrCreate(m metric.Meter, spec instrumentSpec, strict bool) (any, error) {
+	if inst, ok, err := r.lookup(spec, strict); ok {
+		return inst, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.byName[spec.name]; ok {
+		if existing.spec.kind != spec.kind || (strict && existing.spec != spec) {
+			return nil, &InstrumentConflictError{Name: spec.name, Existing: existing.spec.String(), Wanted: spec.String()}
+		}
+		return existing.instrument, nil
+	}
+
+	var inst any
+	var err error
+	switch spec.kind {
+	case kindInt64Counter:
+		inst, err = m.Int64Counter(spec.name, metric.WithUnit(spec.unit), metric.WithDescription(spec.description))
+	case kindFloat64Histogram:
+		inst, err = m.Float64Histogram(spec.name, metric.WithUnit(spec.unit), metric.WithDescription(spec.description))
+	case kindFloat64Gauge:
+		inst, err = m.Float64Gauge(spec.name, metric.WithUnit(spec.unit), metric.WithDescription(spec.description))
+	default:
+		err = fmt.Errorf("unknown instrument kind %q", spec.kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.byName[spec.name] = cachedInstrument{spec: spec, instrument: inst}
+	return inst, nil
+}
+
+// Int64Counter declares a counter with its unit and description, typically at
+// startup. Declaring the same name with a different unit or description
+// returns an *InstrumentConflictError.
+func Int64Counter(name, unit, description string) (metric.Int64Counter, error) {
+	inst, err := instruments.get(getMeter(), instrumentSpec{kindInt64Counter, name, unit, description}, true)
+	if err != nil {
+		return nil, err
+	}
+	return inst.(metric.Int64Counter), nil
+}
+
+// Float64Histogram declares a histogram; see Int64Counter.
+func Float64Histogram(name, unit, description string) (metric.Float64Histogram, error) {
+	inst, err := instruments.get(getMeter(), instrumentSpec{kindFloat64Histogram, name, unit, description}, true)
+	if err != nil {
+		return nil, err
+	}
+	return inst.(metric.Float64Histogram), nil
+}
+
+// Float64Gauge declares a gauge; see Int64Counter.
+func Float64Gauge(name, unit, description string) (metric.Float64Gauge, error) {
+	inst, err := instruments.get(getMeter(), instrumentSpec{kindFloat64Gauge, name, unit, description}, true)
+	if err != nil {
+		return nil, err
+	}
+	return inst.(metric.Float64Gauge), nil
+}
+
+func cachedCounter(r *instrumentRegistry, m metric.Meter, name string) (metric.Int64Counter, error) {
+	inst, err := r.get(m, instrumentSpec{kind: kindInt64Counter, name: name}, false)
+	if err != nil {
+		return nil, err
+	}
+	return inst.(metric.Int64Counter), nil
+}
+
+func cachedHistogram(r *instrumentRegistry, m metric.Meter, name string) (metric.Float64Histogram, error) {
+	inst, err := r.get(m, instrumentSpec{kind: kindFloat64Histogram, name: name}, false)
+	if err != nil {
+		return nil, err
+	}
+	return inst.(metric.Float64Histogram), nil
+}
+
+func cachedGauge(r *instrumentRegistry, m metric.Meter, name string) (metric.Float64Gauge, error) {
+	inst, err := r.get(m, instrumentSpec{kind: kindFloat64Gauge, name: name}, false)
+	if err != nil {
+		return nil, err
+	}
+	return inst.(metric.Float64Gauge), nil
+}
+
+// registry returns the instrument cache of l's meter.
+func (l *Eotel) registry() *instrumentRegistry {
+	if l.provider != nil {
+		return l.provider.instruments
+	}
+	return instruments
+}
+
+// Count adds delta to the counter called name, creating it on first use.
+func (l *Eotel) Count(name string, delta int64, attrs ...attribute.KeyValue) {
+	if l == nil || l.meter == nil {
+		return
+	}
+	c, err := cachedCounter(l.registry(), l.meter, name)
+	if err != nil {
+		return
+	}
+	ctx := l.metricCtx()
+	c.Add(ctx, delta, metric.WithAttributes(labelGuard.guard(name, l.serviceAttrs(attrs))...))
+	captureMetric(ctx, name, "counter", float64(delta), attrs)
+}
+
+// Record records value on the histogram called name, creating it on first use.
+func (l *Eotel) Record(name string, value float64, attrs ...attribute.KeyValue) {
+	if l == nil || l.meter == nil {
+		return
+	}
+	h, err := cachedHistogram(l.registry(), l.meter, name)
+	if err != nil {
+		return
+	}
+	ctx := l.metricCtx()
+	h.Record(ctx, value, metric.WithAttributes(labelGuard.guard(name, l.serviceAttrs(attrs))...))
+	captureMetric(ctx, name, "histogram", value, attrs)
+}
@@ -0,0 +1,71 @@
+package eotel
+
+import "sync/atomic"
+
+type Signal string
+
+const (
+	SignalTracing  Signal = "tracing"
+	SignalMetrics  Signal = "metrics"
+	SignalLoki     Signal = "loki"
+	SignalSentry   Signal = "sentry"
+	SignalOTLPLogs Signal = "otlp_logs"
+)
+
+var activeSignals atomic.Pointer[map[Signal]bool]
+
+func setActiveSignals(active map[Signal]bool) {
+	activeSignals.Store(&active)
+}
+
+// Enabled reports whether a signal pipeline was actually started by
+// InitEOTEL, as opposed to merely being requested in Config.
+func Enabled(signal Signal) bool {
+	m := activeSignals.Load()
+	if m == nil {
+		return false
+	}
+	return (*m)[signal]
+}
+
+// CurrentConfig returns the active configuration with secrets masked, suitable
+// for health and debug endpoints.
+func CurrentConfig() Config {
+	return currentConfig().Redacted()
+}
+
+func (c Config) Redacted() Config {
+	mask := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return redactedValue
+	}
+	maskMap := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		out := make(map[string]string, len(m))
+		for k, v := range m {
+			out[k] = mask(v)
+		}
+		return out
+	}
+
+	c.SentryDSN = mask(c.SentryDSN)
+	c.LokiPassword = mask(c.LokiPassword)
+	c.LokiBearerToken = mask(c.LokiBearerToken)
+	c.CaptureToken = mask(c.CaptureToken)
+	c.SessionHashKey = mask(c.SessionHashKey)
+	c.OtelHeaders = maskMap(c.OtelHeaders)
+	c.LokiHeaders = maskMap(c.LokiHeaders)
+	if c.TenantRoutes != nil {
+		routes := make(TenantRoutes, len(c.TenantRoutes))
+		for tenant, r := range c.TenantRoutes {
+			r.SentryDSN = mask(r.SentryDSN)
+			routes[tenant] = r
+		}
+		c.TenantRoutes = routes
+	}
+	return c
+}
@@ -0,0 +1,89 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	jobMetricsOnce sync.Once
+	jobRuns        metric.Int64Counter
+	jobDuration    metric.Float64Histogram
+)
+
+func initJobMetrics() {
+	m := getMeter()
+	jobRuns, _ = m.Int64Counter("eotel.job.runs",
+		metric.WithUnit("{run}"),
+		metric.WithDescription("Number of background job runs, by outcome."))
+	jobDuration, _ = m.Float64Histogram("eotel.job.duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Duration of background job runs."))
+}
+
+// Job runs fn as one execution of the background job name: it gets a root
+// span, a logger injected into ctx, duration and outcome metrics, and a
+// recovered panic is reported to Sentry and returned as an error.
+func Job(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	jobMetricsOnce.Do(initJobMetrics)
+
+	start := time.Now()
+	ctx, span := getTracer().Start(ctx, name,
+		trace.WithNewRoot(),
+		trace.WithAttributes(attribute.String("job.name", name)),
+	)
+	defer span.End()
+
+	logger := Safe(New(ctx, name)).TraceName(name).WithField("job.name", name)
+	ctx = Inject(ctx, logger)
+
+	outcome := "success"
+	defer func() {
+		if rec := recover(); rec != nil {
+			outcome = "panic"
+			err = fmt.Errorf("panic: %v", rec)
+			stack := string(debug.Stack())
+			lastBreathPanic(span.SpanContext(), err, stack)
+			CaptureError(err, map[string]string{"job.name": name}, map[string]any{"stack": stack})
+		}
+		if err != nil {
+			if outcome != "panic" {
+				outcome = "failure"
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			logger.WithError(err).Error("job failed")
+		} else {
+			logger.Debug("job completed")
+		}
+
+		attrs := metric.WithAttributes(
+			attribute.String("job.name", name),
+			attribute.String("job.outcome", outcome),
+		)
+		mctx := context.WithoutCancel(ctx)
+		jobRuns.Add(mctx, 1, attrs)
+		jobDuration.Record(mctx, time.Since(start).Seconds()*1000, attrs)
+		if o := lookupSLO(name); o != nil {
+			o.Observe(mctx, time.Since(start), err != nil)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// JobFunc adapts Job to the func() shape taken by cron schedulers such as
+// robfig/cron; errors are only reported through telemetry.
+func JobFunc(name string, fn func(ctx context.Context) error) func() {
+	return func() {
+		_ = Job(context.Background(), name, fn)
+	}
+}
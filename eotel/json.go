@@ -0,0 +1,94 @@
+package eotel
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxJSONPayloadBytes = 64 << 10
+	maxJSONValueBytes   = 256
+)
+
+var sensitiveJSONKeys = []string{"password", "passwd", "secret", "token", "authorization", "api_key", "apikey"}
+
+// WithJSON extracts selected dot-separated paths ("user.id", "items.0.sku")
+// from a JSON payload into fields named key.path, instead of logging the whole
+// body. Oversized payloads and values are capped and sensitive keys redacted.
+func (l *Eotel) WithJSON(key string, raw []byte, paths ...string) *Eotel {
+	if l == nil {
+		return Noop("WithJSON")
+	}
+	cp := l.clone()
+
+	if len(raw) > maxJSONPayloadBytes {
+		cp.addField(key+"._truncated", true)
+		return cp
+	}
+
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		cp.addField(key+"._error", "invalid json")
+		return cp
+	}
+
+	for _, path := range paths {
+		v, ok := jsonPath(doc, path)
+		if !ok {
+			continue
+		}
+		field := key + "." + path
+		if isSensitiveJSONPath(path) {
+			cp.addField(field, redactedValue)
+			continue
+		}
+		cp.addField(field, capJSONValue(v))
+	}
+	return cp
+}
+
+func jsonPath(doc any, path string) (any, bool) {
+	cur := doc
+	for _, part := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func capJSONValue(v any) any {
+	switch v.(type) {
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		v = string(b)
+	}
+	if s, ok := v.(string); ok && len(s) > maxJSONValueBytes {
+		return s[:maxJSONValueBytes] + "..."
+	}
+	return v
+}
+
+func isSensitiveJSONPath(path string) bool {
+	p := strings.ToLower(path)
+	for _, k := range sensitiveJSONKeys {
+		if strings.Contains(p, k) {
+			return true
+		}
+	}
+	return false
+}
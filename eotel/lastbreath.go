@@ -0,0 +1,84 @@
+package eotel
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// lastBreath appends fatal and panic records to Config.LastBreathFile and
+// fsyncs them before any network export is attempted, so the final error
+// survives a process that dies mid-flush.
+var lastBreath struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc zapcore.Encoder
+}
+
+// openLastBreath opens path for appending; setLastBreath installs it. An
+// empty path returns a nil file, which disables the writer.
+func openLastBreath(path string) (*os.File, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+}
+
+// setLastBreath replaces the file records are written to, closing the
+// previous one.
+func setLastBreath(f *os.File) {
+	lastBreath.mu.Lock()
+	defer lastBreath.mu.Unlock()
+	if lastBreath.f != nil {
+		_ = lastBreath.f.Close()
+	}
+	lastBreath.f = f
+	lastBreath.enc = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+}
+
+// writeLastBreath writes one JSON record synchronously. Errors go to stderr:
+// there is nowhere else left to report them.
+func writeLastBreath(level, msg string, fields []zap.Field) {
+	lastBreath.mu.Lock()
+	defer lastBreath.mu.Unlock()
+	if lastBreath.f == nil {
+		return
+	}
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		lvl = zapcore.ErrorLevel
+	}
+	buf, err := lastBreath.enc.EncodeEntry(zapcore.Entry{
+		Level:   lvl,
+		Time:    time.Now(),
+		Message: msg,
+	}, fields)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "eotel: last breath: %v\n", err)
+		return
+	}
+	defer buf.Free()
+	if _, err := lastBreath.f.Write(buf.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "eotel: last breath: %v\n", err)
+		return
+	}
+	if err := lastBreath.f.Sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "eotel: last breath: %v\n", err)
+	}
+}
+
+// lastBreathPanic records a recovered panic with its stack.
+func lastBreathPanic(sc trace.SpanContext, err error, stack string) {
+	writeLastBreath("error", "unhandled panic", []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+		zap.String("service", currentConfig().ServiceName),
+		zap.Error(err),
+		zap.String("stack", stack),
+	})
+}
@@ -0,0 +1,67 @@
+package eotel
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// latencyBudget is an end-to-end time allowance shared by a logger and the
+// children started from it.
+type latencyBudget struct {
+	start time.Time
+	total time.Duration
+}
+
+func (b *latencyBudget) remaining() time.Duration {
+	return b.total - time.Since(b.start)
+}
+
+// Budget starts a latency budget of total from now. Every span started from
+// the returned logger with Child records the budget left when it started and,
+// on End, the share it consumed and what remains, so the downstream call
+// that blew an end-to-end SLA stands out in the trace.
+func (l *Eotel) Budget(total time.Duration) *Eotel {
+	if l == nil {
+		return Noop("Budget")
+	}
+	if total <= 0 {
+		return l
+	}
+	cp := l.clone()
+	cp.budget = &latencyBudget{start: time.Now(), total: total}
+	cp.activeSpan().SetAttributes(attribute.Float64("budget.total_ms", durationMs(total)))
+	return cp
+}
+
+// BudgetRemaining returns the time left in the logger's budget, and false
+// when no budget was set.
+func (l *Eotel) BudgetRemaining() (time.Duration, bool) {
+	if l == nil || l.budget == nil {
+		return 0, false
+	}
+	return l.budget.remaining(), true
+}
+
+// budgetStartAttrs are set on a child span when it starts.
+func (b *latencyBudget) startAttrs() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Float64("budget.total_ms", durationMs(b.total)),
+		attribute.Float64("budget.remaining_at_start_ms", durationMs(b.remaining())),
+	}
+}
+
+// endAttrs are set on a child span when it ends, after consumed.
+func (b *latencyBudget) endAttrs(consumed time.Duration) []attribute.KeyValue {
+	remaining := b.remaining()
+	return []attribute.KeyValue{
+		attribute.Float64("budget.consumed_ms", durationMs(consumed)),
+		attribute.Float64("budget.consumed_ratio", float64(consumed)/float64(b.total)),
+		attribute.Float64("budget.remaining_ms", durationMs(remaining)),
+		attribute.Bool("budget.exceeded", remaining < 0),
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,61 @@
+package eotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanLevelPolicy controls how log levels are reflected on the active span.
+type SpanLevelPolicy struct {
+	// ErrorStatus marks the span as error on every Error/Fatal log, not only
+	// when an error was attached with WithError.
+	ErrorStatus bool `yaml:"error_status"`
+	// EventLevel is the minimum level recorded as a span event; empty keeps
+	// every level.
+	EventLevel string `yaml:"event_level"`
+	// FatalAttribute, when set, is added as a boolean attribute on Fatal.
+	FatalAttribute string `yaml:"fatal_attribute"`
+}
+
+var levelRank = map[string]int{
+	"debug": 0,
+	"info":  1,
+	"warn":  2,
+	"error": 3,
+	"fatal": 4,
+}
+
+func levelEnabled(level, min string) bool {
+	if min == "" {
+		return true
+	}
+	return levelRank[level] >= levelRank[min]
+}
+
+func (l *Eotel) applyLevelPolicy(span trace.Span, level, msg string) {
+	policy := currentConfig().SpanLevelPolicy
+
+	span.SetAttributes(l.spanAttrs()...)
+	if levelEnabled(level, policy.EventLevel) {
+		span.AddEvent("log", trace.WithAttributes(
+			attribute.String("log.message", msg),
+			attribute.String("log.level", level),
+		))
+	}
+
+	isError := level == "error" || level == "fatal"
+	switch {
+	case l.err != nil && isError:
+		span.RecordError(l.err)
+		if !markCanceled(span, l.err) {
+			span.SetStatus(codes.Error, l.err.Error())
+		}
+	case policy.ErrorStatus && isError:
+		span.SetStatus(codes.Error, msg)
+	}
+
+	if level == "fatal" && policy.FatalAttribute != "" {
+		span.SetAttributes(attribute.Bool(policy.FatalAttribute, true))
+	}
+}
@@ -0,0 +1,54 @@
+package eotel
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+)
+
+// minLevel is the runtime log level applied to every entry, on top of the
+// level the zap logger itself was built with.
+var minLevel = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
+// SetLevel changes the minimum level at runtime ("debug", "info", "warn",
+// "error"). Entries below it are dropped before reaching any exporter. It
+// cannot enable levels the underlying zap logger was built without.
+func SetLevel(level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	minLevel.SetLevel(lvl)
+	return nil
+}
+
+// Level returns the current minimum level.
+func Level() string {
+	return minLevel.Level().String()
+}
+
+// logLevels resolves the level names the Eotel methods use without parsing.
+var logLevels = map[string]zapcore.Level{
+	"debug": zapcore.DebugLevel,
+	"info":  zapcore.InfoLevel,
+	"warn":  zapcore.WarnLevel,
+	"error": zapcore.ErrorLevel,
+	"fatal": zapcore.FatalLevel,
+}
+
+func levelAllowed(level string) bool {
+	lvl, ok := logLevels[level]
+	if !ok {
+		var err error
+		if lvl, err = zapcore.ParseLevel(level); err != nil {
+			return true
+		}
+	}
+	return minLevel.Enabled(lvl)
+}
+
+// LevelHandler reports the level on GET and changes it on PUT, with the same
+// JSON protocol as zap.AtomicLevel: {"level":"debug"}.
+func LevelHandler() http.Handler {
+	return minLevel
+}
@@ -0,0 +1,49 @@
+package eotel
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// isShutdown is set once the shutdown function returned by InitEOTEL has run.
+// From then on log entries bypass the (closed) pipelines and go to stderr.
+var isShutdown atomic.Bool
+
+var postShutdownLogs atomic.Int64
+
+var (
+	stderrLoggerOnce sync.Once
+	stderrLogger     *zap.Logger
+)
+
+// PostShutdownLogs returns how many entries were logged after shutdown; a
+// non-zero value usually means goroutines outlived the application.
+func PostShutdownLogs() int64 {
+	return postShutdownLogs.Load()
+}
+
+func logAfterShutdown(level, msg string, fields []zap.Field) {
+	if postShutdownLogs.Add(1) == 1 {
+		fmt.Fprintln(os.Stderr, "eotel: logging after shutdown, entries are written to stderr only")
+	}
+	stderrLoggerOnce.Do(func() {
+		enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+		stderrLogger = zap.New(zapcore.NewCore(enc, zapcore.Lock(os.Stderr), zapcore.DebugLevel))
+	})
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		lvl = zapcore.InfoLevel
+	}
+	// Fatal is downgraded: Eotel.Fatal applies FatalBehavior on its own.
+	if lvl > zapcore.ErrorLevel {
+		lvl = zapcore.ErrorLevel
+	}
+	if ce := stderrLogger.Check(lvl, msg); ce != nil {
+		ce.Write(append(fields, zap.Bool("after_shutdown", true))...)
+	}
+}
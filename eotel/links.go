@@ -0,0 +1,33 @@
+package eotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LinkTo links the logger's span to sc, typically the span of a message that
+// a batch or fan-in consumer is processing on behalf of another trace.
+func (l *Eotel) LinkTo(sc trace.SpanContext, attrs ...attribute.KeyValue) {
+	if l == nil || !sc.IsValid() {
+		return
+	}
+	l.activeSpan().AddLink(trace.Link{SpanContext: sc, Attributes: attrs})
+}
+
+// NewWithRemoteParent is New with the trace context extracted from carrier
+// (HTTP headers, message headers, ...), so spans started from the returned
+// logger continue the remote trace.
+func NewWithRemoteParent(ctx context.Context, name string, carrier propagation.TextMapCarrier) *Eotel {
+	return New(otel.GetTextMapPropagator().Extract(ctx, carrier), name)
+}
+
+// RemoteSpanContext returns the span context propagated in carrier, for use
+// with LinkTo; it is invalid when carrier holds none.
+func RemoteSpanContext(carrier propagation.TextMapCarrier) trace.SpanContext {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	return trace.SpanContextFromContext(ctx)
+}
@@ -0,0 +1,101 @@
+package eotel
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBufferedLogs caps the entries held per request; later ones are dropped.
+const maxBufferedLogs = 1000
+
+// With Config.TraceAwareLogs, debug and info entries of unsampled traces are
+// held back from Loki, OTLP and registered exporters until the request's
+// outcome is known: they are exported if it fails (5xx status or an error
+// entry) and discarded otherwise. Local zap output is never held back.
+type logBuffer struct {
+	failed  bool
+	pending []func()
+}
+
+var logBuffers struct {
+	mu     sync.Mutex
+	active atomic.Int32
+	byID   map[trace.TraceID]*logBuffer
+}
+
+// deferExport reports whether an entry at level in a trace with span context
+// sc must go through bufferExport rather than be exported right away.
+// Entries outside a trace, as when tracing is off, are never held back.
+func deferExport(sc trace.SpanContext, level string) bool {
+	return currentConfig().TraceAwareLogs && sc.IsValid() && !sc.IsSampled() && (level == "debug" || level == "info")
+}
+
+func startLogBuffer(id trace.TraceID) {
+	logBuffers.mu.Lock()
+	defer logBuffers.mu.Unlock()
+	if logBuffers.byID == nil {
+		logBuffers.byID = map[trace.TraceID]*logBuffer{}
+	}
+	if _, ok := logBuffers.byID[id]; !ok {
+		logBuffers.byID[id] = &logBuffer{}
+		logBuffers.active.Add(1)
+	}
+}
+
+// bufferExport holds export until the request of trace id completes. Outside
+// a buffered request the entry is not exported; once the request has failed
+// it is exported immediately.
+func bufferExport(id trace.TraceID, export func()) {
+	if logBuffers.active.Load() == 0 {
+		return
+	}
+	logBuffers.mu.Lock()
+	b, ok := logBuffers.byID[id]
+	switch {
+	case !ok:
+		logBuffers.mu.Unlock()
+	case b.failed:
+		logBuffers.mu.Unlock()
+		export()
+	default:
+		if len(b.pending) < maxBufferedLogs {
+			b.pending = append(b.pending, export)
+		}
+		logBuffers.mu.Unlock()
+	}
+}
+
+// failLogBuffer exports what trace id has buffered so far and lets later
+// entries through.
+func failLogBuffer(id trace.TraceID) {
+	if logBuffers.active.Load() == 0 {
+		return
+	}
+	logBuffers.mu.Lock()
+	b, ok := logBuffers.byID[id]
+	var pending []func()
+	if ok && !b.failed {
+		b.failed = true
+		pending, b.pending = b.pending, nil
+	}
+	logBuffers.mu.Unlock()
+	for _, export := range pending {
+		export()
+	}
+}
+
+// finishLogBuffer releases the buffer of trace id, exporting its entries if
+// the request failed.
+func finishLogBuffer(id trace.TraceID, failed bool) {
+	if failed {
+		failLogBuffer(id)
+	}
+	logBuffers.mu.Lock()
+	if _, ok := logBuffers.byID[id]; ok {
+		delete(logBuffers.byID, id)
+		logBuffers.active.Add(-1)
+	}
+	logBuffers.mu.Unlock()
+}
@@ -0,0 +1,196 @@
+package eotel
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerConfig builds the zap logger when Config.Logger is not provided.
+// Leaving it empty keeps using zap.L().
+type LoggerConfig struct {
+	// Encoding is "json" (default) or "console".
+	Encoding string `yaml:"encoding"`
+	// OutputPaths are "stdout", "stderr" or file paths; files are rotated
+	// according to Rotation. Defaults to stdout.
+	OutputPaths []string       `yaml:"output_paths"`
+	Rotation    RotationConfig `yaml:"rotation"`
+
+	// Caller adds the calling file:line; StacktraceLevel ("error" when empty)
+	// is the level from which stack traces are attached.
+	Caller          bool   `yaml:"caller"`
+	StacktraceLevel string `yaml:"stacktrace_level"`
+
+	// Outputs tee entries to several destinations, each with its own
+	// encoding and minimum level, next to OutputPaths. See LogOutput.
+	Outputs []LogOutput `yaml:"outputs"`
+}
+
+// Log destinations that are export pipelines rather than writers.
+const (
+	LogOutputLoki = "loki"
+	LogOutputOTLP = "otlp"
+)
+
+// LogOutput is one destination of LoggerConfig.Outputs. Path is "stdout",
+// "stderr" or a file (rotated per LoggerConfig.Rotation); LogOutputLoki and
+// LogOutputOTLP instead set the minimum level of entries exported to Loki
+// and through OTLP logs. Encoding defaults to LoggerConfig.Encoding; Level
+// applies on top of the runtime level set by SetLevel.
+type LogOutput struct {
+	Path     string `yaml:"path"`
+	Encoding string `yaml:"encoding"`
+	Level    string `yaml:"level"`
+}
+
+func (o LogOutput) pipeline() bool {
+	return o.Path == LogOutputLoki || o.Path == LogOutputOTLP
+}
+
+type RotationConfig struct {
+	MaxSizeMB  int  `yaml:"max_size_mb"`
+	MaxAgeDays int  `yaml:"max_age_days"`
+	MaxBackups int  `yaml:"max_backups"`
+	Compress   bool `yaml:"compress"`
+}
+
+func (c LoggerConfig) enabled() bool {
+	return c.Encoding != "" || len(c.OutputPaths) > 0 || len(c.Outputs) > 0
+}
+
+// outputAllows reports whether entries at level go to the pipeline dest
+// (LogOutputLoki or LogOutputOTLP) under Config.Log.Outputs.
+func outputAllows(dest, level string) bool {
+	for _, o := range currentConfig().Log.Outputs {
+		if o.Path == dest {
+			return levelEnabled(level, o.Level)
+		}
+	}
+	return true
+}
+
+// newLogger builds a logger from cfg. Its level is the runtime level
+// controlled by SetLevel.
+func newLogger(cfg LoggerConfig) (*zap.Logger, error) {
+	var cores []zapcore.Core
+	writerOutputs := 0
+	for _, o := range cfg.Outputs {
+		if o.pipeline() {
+			if _, err := zapcore.ParseLevel(o.Level); o.Level != "" && err != nil {
+				return nil, fmt.Errorf("log output %s: %w", o.Path, err)
+			}
+			continue
+		}
+		writerOutputs++
+		encoding := o.Encoding
+		if encoding == "" {
+			encoding = cfg.Encoding
+		}
+		enc, err := newLogEncoder(encoding)
+		if err != nil {
+			return nil, fmt.Errorf("log output %s: %w", o.Path, err)
+		}
+		var level zapcore.LevelEnabler = minLevel
+		if o.Level != "" {
+			lvl, err := zapcore.ParseLevel(o.Level)
+			if err != nil {
+				return nil, fmt.Errorf("log output %s: %w", o.Path, err)
+			}
+			level = zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+				return l >= lvl && minLevel.Enabled(l)
+			})
+		}
+		w, err := logWriter(o.Path, cfg.Rotation)
+		if err != nil {
+			return nil, fmt.Errorf("log output %s: %w", o.Path, err)
+		}
+		cores = append(cores, zapcore.NewCore(enc, w, level))
+	}
+
+	paths := cfg.OutputPaths
+	if len(paths) == 0 && writerOutputs == 0 {
+		paths = []string{"stdout"}
+	}
+	if len(paths) > 0 {
+		enc, err := newLogEncoder(cfg.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		writers := make([]zapcore.WriteSyncer, 0, len(paths))
+		for _, p := range paths {
+			w, err := logWriter(p, cfg.Rotation)
+			if err != nil {
+				return nil, fmt.Errorf("log output %s: %w", p, err)
+			}
+			writers = append(writers, w)
+		}
+		cores = append(cores, zapcore.NewCore(enc, zapcore.NewMultiWriteSyncer(writers...), minLevel))
+	}
+
+	stackLevel := zapcore.ErrorLevel
+	if cfg.StacktraceLevel != "" {
+		lvl, err := zapcore.ParseLevel(cfg.StacktraceLevel)
+		if err != nil {
+			return nil, fmt.Errorf("stacktrace level: %w", err)
+		}
+		stackLevel = lvl
+	}
+
+	opts := []zap.Option{zap.AddStacktrace(stackLevel)}
+	if cfg.Caller {
+		// Skip Eotel.log and the level method.
+		opts = append(opts, zap.AddCaller(), zap.AddCallerSkip(2))
+	}
+	return zap.New(zapcore.NewTee(cores...), opts...), nil
+}
+
+func newLogEncoder(encoding string) (zapcore.Encoder, error) {
+	encCfg := zap.NewProductionEncoderConfig()
+	encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	switch encoding {
+	case "", "json":
+		return zapcore.NewJSONEncoder(encCfg), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(encCfg), nil
+	}
+	return nil, fmt.Errorf("unsupported log encoding %q", encoding)
+}
+
+var logRotators struct {
+	mu  sync.Mutex
+	new func(path string, rot RotationConfig) io.Writer
+}
+
+// RegisterLogRotator sets the constructor for log files named in
+// LoggerConfig. The eotelrotate module registers one when imported, as the
+// root package does; without it files are opened for appending and never
+// rotated.
+func RegisterLogRotator(newRotator func(path string, rot RotationConfig) io.Writer) {
+	logRotators.mu.Lock()
+	logRotators.new = newRotator
+	logRotators.mu.Unlock()
+}
+
+func logWriter(path string, rot RotationConfig) (zapcore.WriteSyncer, error) {
+	switch path {
+	case "stdout":
+		return zapcore.Lock(os.Stdout), nil
+	case "stderr":
+		return zapcore.Lock(os.Stderr), nil
+	}
+	logRotators.mu.Lock()
+	newRotator := logRotators.new
+	logRotators.mu.Unlock()
+	if newRotator != nil {
+		return zapcore.AddSync(newRotator(path, rot)), nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return zapcore.Lock(f), nil
+}
@@ -0,0 +1,31 @@
+package eotel
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Record is a log entry as seen by Config.LogHooks, before it is written to
+// zap, Loki and the active span.
+type Record struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Logger  string
+	TraceID string
+	SpanID  string
+	Err     error
+	Fields  []zap.Field
+}
+
+// runLogHooks passes r through each hook in order. A hook returning nil drops
+// the entry and the remaining hooks are skipped.
+func runLogHooks(hooks []func(*Record) *Record, r *Record) *Record {
+	for _, h := range hooks {
+		if r = h(r); r == nil {
+			return nil
+		}
+	}
+	return r
+}
@@ -0,0 +1,148 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+)
+
+const (
+	logRecordsMetric  = "eotel.log.records"
+	logDurationMetric = "eotel.log.duration"
+
+	legacyLogRecordsMetric  = "log_total"
+	legacyLogDurationMetric = "log_duration_ms"
+)
+
+// initMetrics creates the built-in log instruments. With
+// Config.LegacyMetricNames the pre-semconv names are recorded as well, so
+// existing dashboards keep working during migration. Instruments that fail
+// to be created are replaced by no-ops and the errors returned.
+func initMetrics(m metric.Meter) (metric.Int64Counter, metric.Float64Histogram, error) {
+	var errs []error
+	c, err := m.Int64Counter(logRecordsMetric,
+		metric.WithUnit("{record}"),
+		metric.WithDescription("Number of log records emitted, by level."))
+	if err != nil || c == nil {
+		errs = append(errs, fmt.Errorf("%s: %w", logRecordsMetric, err))
+		c = noop.Int64Counter{}
+	}
+	h, err := m.Float64Histogram(logDurationMetric,
+		metric.WithUnit("ms"),
+		metric.WithDescription("Time between logger creation and each log record."))
+	if err != nil || h == nil {
+		errs = append(errs, fmt.Errorf("%s: %w", logDurationMetric, err))
+		h = noop.Float64Histogram{}
+	}
+
+	if !currentConfig().LegacyMetricNames {
+		return c, h, errors.Join(errs...)
+	}
+
+	lc, err := m.Int64Counter(legacyLogRecordsMetric)
+	if err != nil || lc == nil {
+		errs = append(errs, fmt.Errorf("%s: %w", legacyLogRecordsMetric, err))
+		lc = noop.Int64Counter{}
+	}
+	lh, err := m.Float64Histogram(legacyLogDurationMetric)
+	if err != nil || lh == nil {
+		errs = append(errs, fmt.Errorf("%s: %w", legacyLogDurationMetric, err))
+		lh = noop.Float64Histogram{}
+	}
+	return teeCounter{primary: c, alias: lc}, teeHistogram{primary: h, alias: lh}, errors.Join(errs...)
+}
+
+// instrumentErrors counts failed instrument creations and lookups
+// (conflicting redefinitions, invalid names), which are otherwise only
+// visible as missing series.
+var instrumentErrors atomic.Pointer[metric.Int64Counter]
+
+// initInstruments is called by InitEOTEL once the meter is set up: it resets
+// the instrument registry to the new meter and creates the built-in log
+// instruments, so New only reads them. Nothing is changed when it fails.
+func initInstruments(m metric.Meter, legacy bool) error {
+	ie, err := m.Int64Counter("eotel.instrument.errors",
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Failed instrument creations and lookups, by instrument and reason."))
+	if err != nil {
+		return fmt.Errorf("eotel.instrument.errors: %w", err)
+	}
+	c, h, err := initMetrics(m)
+	if err != nil {
+		return err
+	}
+	instruments.reset()
+	instrumentErrors.Store(&ie)
+	cachedLogInstruments.Store(&logInstruments{meter: m, legacy: legacy, counter: c, hist: h})
+	return nil
+}
+
+// instrumentFailed records a failed instrument operation.
+func instrumentFailed(name, reason string) {
+	if ie := instrumentErrors.Load(); ie != nil {
+		(*ie).Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("instrument", name),
+			attribute.String("reason", reason),
+		))
+	}
+}
+
+// logInstruments holds the built-in log instruments of the current meter,
+// created by initInstruments so New doesn't look them up on every call.
+type logInstruments struct {
+	meter   metric.Meter
+	legacy  bool
+	counter metric.Int64Counter
+	hist    metric.Float64Histogram
+}
+
+var cachedLogInstruments atomic.Pointer[logInstruments]
+
+func logMetrics(m metric.Meter) (metric.Int64Counter, metric.Float64Histogram) {
+	if !reflect.TypeOf(m).Comparable() {
+		c, h, err := initMetrics(m)
+		if err != nil {
+			instrumentFailed(logRecordsMetric, "create")
+		}
+		return c, h
+	}
+	legacy := currentConfig().LegacyMetricNames
+	if li := cachedLogInstruments.Load(); li != nil && li.meter == m && li.legacy == legacy {
+		return li.counter, li.hist
+	}
+	c, h, err := initMetrics(m)
+	if err != nil {
+		instrumentFailed(logRecordsMetric, "create")
+		return c, h
+	}
+	cachedLogInstruments.Store(&logInstruments{meter: m, legacy: legacy, counter: c, hist: h})
+	return c, h
+}
+
+type teeCounter struct {
+	embedded.Int64Counter
+	primary, alias metric.Int64Counter
+}
+
+func (t teeCounter) Add(ctx context.Context, incr int64, opts ...metric.AddOption) {
+	t.primary.Add(ctx, incr, opts...)
+	t.alias.Add(ctx, incr, opts...)
+}
+
+type teeHistogram struct {
+	embedded.Float64Histogram
+	primary, alias metric.Float64Histogram
+}
+
+func (t teeHistogram) Record(ctx context.Context, v float64, opts ...metric.RecordOption) {
+	t.primary.Record(ctx, v, opts...)
+	t.alias.Record(ctx, v, opts...)
+}
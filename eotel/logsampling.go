@@ -0,0 +1,176 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// maxRateLimitKeys bounds the per-message buckets; past it the table is reset.
+const maxRateLimitKeys = 10000
+
+// LogSampling thins debug, info and warn entries during log storms. Error
+// entries are only sampled per signature with ErrorEvery; fatal entries are
+// always kept.
+type LogSampling struct {
+	// Every keeps 1 entry in N for the given level, e.g. {"debug": 100}.
+	Every map[string]int `yaml:"every"`
+	// RepeatRate and RepeatBurst rate-limit identical messages (same level
+	// and text) with a token bucket refilled at RepeatRate per second.
+	// Zero RepeatRate disables the limiter; RepeatBurst defaults to 1.
+	RepeatRate  float64 `yaml:"repeat_rate"`
+	RepeatBurst int     `yaml:"repeat_burst"`
+	// ErrorFirst and ErrorEvery sample error entries by signature (message
+	// and error text): within each ErrorWindow the first ErrorFirst are
+	// kept, then 1 in ErrorEvery. Zero ErrorEvery disables it; ErrorWindow
+	// defaults to a minute.
+	ErrorFirst  int           `yaml:"error_first"`
+	ErrorEvery  int           `yaml:"error_every"`
+	ErrorWindow time.Duration `yaml:"error_window"`
+}
+
+func (s LogSampling) enabled() bool {
+	return len(s.Every) > 0 || s.RepeatRate > 0 || s.ErrorEvery > 1
+}
+
+type logSampler struct {
+	every   map[string]uint64
+	counts  map[string]*atomic.Uint64
+	rate    float64
+	burst   float64
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+
+	errFirst   uint64
+	errEvery   uint64
+	errWindow  time.Duration
+	errMu      sync.Mutex
+	signatures map[string]*signatureCount
+
+	dropped metric.Int64Counter
+}
+
+type signatureCount struct {
+	n     uint64
+	since time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+var activeLogSampler atomic.Pointer[logSampler]
+
+func newLogSampler(cfg LogSampling) *logSampler {
+	if !cfg.enabled() {
+		return nil
+	}
+	s := &logSampler{
+		every:   map[string]uint64{},
+		counts:  map[string]*atomic.Uint64{},
+		rate:    cfg.RepeatRate,
+		burst:   float64(cfg.RepeatBurst),
+		buckets: map[string]*tokenBucket{},
+	}
+	if s.burst <= 0 {
+		s.burst = 1
+	}
+	if cfg.ErrorEvery > 1 {
+		s.errFirst = uint64(max(cfg.ErrorFirst, 0))
+		s.errEvery = uint64(cfg.ErrorEvery)
+		s.errWindow = cfg.ErrorWindow
+		if s.errWindow <= 0 {
+			s.errWindow = time.Minute
+		}
+		s.signatures = map[string]*signatureCount{}
+	}
+	for level, n := range cfg.Every {
+		if n > 1 {
+			s.every[level] = uint64(n)
+			s.counts[level] = new(atomic.Uint64)
+		}
+	}
+	s.dropped, _ = getMeter().Int64Counter("eotel.log.dropped",
+		metric.WithUnit("{record}"),
+		metric.WithDescription("Log records dropped by sampling or rate limiting."))
+	return s
+}
+
+// keep reports whether the entry should be emitted.
+func (s *logSampler) keep(level, msg string, err error) bool {
+	if s == nil || level == "fatal" {
+		return true
+	}
+	if level == "error" {
+		if s.errEvery == 0 || s.keepError(msg, err) {
+			return true
+		}
+		s.drop(level, "error_sampled")
+		return false
+	}
+	if n, ok := s.every[level]; ok && s.counts[level].Add(1)%n != 1 {
+		s.drop(level, "sampled")
+		return false
+	}
+	if s.rate > 0 && !s.allow(level+"\x00"+msg) {
+		s.drop(level, "rate_limited")
+		return false
+	}
+	return true
+}
+
+// keepError applies ErrorFirst/ErrorEvery to the entry's signature.
+func (s *logSampler) keepError(msg string, err error) bool {
+	sig := msg
+	if err != nil {
+		sig += "\x00" + err.Error()
+	}
+	now := time.Now()
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	c, ok := s.signatures[sig]
+	if !ok || now.Sub(c.since) >= s.errWindow {
+		if !ok && len(s.signatures) >= maxRateLimitKeys {
+			s.signatures = map[string]*signatureCount{}
+		}
+		c = &signatureCount{since: now}
+		s.signatures[sig] = c
+	}
+	c.n++
+	return c.n <= s.errFirst || (c.n-s.errFirst)%s.errEvery == 1
+}
+
+func (s *logSampler) allow(key string) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[key]
+	if !ok {
+		if len(s.buckets) >= maxRateLimitKeys {
+			s.buckets = map[string]*tokenBucket{}
+		}
+		b = &tokenBucket{tokens: s.burst, last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = min(s.burst, b.tokens+now.Sub(b.last).Seconds()*s.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (s *logSampler) drop(level, reason string) {
+	if s.dropped != nil {
+		s.dropped.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("level", level),
+			attribute.String("reason", reason),
+		))
+	}
+}
@@ -0,0 +1,512 @@
+package eotel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type LokiEntry struct {
+	Labels  map[string]string
+	Message string
+	Time    time.Time
+	// OrgID overrides the X-Scope-OrgID of the push (see TenantRoute).
+	OrgID string `json:",omitempty"`
+}
+
+const (
+	defaultLokiBatchSize     = 100
+	defaultLokiBatchInterval = time.Second
+	defaultLokiQueueSize     = 1000
+	defaultLokiMaxRetries    = 5
+)
+
+func SendLokiAsync(level string, msg string, traceID string, spanID string) {
+	SendLokiFields(level, msg, traceID, spanID, nil)
+}
+
+// SendLokiFields queues a log line for Loki. Stream labels are limited to the
+// static labels, the level and the fields allowlisted in LokiLabelFields;
+// everything else, trace IDs included, goes into the JSON log line to keep
+// stream cardinality bounded.
+func SendLokiFields(level string, msg string, traceID string, spanID string, fields map[string]any) {
+	sendLoki(context.Background(), level, msg, traceID, spanID, fields)
+}
+
+func sendLoki(ctx context.Context, level, msg, traceID, spanID string, fields map[string]any) {
+	cfg := currentConfig()
+	if !cfg.EnableLoki || !outputAllows(LogOutputLoki, level) {
+		return
+	}
+	p := lokiClient.Load()
+	if p == nil {
+		return
+	}
+	labels, line := buildLokiEntry(*cfg, level, msg, traceID, spanID, fields)
+	p.enqueue(LokiEntry{Labels: labels, Message: line, Time: time.Now(), OrgID: lokiOrgID(ctx)})
+}
+
+func buildLokiEntry(cfg Config, level, msg, traceID, spanID string, fields map[string]any) (map[string]string, string) {
+	labels := map[string]string{
+		"service": cfg.ServiceName,
+		"job":     cfg.JobName,
+	}
+	for k, v := range staticLabels() {
+		labels[k] = v
+	}
+	for k, v := range cfg.LokiStaticLabels {
+		labels[k] = v
+	}
+	labels["level"] = level
+
+	rd := activeRedactor.Load()
+	line := map[string]any{"msg": rd.scrub(msg)}
+	if traceID != "" {
+		line["trace_id"] = traceID
+	}
+	if spanID != "" {
+		line["span_id"] = spanID
+	}
+
+	allowed := make(map[string]string, len(cfg.LokiLabelFields))
+	for _, f := range cfg.LokiLabelFields {
+		allowed[f] = sanitizeLokiLabel(f)
+	}
+	for k, v := range rd.redactMap(fields) {
+		if isStaticField(k) {
+			continue
+		}
+		if label, ok := allowed[k]; ok {
+			labels[label] = fmt.Sprintf("%v", v)
+			continue
+		}
+		line[k] = v
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return labels, msg
+	}
+	return labels, string(data)
+}
+
+// sanitizeLokiLabel maps a field name onto Loki's label charset
+// [a-zA-Z_][a-zA-Z0-9_]*.
+func sanitizeLokiLabel(name string) string {
+	b := []byte(sanitizeKey(name))
+	for i, c := range b {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9')
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+var lokiClient atomic.Pointer[lokiPusher]
+
+// lokiPusher batches entries and pushes them from a single worker, retrying
+// with exponential backoff on network errors and 5xx responses. What a full
+// queue does with new entries is up to Config.LokiQueue; by default they are
+// dropped and counted.
+type lokiPusher struct {
+	url        string
+	batchSize  int
+	interval   time.Duration
+	maxRetries int
+	client     *http.Client
+	auth       lokiAuth
+
+	queue   *exportQueue[LokiEntry]
+	flushCh chan chan struct{}
+	quit    chan struct{}
+	pending atomic.Int64
+	breaker *breaker
+	spill   *spillQueue
+
+	sent    metric.Int64Counter
+	dropped metric.Int64Counter
+	retried metric.Int64Counter
+}
+
+func startLoki(cfg Config) error {
+	p, err := newLokiPusher(cfg, getMeter())
+	if err != nil {
+		return err
+	}
+	p.start()
+	return nil
+}
+
+// newLokiPusher builds a pusher without starting it; start installs it in
+// place of the running one and stop releases it either way.
+func newLokiPusher(cfg Config, meter metric.Meter) (*lokiPusher, error) {
+	spill, err := openSpill("loki", cfg.DiskBuffer, meter)
+	if err != nil {
+		return nil, err
+	}
+	p := &lokiPusher{
+		url:        cfg.LokiURL,
+		batchSize:  cfg.LokiBatchSize,
+		interval:   cfg.LokiBatchInterval,
+		maxRetries: cfg.LokiMaxRetries,
+		client:     &http.Client{Timeout: timeoutOr(cfg.ExporterTimeouts.Loki, defaultLokiTimeout)},
+		flushCh:    make(chan chan struct{}),
+		quit:       make(chan struct{}),
+		auth: lokiAuth{
+			username:    cfg.LokiUsername,
+			password:    cfg.LokiPassword,
+			bearerToken: cfg.LokiBearerToken,
+			tenantID:    cfg.LokiTenantID,
+			headers:     cfg.LokiHeaders,
+		},
+	}
+	if p.batchSize <= 0 {
+		p.batchSize = defaultLokiBatchSize
+	}
+	if p.interval <= 0 {
+		p.interval = defaultLokiBatchInterval
+	}
+	if p.maxRetries <= 0 {
+		p.maxRetries = defaultLokiMaxRetries
+	}
+	queueSize := cfg.LokiQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultLokiQueueSize
+	}
+	p.queue, err = newExportQueue("loki", queueSize, cfg.LokiQueue, cfg.DiskBuffer, lokiQueueCodec, meter)
+	if err != nil {
+		spill.close()
+		return nil, err
+	}
+	p.breaker = newBreaker("loki", cfg.CircuitBreaker, &lokiStatus, meter)
+	p.spill = spill
+
+	p.sent, _ = meter.Int64Counter("loki_entries_sent_total")
+	p.dropped, _ = meter.Int64Counter("loki_entries_dropped_total")
+	p.retried, _ = meter.Int64Counter("loki_entries_retried_total")
+	return p, nil
+}
+
+func (p *lokiPusher) start() {
+	go p.run()
+	if old := lokiClient.Swap(p); old != nil {
+		old.stop()
+	}
+}
+
+func (p *lokiPusher) enqueue(entry LokiEntry) {
+	if !p.tryEnqueue(entry) {
+		lokiStatus.drop(1)
+		p.dropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", "queue_full")))
+	}
+}
+
+func (p *lokiPusher) tryEnqueue(entry LokiEntry) bool {
+	ok, evicted := p.queue.push(entry)
+	if evicted > 0 {
+		lokiStatus.drop(evicted)
+		p.dropped.Add(context.Background(), int64(evicted), metric.WithAttributes(attribute.String("reason", "evicted")))
+	}
+	if ok {
+		p.pending.Add(int64(1 - evicted))
+	}
+	return ok
+}
+
+var lokiQueueCodec = &queueCodec[LokiEntry]{
+	encode: func(batch []LokiEntry) [][]byte {
+		records := make([][]byte, 0, len(batch))
+		for _, e := range batch {
+			if b, err := json.Marshal(e); err == nil {
+				records = append(records, b)
+			}
+		}
+		return records
+	},
+	decode: decodeLokiRecords,
+}
+
+func (p *lokiPusher) stop() {
+	p.queue.close()
+	p.spill.close()
+	close(p.quit)
+}
+
+func (p *lokiPusher) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	batch := make([]LokiEntry, 0, p.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		p.push(batch)
+		p.pending.Add(-int64(len(batch)))
+		batch = batch[:0]
+	}
+
+	fill := func() {
+		for {
+			entries := p.queue.take(p.batchSize - len(batch))
+			if len(entries) == 0 {
+				return
+			}
+			batch = append(batch, entries...)
+			if len(batch) >= p.batchSize {
+				flush()
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-p.queue.ready:
+			fill()
+		case <-ticker.C:
+			flush()
+		case done := <-p.flushCh:
+			fill()
+			flush()
+			close(done)
+		}
+	}
+}
+
+// push sends batch with one request per Loki tenant.
+func (p *lokiPusher) push(batch []LokiEntry) {
+	for _, group := range groupByOrg(batch) {
+		p.pushOrg(group)
+	}
+}
+
+// groupByOrg splits batch by OrgID, keeping the order within each group.
+func groupByOrg(batch []LokiEntry) [][]LokiEntry {
+	var groups [][]LokiEntry
+	index := map[string]int{}
+	for _, e := range batch {
+		i, ok := index[e.OrgID]
+		if !ok {
+			i = len(groups)
+			index[e.OrgID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
+	}
+	return groups
+}
+
+// pushOrg sends entries sharing one OrgID.
+func (p *lokiPusher) pushOrg(batch []LokiEntry) {
+	ctx, cancel := exportContext(context.Background(), timeoutOr(currentConfig().ExporterTimeouts.LokiPush, defaultLokiPushTimeout))
+	defer cancel()
+	if !p.breaker.allow() {
+		if p.spill.spillLoki(batch) {
+			return
+		}
+		p.breaker.reject(len(batch), &lokiStatus)
+		p.dropped.Add(ctx, int64(len(batch)), metric.WithAttributes(attribute.String("reason", "circuit_open")))
+		return
+	}
+	body, err := encodeLokiBatch(batch)
+	if err != nil {
+		lokiStatus.record(err)
+		lokiStatus.drop(len(batch))
+		p.dropped.Add(ctx, int64(len(batch)), metric.WithAttributes(attribute.String("reason", "encode")))
+		return
+	}
+
+	backoff := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		retry, err := p.send(ctx, body, batch[0].OrgID)
+		lokiStatus.record(err)
+		if err == nil {
+			p.breaker.done(nil)
+			p.sent.Add(ctx, int64(len(batch)))
+			if p.spill.pending() {
+				go p.spill.replay(p.replay)
+			}
+			return
+		}
+		if !retry || attempt >= p.maxRetries || ctx.Err() != nil {
+			p.breaker.done(err)
+			if retry && p.spill.spillLoki(batch) {
+				return
+			}
+			lokiStatus.drop(len(batch))
+			p.dropped.Add(ctx, int64(len(batch)), metric.WithAttributes(attribute.String("reason", "send_failed")))
+			return
+		}
+		p.retried.Add(ctx, int64(len(batch)))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+		if backoff < 10*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// replay pushes entries read back from the disk buffer, once, without the
+// retries of push: a failure leaves them on disk for the next attempt.
+func (p *lokiPusher) replay(records [][]byte) error {
+	ctx, cancel := exportContext(context.Background(), timeoutOr(currentConfig().ExporterTimeouts.LokiPush, defaultLokiPushTimeout))
+	defer cancel()
+	for _, batch := range groupByOrg(decodeLokiRecords(records)) {
+		body, err := encodeLokiBatch(batch)
+		if err != nil {
+			continue
+		}
+		_, err = p.send(ctx, body, batch[0].OrgID)
+		lokiStatus.record(err)
+		if err != nil {
+			return err
+		}
+		p.sent.Add(context.Background(), int64(len(batch)))
+	}
+	return nil
+}
+
+// send reports whether a failed push is worth retrying. A non-empty orgID
+// replaces the configured tenant.
+func (p *lokiPusher) send(ctx context.Context, body []byte, orgID string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	p.auth.apply(req)
+	if orgID != "" {
+		req.Header.Set("X-Scope-OrgID", orgID)
+	}
+	recordUsage(usageLoki, int64(len(body)))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return true, fmt.Errorf("loki response: %s", resp.Status)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("loki response: %s", resp.Status)
+	}
+	return false, nil
+}
+
+type lokiAuth struct {
+	username    string
+	password    string
+	bearerToken string
+	tenantID    string
+	headers     map[string]string
+}
+
+func (a lokiAuth) apply(req *http.Request) {
+	for k, v := range a.headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case a.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+a.bearerToken)
+	case a.username != "":
+		req.SetBasicAuth(a.username, a.password)
+	}
+	if a.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", a.tenantID)
+	}
+}
+
+// encodeLokiBatch groups entries by label set into streams and gzips the
+// push request body.
+func encodeLokiBatch(batch []LokiEntry) ([]byte, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	streams := map[string]*stream{}
+	var order []string
+	for _, e := range batch {
+		key := labelsKey(e.Labels)
+		s, ok := streams[key]
+		if !ok {
+			s = &stream{Stream: e.Labels}
+			streams[key] = s
+			order = append(order, key)
+		}
+		ts := e.Time
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+		s.Values = append(s.Values, [2]string{fmt.Sprintf("%d", ts.UnixNano()), e.Message})
+	}
+
+	payload := struct {
+		Streams []*stream `json:"streams"`
+	}{}
+	for _, key := range order {
+		payload.Streams = append(payload.Streams, streams[key])
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(payload); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(labels[k])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func drainLoki(ctx context.Context) error {
+	p := lokiClient.Load()
+	if p == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	select {
+	case p.flushCh <- done:
+	case <-ctx.Done():
+		return fmt.Errorf("%d entries not sent: %w", p.pending.Load(), ctx.Err())
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("%d entries not sent: %w", p.pending.Load(), ctx.Err())
+	}
+	return nil
+}
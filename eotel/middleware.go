@@ -0,0 +1,249 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type MiddlewareOption func(*middlewareConfig)
+
+type middlewareConfig struct {
+	skipPaths map[string]struct{}
+	spanName  func(rc RouterContext) string
+	filters   []func(rc RouterContext) bool
+	session   func(rc RouterContext) string
+	proxySpan bool
+	bodies    *BodyCapture
+	accessLog string
+
+	streamHeartbeat time.Duration
+}
+
+// WithSkipPaths disables instrumentation for exact request paths such as
+// health checks and metrics endpoints.
+func WithSkipPaths(paths ...string) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		for _, p := range paths {
+			cfg.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+func defaultSpanName(rc RouterContext) string {
+	return fmt.Sprintf("%s %s", rc.Request().Method, rc.Route())
+}
+
+func (cfg *middlewareConfig) skip(rc RouterContext) bool {
+	if _, ok := cfg.skipPaths[rc.Request().URL.Path]; ok {
+		return true
+	}
+	for _, f := range cfg.filters {
+		if !f(rc) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpServerMetrics are the RED instruments recorded by router middlewares.
+type httpServerMetrics struct {
+	requests     metric.Int64Counter
+	duration     metric.Float64Histogram
+	inFlight     metric.Int64UpDownCounter
+	responseSize metric.Int64Histogram
+}
+
+func newHTTPServerMetrics(m metric.Meter) httpServerMetrics {
+	var hm httpServerMetrics
+	hm.requests, _ = m.Int64Counter("http_server_requests_total")
+	hm.duration, _ = m.Float64Histogram("http_server_duration_ms", metric.WithUnit("ms"))
+	hm.inFlight, _ = m.Int64UpDownCounter("http_server_active_requests")
+	hm.responseSize, _ = m.Int64Histogram("http_server_response_size_bytes", metric.WithUnit("By"))
+	return hm
+}
+
+// NewRouterMiddleware returns the request instrumentation run for each
+// RouterContext, with request loggers named name. Router adapters such as
+// eotelgin call it from their middleware.
+func NewRouterMiddleware(name string, opts ...MiddlewareOption) func(rc RouterContext) {
+	cfg := &middlewareConfig{
+		skipPaths: map[string]struct{}{},
+		spanName:  defaultSpanName,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var hm httpServerMetrics
+	var hmOnce sync.Once
+
+	return func(rc RouterContext) {
+		if cfg.skip(rc) {
+			rc.Next()
+			return
+		}
+		ensureInit("Middleware")
+
+		// Instruments are created on first use so InitEOTEL may run after
+		// the router is built.
+		hmOnce.Do(func() { hm = newHTTPServerMetrics(getMeter()) })
+
+		start := time.Now()
+		route := rc.Route()
+		if route == "" {
+			route = "unmatched"
+		}
+		methodAttr := attribute.String("http.request.method", rc.Request().Method)
+		hm.inFlight.Add(rc.Request().Context(), 1, metric.WithAttributes(methodAttr))
+		var strm *stream
+		defer func() {
+			attrs := metric.WithAttributes(
+				methodAttr,
+				attribute.String("http.route", route),
+				attribute.Int("http.response.status_code", rc.Status()),
+			)
+			ctx := context.WithoutCancel(rc.Request().Context())
+			hm.inFlight.Add(ctx, -1, metric.WithAttributes(methodAttr))
+			hm.requests.Add(ctx, 1, attrs)
+			if strm == nil {
+				hm.duration.Record(ctx, time.Since(start).Seconds()*1000, attrs)
+			}
+			if size := rc.Size(); size >= 0 {
+				hm.responseSize.Record(ctx, int64(size), attrs)
+			}
+		}()
+
+		// Registered before the panic handler so a panic's error entry and
+		// 500 status are seen when the buffer is released.
+		var bufferID trace.TraceID
+		defer func() {
+			if bufferID.IsValid() {
+				finishLogBuffer(bufferID, rc.Status() >= http.StatusInternalServerError)
+			}
+		}()
+
+		req := rc.Request()
+		reqCtx := req.Context()
+		conf := currentConfig()
+		ctx := otel.GetTextMapPropagator().Extract(reqCtx, propagation.HeaderCarrier(req.Header))
+		if conf.SamplingPriority != nil {
+			ctx = WithSamplingPriority(ctx, conf.SamplingPriority(req))
+		}
+
+		var captureID trace.TraceID
+		defer func() {
+			if captureID.IsValid() {
+				finishCapture(captureID)
+			}
+		}()
+
+		ctx, span := startServerSpan(ctx, cfg.proxySpan, cfg.spanName(rc),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(httpServerAttrs(req, rc.ClientIP())...),
+			trace.WithAttributes(attribute.String("http.route", route)),
+		)
+		defer span.End()
+
+		// Deferred after span.End so a panic is recovered, and the span
+		// given the response status, while the span is still recording.
+		defer func() {
+			if rec := recover(); rec != nil {
+				err := RecoverRouter(rc, rec)
+				span.SetAttributes(attribute.Int("http.response.status_code", rc.Status()))
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}()
+
+		recordQueueTime(ctx, span, req.Header, start)
+
+		if sc := span.SpanContext(); conf.TraceAwareLogs && sc.IsValid() && !sc.IsSampled() {
+			bufferID = span.SpanContext().TraceID()
+			startLogBuffer(bufferID)
+		}
+
+		if conf.CaptureHeader != "" && captureRequested(req.Header.Get(conf.CaptureHeader)) {
+			captureID = span.SpanContext().TraceID()
+			startCapture(captureID)
+		}
+
+		ctx = withSentryRequest(ctx, req, rc.ClientIP())
+		ctx = WithRequestScope(ctx)
+
+		logger := Safe(New(ctx, name)).
+			TraceName(name).
+			WithHTTPRequest(req, rc.ClientIP())
+		logger = bindRequestID(rc.Writer().Header(), span, logger, requestIDFor(req.Header))
+		ctx, logger = bindIdempotencyKey(ctx, req.Header, span, logger, route)
+
+		if cfg.session != nil {
+			if sid := cfg.session(rc); sid != "" {
+				hashed := HashSessionID(sid)
+				span.SetAttributes(attribute.String("session.id", hashed))
+				logger = logger.WithField("session.id", hashed)
+			}
+		}
+
+		if cfg.streamHeartbeat > 0 && isStream(req) {
+			ctx, strm = startStream(ctx, span, req, route, cfg.streamHeartbeat)
+			defer strm.finish(ctx)
+			if streamKind(req) == "sse" {
+				sseCtx, sseStream := ctx, strm
+				rc.OnFlush(func() { sseStream.count(sseCtx, "sent") })
+			}
+		}
+
+		ctx = Inject(ctx, logger)
+		rc.SetRequest(req.WithContext(ctx))
+		bodies := tapRequest(cfg.bodies, rc.Request())
+		if bodies != nil {
+			rc.TapWrites(func(p []byte) { _, _ = bodies.resp.Write(p) })
+		}
+		// Runs before span.End; children of the request logger share its
+		// aggregator.
+		defer logger.FlushAggregates()
+
+		rc.Next()
+
+		req = rc.Request()
+		recordResponse(span, rc)
+		logger = tagAborted(span, logger, abortedBy(reqCtx, req.Context()))
+		if strm == nil {
+			checkSLA(span, logger, req.Method, route, time.Since(start))
+			observeRouteSLO(ctx, req.Method, route, time.Since(start), rc.Status())
+		}
+		logger = bodies.attach(span, logger, req, rc.Writer().Header(), rc.Status())
+
+		if cfg.accessLog != "" {
+			logAccess(logger, cfg.accessLog, rc, route, start)
+			return
+		}
+		logger.Info("request completed")
+	}
+}
+
+// recordResponse tags the server span with the response status and records
+// the errors handlers attached to the request as span events.
+func recordResponse(span trace.Span, rc RouterContext) {
+	status := rc.Status()
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	for _, e := range rc.Errors() {
+		span.AddEvent("exception", trace.WithAttributes(
+			attribute.String("exception.type", fmt.Sprintf("%T", e.Err)),
+			attribute.String("exception.message", e.Err.Error()),
+			attribute.Int64("gin.error.type", int64(e.Type)),
+		))
+	}
+}
@@ -0,0 +1,69 @@
+package eotel
+
+import (
+	"strings"
+)
+
+const defaultSpanAttributeNamespace = "app"
+
+// semconvNamespaces are the first segments of OTel semantic convention keys;
+// fields already named after a convention keep their key on spans.
+var semconvNamespaces = map[string]struct{}{
+	"client":       {},
+	"cloud":        {},
+	"code":         {},
+	"db":           {},
+	"deployment":   {},
+	"enduser":      {},
+	"error":        {},
+	"exception":    {},
+	"feature_flag": {},
+	"http":         {},
+	"job":          {},
+	"messaging":    {},
+	"network":      {},
+	"peer":         {},
+	"rpc":          {},
+	"server":       {},
+	"service":      {},
+	"session":      {},
+	"url":          {},
+	"user_agent":   {},
+}
+
+// defaultSpanAttributeMapping maps common ad-hoc field names onto their
+// semconv attribute.
+var defaultSpanAttributeMapping = map[string]string{
+	"user_id":    "enduser.id",
+	"method":     "http.request.method",
+	"path":       "url.path",
+	"ip":         "client.address",
+	"ua":         "user_agent.original",
+	"session_id": "session.id",
+}
+
+// spanAttrKey returns the span attribute key for field key: the configured
+// mapping first, then semconv keys unchanged, then the key under the
+// namespace (app.* by default). Config.SpanAttributeNamespace "-" disables
+// prefixing.
+func spanAttrKey(key string) string {
+	cfg := currentConfig()
+	if k, ok := cfg.SpanAttributeMapping[key]; ok {
+		return k
+	}
+	if k, ok := defaultSpanAttributeMapping[key]; ok {
+		return k
+	}
+	ns := cfg.SpanAttributeNamespace
+	if ns == "-" {
+		return key
+	}
+	if ns == "" {
+		ns = defaultSpanAttributeNamespace
+	}
+	first, _, _ := strings.Cut(key, ".")
+	if _, ok := semconvNamespaces[first]; ok || first == ns {
+		return key
+	}
+	return ns + "." + key
+}
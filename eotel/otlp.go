@@ -0,0 +1,166 @@
+package eotel
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func newTraceExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	if cfg.DevMode {
+		return newDevTraceExporter()
+	}
+	tlsCfg, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.OtelProtocol {
+	case "", "grpc":
+		conn, err := dialCollector(cfg, SignalTracing, tlsCfg, usageOTLPTraces)
+		if err != nil {
+			return nil, err
+		}
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithGRPCConn(conn),
+			otlptracegrpc.WithTimeout(timeoutOr(cfg.ExporterTimeouts.OTLP, defaultOTLPTimeout)),
+			otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig(cfg.OTLPRetry.config())),
+		}
+		if len(cfg.OtelHeaders) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.OtelHeaders))
+		}
+		exp, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			closeCollector(conn)
+			return nil, err
+		}
+		return connSpanExporter{exp, conn}, nil
+	case "http":
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.OtelCollector),
+			otlptracehttp.WithTimeout(timeoutOr(cfg.ExporterTimeouts.OTLP, defaultOTLPTimeout)),
+			otlptracehttp.WithRetry(otlptracehttp.RetryConfig(cfg.OTLPRetry.config())),
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+		} else {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.OtelHeaders) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.OtelHeaders))
+		}
+		if cfg.EnableUsageReporting {
+			opts = append(opts, otlptracehttp.WithHTTPClient(usageHTTPClient(tlsCfg, usageOTLPTraces)))
+		}
+		if cfg.OtelTracesURLPath != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(cfg.OtelTracesURLPath))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported otel protocol %q", cfg.OtelProtocol)
+	}
+}
+
+func newMetricExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	if cfg.DevMode {
+		return newDevMetricExporter()
+	}
+	tlsCfg, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.OtelProtocol {
+	case "", "grpc":
+		conn, err := dialCollector(cfg, SignalMetrics, tlsCfg, usageOTLPMetrics)
+		if err != nil {
+			return nil, err
+		}
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithGRPCConn(conn),
+			otlpmetricgrpc.WithTimeout(timeoutOr(cfg.ExporterTimeouts.OTLP, defaultOTLPTimeout)),
+			otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig(cfg.OTLPRetry.config())),
+		}
+		if len(cfg.OtelHeaders) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.OtelHeaders))
+		}
+		exp, err := otlpmetricgrpc.New(ctx, opts...)
+		if err != nil {
+			closeCollector(conn)
+			return nil, err
+		}
+		return connMetricExporter{exp, conn}, nil
+	case "http":
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(cfg.OtelCollector),
+			otlpmetrichttp.WithTimeout(timeoutOr(cfg.ExporterTimeouts.OTLP, defaultOTLPTimeout)),
+			otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig(cfg.OTLPRetry.config())),
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+		} else {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(cfg.OtelHeaders) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.OtelHeaders))
+		}
+		if cfg.EnableUsageReporting {
+			opts = append(opts, otlpmetrichttp.WithHTTPClient(usageHTTPClient(tlsCfg, usageOTLPMetrics)))
+		}
+		if cfg.OtelMetricsURLPath != "" {
+			opts = append(opts, otlpmetrichttp.WithURLPath(cfg.OtelMetricsURLPath))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported otel protocol %q", cfg.OtelProtocol)
+	}
+}
+
+// buildTLSConfig returns the TLS configuration of the collector connection,
+// or nil for a plaintext one when Config.OtelInsecure is set.
+func buildTLSConfig(cfg Config) (*tls.Config, error) {
+	if cfg.OtelInsecure {
+		return nil, nil
+	}
+	t := cfg.OtelTLS
+	if t == nil {
+		return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+	}
+
+	tlsCfg := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", t.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
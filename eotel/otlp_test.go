@@ -0,0 +1,27 @@
+package eotel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollectorTLS(t *testing.T) {
+	tlsCfg, err := buildTLSConfig(Config{})
+	if err != nil || tlsCfg == nil || tlsCfg.RootCAs != nil || tlsCfg.InsecureSkipVerify {
+		t.Errorf("default: tls = %+v, err = %v, want TLS verified against the system roots", tlsCfg, err)
+	}
+
+	if tlsCfg, err := buildTLSConfig(Config{OtelInsecure: true}); err != nil || tlsCfg != nil {
+		t.Errorf("insecure: tls = %+v, err = %v, want plaintext", tlsCfg, err)
+	}
+
+	tlsCfg, err = buildTLSConfig(Config{OtelTLS: &TLSConfig{ServerName: "collector.internal"}})
+	if err != nil || tlsCfg == nil || tlsCfg.ServerName != "collector.internal" {
+		t.Errorf("configured: tls = %+v, err = %v, want the configured server name", tlsCfg, err)
+	}
+
+	err = Config{ServiceName: "svc", OtelInsecure: true, OtelTLS: &TLSConfig{}}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("Validate = %v, want insecure with tls rejected", err)
+	}
+}
@@ -0,0 +1,143 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	globalLoggerProvider *sdklog.LoggerProvider
+	otlpLogger           otellog.Logger
+)
+
+func newLogExporter(ctx context.Context, cfg Config) (sdklog.Exporter, error) {
+	tlsCfg, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.OtelProtocol {
+	case "", "grpc":
+		conn, err := dialCollector(cfg, SignalOTLPLogs, tlsCfg, usageOTLPLogs)
+		if err != nil {
+			return nil, err
+		}
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithGRPCConn(conn),
+			otlploggrpc.WithTimeout(timeoutOr(cfg.ExporterTimeouts.OTLP, defaultOTLPTimeout)),
+			otlploggrpc.WithRetry(otlploggrpc.RetryConfig(cfg.OTLPRetry.config())),
+		}
+		if len(cfg.OtelHeaders) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.OtelHeaders))
+		}
+		exp, err := otlploggrpc.New(ctx, opts...)
+		if err != nil {
+			closeCollector(conn)
+			return nil, err
+		}
+		return connLogExporter{exp, conn}, nil
+	case "http":
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(cfg.OtelCollector),
+			otlploghttp.WithTimeout(timeoutOr(cfg.ExporterTimeouts.OTLP, defaultOTLPTimeout)),
+			otlploghttp.WithRetry(otlploghttp.RetryConfig(cfg.OTLPRetry.config())),
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(tlsCfg))
+		} else {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(cfg.OtelHeaders) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(cfg.OtelHeaders))
+		}
+		if cfg.EnableUsageReporting {
+			opts = append(opts, otlploghttp.WithHTTPClient(usageHTTPClient(tlsCfg, usageOTLPLogs)))
+		}
+		if cfg.OtelLogsURLPath != "" {
+			opts = append(opts, otlploghttp.WithURLPath(cfg.OtelLogsURLPath))
+		}
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported otel protocol %q", cfg.OtelProtocol)
+	}
+}
+
+var otlpSeverity = map[string]otellog.Severity{
+	"debug": otellog.SeverityDebug,
+	"info":  otellog.SeverityInfo,
+	"warn":  otellog.SeverityWarn,
+	"error": otellog.SeverityError,
+	"fatal": otellog.SeverityFatal,
+}
+
+// emitOTLPLog sends the entry through the OTel Logs SDK. The span is put in
+// the context so the SDK fills in the trace and span IDs.
+func emitOTLPLog(ctx context.Context, span trace.Span, level, msg string, fields []zap.Field) {
+	if otlpLogger == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = trace.ContextWithSpan(ctx, span)
+
+	var rec otellog.Record
+	now := time.Now()
+	rec.SetTimestamp(now)
+	rec.SetObservedTimestamp(now)
+	rec.SetSeverity(otlpSeverity[level])
+	rec.SetSeverityText(level)
+	rec.SetBody(otellog.StringValue(msg))
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	attrs := make([]otellog.KeyValue, 0, len(enc.Fields))
+	for k, v := range enc.Fields {
+		attrs = append(attrs, otellog.KeyValue{Key: k, Value: otlpLogValue(v)})
+	}
+	rec.AddAttributes(attrs...)
+
+	otlpLogger.Emit(ctx, rec)
+}
+
+func otlpLogValue(v any) otellog.Value {
+	switch x := v.(type) {
+	case string:
+		return otellog.StringValue(x)
+	case bool:
+		return otellog.BoolValue(x)
+	case int:
+		return otellog.IntValue(x)
+	case int64:
+		return otellog.Int64Value(x)
+	case int32:
+		return otellog.Int64Value(int64(x))
+	case uint32:
+		return otellog.Int64Value(int64(x))
+	case uint64:
+		if x <= math.MaxInt64 {
+			return otellog.Int64Value(int64(x))
+		}
+	case float64:
+		return otellog.Float64Value(x)
+	case float32:
+		return otellog.Float64Value(float64(x))
+	case time.Duration:
+		return otellog.StringValue(x.String())
+	case time.Time:
+		return otellog.StringValue(x.Format(time.RFC3339Nano))
+	}
+	return otellog.StringValue(fmt.Sprintf("%v", v))
+}
@@ -0,0 +1,64 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	panicMetricsOnce sync.Once
+	panicsTotal      metric.Int64Counter
+)
+
+// recoverRequest reports a panic recovered by a server middleware: the
+// panic_total counter by route, an error entry and a Sentry event carrying
+// the stack, and the error status on the request span. It returns the error
+// built from rec.
+func recoverRequest(ctx context.Context, rec any, route string) error {
+	err := fmt.Errorf("panic: %v", rec)
+	stack := string(debug.Stack())
+	lastBreathPanic(trace.SpanContextFromContext(ctx), err, stack)
+
+	panicMetricsOnce.Do(func() {
+		panicsTotal, _ = getMeter().Int64Counter("panic_total",
+			metric.WithDescription("Panics recovered by the server middlewares, by route."))
+	})
+	if panicsTotal != nil {
+		panicsTotal.Add(context.WithoutCancel(ctx), 1, metric.WithAttributes(attribute.String("http.route", route)))
+	}
+
+	log := Safe(FromContext(ctx, "panic")).WithField("stack", stack).withErrorFields(err)
+	if exp := log.exporter; exporterActive(exp) && firstErrorOfRequest(ctx, err) {
+		withinBudget(func() {
+			captureErrorWith(ctx, exp, err,
+				map[string]string{"panic": "true", "http.route": route},
+				map[string]any{"error": err.Error(), "stack": stack})
+		})
+	}
+	log.Error("unhandled panic")
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// writePanicResponse answers a request whose handler panicked, through
+// Config.PanicResponse when set.
+func writePanicResponse(w http.ResponseWriter, r *http.Request, rec any) {
+	if h := currentConfig().PanicResponse; h != nil {
+		h(w, r, rec)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte(`{"error":"internal server error"}`))
+}
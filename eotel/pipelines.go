@@ -0,0 +1,197 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+// pipelines holds what InitEOTEL builds before installing any of it, so a
+// failed init can stop it again without touching the running configuration.
+type pipelines struct {
+	tp      *sdktrace.TracerProvider
+	sampler sdktrace.Sampler
+	traces  *swapSpanExporter
+
+	mp             *sdkmetric.MeterProvider
+	metrics        *swapMetricExporter
+	promHandler    http.Handler
+	stopPrometheus func(context.Context) error
+	pusher         func(context.Context) error
+
+	lp   *sdklog.LoggerProvider
+	logs *swapLogExporter
+
+	loki       *lokiPusher
+	sentry     *sentryWorker
+	lastBreath *os.File
+
+	// prevConns are the collector connections open before the build; the
+	// others were dialed by it.
+	prevConns map[*grpc.ClientConn]Signal
+}
+
+func newPipelines() *pipelines {
+	return &pipelines{prevConns: openCollectors()}
+}
+
+// collectorConns returns the collector connections dialed by the build.
+func (p *pipelines) collectorConns() map[*grpc.ClientConn]Signal {
+	conns := openCollectors()
+	for c := range p.prevConns {
+		delete(conns, c)
+	}
+	return conns
+}
+
+// discard stops everything built so far. Shutting the providers down shuts
+// their exporters down, which closes the collector connections.
+func (p *pipelines) discard(ctx context.Context) {
+	if p.loki != nil {
+		p.loki.stop()
+	}
+	if p.sentry != nil {
+		p.sentry.stop()
+	}
+	if p.lp != nil {
+		_ = p.lp.Shutdown(ctx)
+	}
+	if p.tp != nil {
+		_ = p.tp.Shutdown(ctx)
+	}
+	closeSampler(p.sampler)
+	if p.stopPrometheus != nil {
+		_ = p.stopPrometheus(ctx)
+	}
+	if p.mp != nil {
+		// Nothing was recorded through the provider; a cancelled context
+		// skips its final export, which could otherwise wait out the export
+		// timeout on an unreachable collector.
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_ = p.mp.Shutdown(cancelled)
+	}
+	if p.lastBreath != nil {
+		_ = p.lastBreath.Close()
+	}
+}
+
+func (p *pipelines) buildMetrics(ctx context.Context, cfg Config, res *resource.Resource) error {
+	filter, err := exemplarFilter(cfg.MetricExemplars)
+	if err != nil {
+		return subsystemErr(SubsystemMetrics, err)
+	}
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res), sdkmetric.WithExemplarFilter(filter)}
+	// The push gateway reader holds no resources, so it is built before the
+	// readers that would need stopping if it failed.
+	if cfg.MetricsPushGatewayURL != "" {
+		reader, pusher, err := newPushGateway(cfg)
+		if err != nil {
+			return subsystemErr(SubsystemMetrics, fmt.Errorf("pushgateway: %w", err))
+		}
+		p.pusher = pusher
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+	switch {
+	case cfg.EnableMetrics && cfg.MetricsExporter == metricsExporterPrometheus:
+		reader, h, stop, err := newPrometheusReader(cfg)
+		if err != nil {
+			return subsystemErr(SubsystemMetrics, fmt.Errorf("prometheus exporter: %w", err))
+		}
+		p.promHandler, p.stopPrometheus = h, stop
+		opts = append(opts, sdkmetric.WithReader(reader))
+	case cfg.EnableMetrics:
+		inner, err := newMetricExporter(ctx, cfg)
+		if err != nil {
+			return subsystemErr(SubsystemMetrics, fmt.Errorf("metric exporter: %w", err))
+		}
+		p.metrics = newSwapMetricExporter(inner)
+		var mExp sdkmetric.Exporter = statusMetricExporter{Exporter: p.metrics, status: &otlpMetricStatus}
+		if b := newBreaker("otlp_metrics", cfg.CircuitBreaker, &otlpMetricStatus, getMeter()); b != nil {
+			mExp = breakerMetricExporter{Exporter: mExp, breaker: b, status: &otlpMetricStatus}
+		}
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(mExp)))
+	}
+	p.mp = sdkmetric.NewMeterProvider(opts...)
+	return nil
+}
+
+func (p *pipelines) buildTracing(ctx context.Context, cfg Config, res *resource.Resource, meter metric.Meter) error {
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return subsystemErr(SubsystemTracing, fmt.Errorf("sampler: %w", err))
+	}
+	p.sampler = sampler
+	spill, err := openSpill("traces", cfg.DiskBuffer, meter)
+	if err != nil {
+		return subsystemErr(SubsystemTracing, err)
+	}
+	inner, err := newTraceExporter(ctx, cfg)
+	if err != nil {
+		spill.close()
+		return subsystemErr(SubsystemTracing, fmt.Errorf("trace exporter: %w", err))
+	}
+	p.traces = newSwapSpanExporter(inner)
+	var tExp sdktrace.SpanExporter = statusSpanExporter{SpanExporter: p.traces, status: &otlpTraceStatus}
+	if b := newBreaker("otlp_traces", cfg.CircuitBreaker, &otlpTraceStatus, meter); b != nil || spill != nil {
+		tExp = breakerSpanExporter{SpanExporter: tExp, breaker: b, status: &otlpTraceStatus, spill: spill}
+	}
+	var sp sdktrace.SpanProcessor
+	if cfg.SyncExport {
+		sp = sdktrace.NewSimpleSpanProcessor(tExp)
+	} else {
+		sp = sdktrace.NewBatchSpanProcessor(tExp)
+	}
+	if cfg.ShortSpans.Threshold > 0 {
+		sp = newShortSpanProcessor(sp, cfg.ShortSpans, meter)
+	}
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(traceSampler),
+		sdktrace.WithSpanProcessor(traceStateProcessor{}),
+		sdktrace.WithSpanProcessor(baggageProcessor{}),
+		sdktrace.WithSpanProcessor(staticFieldsProcessor{}),
+		sdktrace.WithSpanProcessor(spanLimitsProcessor{}),
+	}
+	if cfg.SpanLimits.enabled() {
+		tpOpts = append(tpOpts, sdktrace.WithRawSpanLimits(cfg.SpanLimits.sdk()))
+	}
+	if cfg.MaxSpansPerTrace > 0 {
+		limiter := newSpanLimiter(traceSampler, cfg.MaxSpansPerTrace)
+		tpOpts = append(tpOpts, sdktrace.WithSampler(limiter), sdktrace.WithSpanProcessor(limiter))
+	}
+	for _, usp := range userSpanProcessors(cfg) {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(usp))
+	}
+	p.tp = sdktrace.NewTracerProvider(append(tpOpts, sdktrace.WithSpanProcessor(sp))...)
+	return nil
+}
+
+func (p *pipelines) buildLogs(ctx context.Context, cfg Config, res *resource.Resource, meter metric.Meter) error {
+	inner, err := newLogExporter(ctx, cfg)
+	if err != nil {
+		return subsystemErr(SubsystemOTLPLogs, fmt.Errorf("log exporter: %w", err))
+	}
+	p.logs = newSwapLogExporter(inner)
+	var lExp sdklog.Exporter = statusLogExporter{Exporter: p.logs, status: &otlpLogStatus}
+	if b := newBreaker("otlp_logs", cfg.CircuitBreaker, &otlpLogStatus, meter); b != nil {
+		lExp = breakerLogExporter{Exporter: lExp, breaker: b, status: &otlpLogStatus}
+	}
+	var lp sdklog.Processor = sdklog.NewBatchProcessor(lExp)
+	if cfg.SyncExport {
+		lp = sdklog.NewSimpleProcessor(lExp)
+	}
+	p.lp = sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(lp),
+	)
+	return nil
+}
@@ -0,0 +1,107 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	metricsExporterOTLP       = "otlp"
+	metricsExporterPrometheus = "prometheus"
+)
+
+var promHandler atomic.Pointer[http.Handler]
+
+// PrometheusBackend builds the readers behind MetricsExporter "prometheus"
+// and MetricsPushGatewayURL. The eotelprom module provides it and registers
+// it when imported, as the root package does; the core only depends on this
+// interface.
+type PrometheusBackend interface {
+	// NewReader returns a pull reader on its own registry and the handler
+	// serving that registry.
+	NewReader() (sdkmetric.Reader, http.Handler, error)
+	// NewPusher returns a reader on its own registry and the function
+	// pushing that registry to the push gateway at url under job.
+	NewPusher(url, job string) (sdkmetric.Reader, func(context.Context) error, error)
+}
+
+var prometheusBackends struct {
+	mu      sync.Mutex
+	backend PrometheusBackend
+}
+
+// RegisterPrometheusBackend sets the backend InitEOTEL uses for the
+// Prometheus exporter and the push gateway.
+func RegisterPrometheusBackend(b PrometheusBackend) {
+	prometheusBackends.mu.Lock()
+	prometheusBackends.backend = b
+	prometheusBackends.mu.Unlock()
+}
+
+func prometheusBackend() (PrometheusBackend, error) {
+	prometheusBackends.mu.Lock()
+	defer prometheusBackends.mu.Unlock()
+	if prometheusBackends.backend == nil {
+		return nil, errors.New("no backend registered; import github.com/nicedev97/eotel-v2/eotelprom")
+	}
+	return prometheusBackends.backend, nil
+}
+
+// newPrometheusReader registers a pull reader on its own registry. The
+// returned handler serves it, through MetricsHandler once stored in
+// promHandler, and when Config.PrometheusListenAddr is set a dedicated
+// /metrics server serves it too; the returned function shuts that down.
+func newPrometheusReader(cfg Config) (sdkmetric.Reader, http.Handler, func(context.Context) error, error) {
+	b, err := prometheusBackend()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	reader, h, err := b.NewReader()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if cfg.PrometheusListenAddr == "" {
+		return reader, h, func(context.Context) error { return nil }, nil
+	}
+
+	ln, err := net.Listen("tcp", cfg.PrometheusListenAddr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", h)
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			getLogger().Sugar().Errorf("eotel: prometheus server: %v", err)
+		}
+	}()
+	// Shutdown only closes the listener once Serve has picked it up; close
+	// it too so the port is free when stop returns.
+	stop := func(ctx context.Context) error {
+		err := srv.Shutdown(ctx)
+		_ = ln.Close()
+		return err
+	}
+	return reader, h, stop, nil
+}
+
+// MetricsHandler serves the metrics in Prometheus text format when
+// Config.MetricsExporter is "prometheus"; otherwise it responds 404.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := promHandler.Load()
+		if h == nil {
+			http.NotFound(w, r)
+			return
+		}
+		(*h).ServeHTTP(w, r)
+	})
+}
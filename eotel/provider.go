@@ -0,0 +1,238 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+// Provider is a set of pipelines isolated from InitEOTEL and from other
+// providers: its own tracer and meter providers, resource, zap logger,
+// exporters and named instruments (Count, Counter...), all built from one
+// Config. A binary hosting several logical components, or tests running in
+// parallel, can give each its own service name and backends. Loggers from
+// Provider.New use it instead of the globals; the otel global providers are
+// left alone.
+//
+// Only the per-signal pipelines are isolated. The built-in Loki, Sentry and
+// OTLP logs pipelines, the Prometheus endpoint, Reload and signal toggles
+// are process-wide and stay with InitEOTEL; NewProvider rejects a Config
+// enabling them. Use Config.Exporters for other log destinations. Process
+// settings such as the minimum level and redaction also remain global.
+type Provider struct {
+	cfg Config
+
+	tp      *sdktrace.TracerProvider
+	sampler sdktrace.Sampler
+	mp      *sdkmetric.MeterProvider
+	tracer  trace.Tracer
+	meter   metric.Meter
+	logger  *zap.Logger
+
+	exporter     Exporter
+	instruments  *instrumentRegistry
+	logCounter   metric.Int64Counter
+	durationHist metric.Float64Histogram
+}
+
+// NewProvider builds an isolated Provider from cfg. Call Shutdown to flush
+// and stop it.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	switch {
+	case cfg.EnableLoki:
+		return nil, errors.New("provider: Loki is process-wide, use InitEOTEL or Config.Exporters")
+	case cfg.EnableSentry:
+		return nil, errors.New("provider: Sentry is process-wide, use InitEOTEL or Config.Exporters")
+	case cfg.EnableOTLPLogs:
+		return nil, errors.New("provider: OTLP logs are process-wide, use InitEOTEL")
+	case cfg.MetricsExporter == metricsExporterPrometheus || cfg.MetricsPushGatewayURL != "":
+		return nil, errors.New("provider: Prometheus metrics are process-wide, use InitEOTEL")
+	}
+
+	p := &Provider{cfg: cfg, logger: cfg.Logger, instruments: newInstrumentRegistry()}
+	if p.logger == nil && cfg.Log.enabled() {
+		logger, err := newLogger(cfg.Log)
+		if err != nil {
+			return nil, fmt.Errorf("logger: %w", err)
+		}
+		p.logger = logger
+	}
+	if p.logger == nil {
+		p.logger = zap.NewNop()
+	}
+
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("resource.New: %w", err)
+	}
+
+	switch {
+	case cfg.TracerProvider != nil:
+		p.tracer = cfg.TracerProvider.Tracer(cfg.ServiceName)
+	case cfg.EnableTracing:
+		exp, err := newTraceExporter(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("trace exporter: %w", err)
+		}
+		var sp sdktrace.SpanProcessor
+		if cfg.SyncExport {
+			sp = sdktrace.NewSimpleSpanProcessor(exp)
+		} else {
+			sp = sdktrace.NewBatchSpanProcessor(exp)
+		}
+		if p.sampler, err = newSampler(cfg); err != nil {
+			return nil, fmt.Errorf("sampler: %w", err)
+		}
+		tpOpts := []sdktrace.TracerProviderOption{
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(p.sampler),
+			sdktrace.WithSpanProcessor(traceStateProcessor{}),
+			sdktrace.WithSpanProcessor(baggageProcessor{}),
+		}
+		if cfg.SpanLimits.enabled() {
+			tpOpts = append(tpOpts, sdktrace.WithRawSpanLimits(cfg.SpanLimits.sdk()))
+		}
+		for _, usp := range userSpanProcessors(cfg) {
+			tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(usp))
+		}
+		p.tp = sdktrace.NewTracerProvider(append(tpOpts, sdktrace.WithSpanProcessor(sp))...)
+		p.tracer = p.tp.Tracer(cfg.ServiceName)
+	default:
+		p.tracer = tracenoop.NewTracerProvider().Tracer(cfg.ServiceName)
+	}
+
+	switch {
+	case cfg.MeterProvider != nil:
+		p.meter = cfg.MeterProvider.Meter(cfg.ServiceName)
+	case cfg.EnableMetrics:
+		filter, err := exemplarFilter(cfg.MetricExemplars)
+		if err != nil {
+			_ = p.shutdownTraces(ctx)
+			return nil, err
+		}
+		exp, err := newMetricExporter(ctx, cfg)
+		if err != nil {
+			_ = p.shutdownTraces(ctx)
+			return nil, fmt.Errorf("metric exporter: %w", err)
+		}
+		p.mp = sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(res),
+			sdkmetric.WithExemplarFilter(filter),
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp)),
+		)
+		p.meter = p.mp.Meter(cfg.ServiceName)
+	default:
+		p.meter = metricnoop.NewMeterProvider().Meter(cfg.ServiceName)
+	}
+	// Not logMetrics: its cache holds the global meter's instruments.
+	if p.logCounter, p.durationHist, err = initMetrics(p.meter); err != nil {
+		instrumentFailed(logRecordsMetric, "create")
+	}
+
+	if len(cfg.Exporters) > 0 {
+		p.exporter = providerExporter(cfg.Exporters)
+	}
+	return p, nil
+}
+
+// New returns a logger whose telemetry goes through p, named name.
+func (p *Provider) New(ctx context.Context, name string) *Eotel {
+	return &Eotel{
+		ctx:          ctx,
+		logger:       p.logger,
+		tracer:       p.tracer,
+		meter:        p.meter,
+		logCounter:   p.logCounter,
+		durationHist: p.durationHist,
+		start:        time.Now(),
+		exporter:     p.exporter,
+		name:         name,
+		aggs:         &aggregator{},
+		provider:     p,
+	}
+}
+
+// Tracer returns p's tracer, for instrumentation outside eotel loggers.
+func (p *Provider) Tracer() trace.Tracer { return p.tracer }
+
+// Meter returns p's meter.
+func (p *Provider) Meter() metric.Meter { return p.meter }
+
+// Logger returns p's zap logger.
+func (p *Provider) Logger() *zap.Logger { return p.logger }
+
+// Config returns the configuration p was built from.
+func (p *Provider) Config() Config { return p.cfg }
+
+// ForceFlush exports everything p has buffered.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	var errs []error
+	if p.tp != nil {
+		errs = append(errs, p.tp.ForceFlush(ctx))
+	}
+	if p.mp != nil {
+		errs = append(errs, p.mp.ForceFlush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown flushes and stops p's pipelines. Loggers from p keep working but
+// no longer export.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	var errs []error
+	if err := p.shutdownTraces(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("tracer provider: %w", err))
+	}
+	if p.mp != nil {
+		if err := p.mp.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("meter provider: %w", err))
+		}
+	}
+	_ = p.logger.Sync()
+	return errors.Join(errs...)
+}
+
+func (p *Provider) shutdownTraces(ctx context.Context) error {
+	if p.tp == nil {
+		return nil
+	}
+	err := p.tp.Shutdown(ctx)
+	closeSampler(p.sampler)
+	return err
+}
+
+// providerExporter forwards to a Provider's own exporters.
+type providerExporter []Exporter
+
+func (e providerExporter) Send(level, msg, traceID, spanID string) {
+	e.SendContext(context.Background(), level, msg, traceID, spanID)
+}
+
+func (e providerExporter) SendContext(ctx context.Context, level, msg, traceID, spanID string) {
+	e.SendFields(ctx, level, msg, traceID, spanID, nil)
+}
+
+func (e providerExporter) SendFields(ctx context.Context, level, msg, traceID, spanID string, fields map[string]any) {
+	for _, exp := range e {
+		registeredExporter{exp: exp}.SendFields(ctx, level, msg, traceID, spanID, fields)
+	}
+}
+
+func (e providerExporter) CaptureError(err error, tags map[string]string, extras map[string]any) {
+	e.CaptureErrorContext(context.Background(), err, tags, extras)
+}
+
+func (e providerExporter) CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	for _, exp := range e {
+		registeredExporter{exp: exp}.CaptureErrorContext(ctx, err, tags, extras)
+	}
+}
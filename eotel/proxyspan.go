@@ -0,0 +1,31 @@
+package eotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithProxyServerSpan makes the router middleware continue the server span
+// of an OTel-instrumented ingress (Envoy, the NGINX OTel module) instead of
+// starting a server span of its own under it: when the request carries a
+// remote parent, that span is the request span and handler spans become its
+// direct children. Only enable it when the proxy is what sets traceparent.
+// Config.ProxyServerSpan does the same for every middleware.
+func WithProxyServerSpan() MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.proxySpan = true
+	}
+}
+
+// startServerSpan starts the request span, or returns the remote parent
+// from ctx when reuse is set. The returned span is then non-recording, so
+// attributes set on it are dropped; the proxy reports status and timing.
+func startServerSpan(ctx context.Context, reuse bool, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if reuse || currentConfig().ProxyServerSpan {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() && sc.IsRemote() {
+			return ctx, trace.SpanFromContext(ctx)
+		}
+	}
+	return getTracer().Start(ctx, name, opts...)
+}
@@ -0,0 +1,48 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+var globalPusher func(context.Context) error
+
+// newPushGateway returns a Prometheus reader and the function pushing its
+// registry to Config.MetricsPushGatewayURL on PushMetrics and on shutdown,
+// once installed as globalPusher.
+func newPushGateway(cfg Config) (sdkmetric.Reader, func(context.Context) error, error) {
+	b, err := prometheusBackend()
+	if err != nil {
+		return nil, nil, err
+	}
+	job := cfg.JobName
+	if job == "" {
+		job = cfg.ServiceName
+	}
+	return b.NewPusher(cfg.MetricsPushGatewayURL, job)
+}
+
+func pushGateway(ctx context.Context) error {
+	if globalPusher == nil {
+		return nil
+	}
+	return globalPusher(ctx)
+}
+
+// PushMetrics force-collects and exports metrics once, for short-lived jobs
+// that may exit before the periodic reader fires. It is also done on
+// shutdown.
+func PushMetrics(ctx context.Context) error {
+	var errs []error
+	if mp := globalMeterProvider; mp != nil {
+		if err := mp.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := pushGateway(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
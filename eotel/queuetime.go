@@ -0,0 +1,82 @@
+package eotel
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxQueueTime discards header values too far in the past to be a real
+// queueing delay (bad clocks, replayed headers).
+const maxQueueTime = time.Hour
+
+var (
+	queueTimeOnce sync.Once
+	queueTimeHist metric.Float64Histogram
+)
+
+// queueStart parses the time a load balancer accepted the request from
+// X-Request-Start or X-Queue-Start. Values may be prefixed with "t=" and be
+// in seconds (with fraction), milliseconds, microseconds or nanoseconds since
+// the epoch; the unit is inferred from the magnitude.
+func queueStart(h http.Header) (time.Time, bool) {
+	v := h.Get("X-Request-Start")
+	if v == "" {
+		v = h.Get("X-Queue-Start")
+	}
+	v = strings.TrimPrefix(strings.TrimSpace(v), "t=")
+	if v == "" {
+		return time.Time{}, false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 {
+		return time.Time{}, false
+	}
+	var ns float64
+	switch {
+	case f > 1e17:
+		ns = f
+	case f > 1e14:
+		ns = f * 1e3
+	case f > 1e11:
+		ns = f * 1e6
+	default:
+		ns = f * 1e9
+	}
+	return time.Unix(0, int64(ns)), true
+}
+
+// recordQueueTime sets http.server.queue_time_ms on span and records it in the
+// http_server_queue_time_ms histogram when the request carries a queue start
+// header. Negative values from clock skew are recorded as zero.
+func recordQueueTime(ctx context.Context, span trace.Span, h http.Header, now time.Time) {
+	start, ok := queueStart(h)
+	if !ok {
+		return
+	}
+	d := now.Sub(start)
+	if d > maxQueueTime || d < -maxQueueTime {
+		return
+	}
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Seconds() * 1000
+	span.SetAttributes(attribute.Float64("http.server.queue_time_ms", ms))
+
+	queueTimeOnce.Do(func() {
+		queueTimeHist, _ = getMeter().Float64Histogram("http_server_queue_time_ms",
+			metric.WithUnit("ms"),
+			metric.WithDescription("Time between the load balancer accepting the request and the application handling it."))
+	})
+	if queueTimeHist != nil {
+		queueTimeHist.Record(context.WithoutCancel(ctx), ms)
+	}
+}
@@ -0,0 +1,112 @@
+package eotel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+const defaultRedactionReplacement = "[REDACTED]"
+
+// DefaultRedactKeys is a starting point for Redaction.Keys.
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie",
+	"api_key", "apikey", "card_number", "credit_card", "cvv",
+}
+
+// CardNumberPattern matches 13 to 19 digit card numbers, optionally grouped
+// with spaces or dashes; add it to Redaction.Patterns to scrub them.
+const CardNumberPattern = `\b(?:\d[ -]?){12,18}\d\b`
+
+// Redaction scrubs sensitive data from fields, span attributes, Loki lines and
+// Sentry extras before anything is exported.
+type Redaction struct {
+	// Keys are matched case-insensitively as substrings of field keys; the
+	// whole value of a matching field is replaced.
+	Keys []string `yaml:"keys"`
+	// Patterns are regular expressions; matches inside string values and
+	// log messages are replaced.
+	Patterns []string `yaml:"patterns"`
+	// Replacement defaults to "[REDACTED]".
+	Replacement string `yaml:"replacement"`
+}
+
+type redactor struct {
+	keys        []string
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+var activeRedactor atomic.Pointer[redactor]
+
+// newRedactor returns nil when r redacts nothing.
+func newRedactor(r Redaction) (*redactor, error) {
+	if len(r.Keys) == 0 && len(r.Patterns) == 0 {
+		return nil, nil
+	}
+	rd := &redactor{replacement: r.Replacement}
+	if rd.replacement == "" {
+		rd.replacement = defaultRedactionReplacement
+	}
+	for _, k := range r.Keys {
+		if k != "" {
+			rd.keys = append(rd.keys, strings.ToLower(k))
+		}
+	}
+	for _, p := range r.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %q: %w", p, err)
+		}
+		rd.patterns = append(rd.patterns, re)
+	}
+	return rd, nil
+}
+
+func (rd *redactor) sensitiveKey(key string) bool {
+	if rd == nil || len(rd.keys) == 0 {
+		return false
+	}
+	key = strings.ToLower(key)
+	for _, k := range rd.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rd *redactor) scrub(s string) string {
+	if rd == nil {
+		return s
+	}
+	for _, re := range rd.patterns {
+		s = re.ReplaceAllString(s, rd.replacement)
+	}
+	return s
+}
+
+// redactValue returns the exported form of a key/value pair.
+func (rd *redactor) redactValue(key string, v any) any {
+	if rd.sensitiveKey(key) {
+		return rd.replacement
+	}
+	if s, ok := v.(string); ok {
+		return rd.scrub(s)
+	}
+	return v
+}
+
+// redactMap returns m with redactValue applied, or m itself when nothing is
+// configured.
+func (rd *redactor) redactMap(m map[string]any) map[string]any {
+	if rd == nil || len(m) == 0 {
+		return m
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = rd.redactValue(k, v)
+	}
+	return out
+}
@@ -0,0 +1,397 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var reloadMu sync.Mutex
+
+// Exporters InitEOTEL built for the collector; nil when the signal is off or
+// comes from a caller-supplied provider.
+var (
+	reloadTraces  *swapSpanExporter
+	reloadMetrics *swapMetricExporter
+	reloadLogs    *swapLogExporter
+)
+
+// Reload applies the runtime-adjustable part of cfg without re-running
+// InitEOTEL: the minimum log level, trace and log sampling, redaction rules,
+// and the collector and Loki endpoints (with their TLS, headers and auth).
+// Everything is built before anything is swapped, so on error the previous
+// configuration stays in place. Other changed keys are logged and ignored;
+// they need InitEOTEL.
+func Reload(cfg Config) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	if !initialized.Load() {
+		return errors.New("eotel: Reload called before InitEOTEL")
+	}
+
+	old := *currentConfig()
+	next := reloadable(old, cfg)
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("reload: %w", err)
+	}
+
+	level := zapcore.DebugLevel
+	if next.MinLevel != "" {
+		var err error
+		if level, err = zapcore.ParseLevel(next.MinLevel); err != nil {
+			return fmt.Errorf("reload: min level: %w", err)
+		}
+	}
+	rd, err := newRedactor(next.Redaction)
+	if err != nil {
+		return fmt.Errorf("reload: redaction: %w", err)
+	}
+	sampler, err := newSampler(next)
+	if err != nil {
+		return fmt.Errorf("reload: sampler: %w", err)
+	}
+	exps, err := newReloadExporters(old, next)
+	if err != nil {
+		return fmt.Errorf("reload: %w", err)
+	}
+
+	if next.MinLevel != old.MinLevel {
+		minLevel.SetLevel(level)
+	}
+	if !reflect.DeepEqual(next.Redaction, old.Redaction) {
+		activeRedactor.Store(rd)
+	}
+	if samplerChanged(old, next) {
+		closeSampler(traceSampler.set(sampler))
+	}
+	if !reflect.DeepEqual(next.LogSampling, old.LogSampling) {
+		activeLogSampler.Store(newLogSampler(next.LogSampling))
+	}
+	exps.swap()
+
+	globalCfg.Store(&next)
+	if next.EnableLoki && lokiChanged(old, next) {
+		if err := restartLoki(next); err != nil {
+			getLogger().Warn("eotel: loki restart failed", zap.Error(err))
+		}
+	}
+
+	logConfigChanges(old, next)
+	if ignored := DiffConfig(next, cfg); len(ignored) > 0 {
+		keys := make([]string, len(ignored))
+		for i, c := range ignored {
+			keys[i] = c.Key
+		}
+		getLogger().Warn("eotel: configuration keys need InitEOTEL to take effect", zap.Strings("keys", keys))
+	}
+	return nil
+}
+
+// reloadable returns old with the fields Reload can apply taken from cfg.
+func reloadable(old, cfg Config) Config {
+	next := old
+	next.MinLevel = cfg.MinLevel
+	next.Sampler = cfg.Sampler
+	next.SamplerRatio = cfg.SamplerRatio
+	next.SamplerTargetTPS = cfg.SamplerTargetTPS
+	next.SamplerRemoteURL = cfg.SamplerRemoteURL
+	next.SamplerRemoteInterval = cfg.SamplerRemoteInterval
+	next.LogSampling = cfg.LogSampling
+	next.Redaction = cfg.Redaction
+
+	next.OtelCollector = cfg.OtelCollector
+	next.OtelTLS = cfg.OtelTLS
+	next.OtelInsecure = cfg.OtelInsecure
+	next.OtelHeaders = cfg.OtelHeaders
+
+	next.LokiURL = cfg.LokiURL
+	next.LokiUsername = cfg.LokiUsername
+	next.LokiPassword = cfg.LokiPassword
+	next.LokiBearerToken = cfg.LokiBearerToken
+	next.LokiTenantID = cfg.LokiTenantID
+	next.LokiHeaders = cfg.LokiHeaders
+	return next
+}
+
+func samplerChanged(old, next Config) bool {
+	return old.Sampler != next.Sampler ||
+		old.SamplerRatio != next.SamplerRatio ||
+		old.SamplerTargetTPS != next.SamplerTargetTPS ||
+		old.SamplerRemoteURL != next.SamplerRemoteURL ||
+		old.SamplerRemoteInterval != next.SamplerRemoteInterval
+}
+
+func collectorChanged(old, next Config) bool {
+	return old.OtelCollector != next.OtelCollector ||
+		!reflect.DeepEqual(old.OtelTLS, next.OtelTLS) ||
+		old.OtelInsecure != next.OtelInsecure ||
+		!reflect.DeepEqual(old.OtelHeaders, next.OtelHeaders)
+}
+
+func lokiChanged(old, next Config) bool {
+	return old.LokiURL != next.LokiURL ||
+		old.LokiUsername != next.LokiUsername ||
+		old.LokiPassword != next.LokiPassword ||
+		old.LokiBearerToken != next.LokiBearerToken ||
+		old.LokiTenantID != next.LokiTenantID ||
+		!reflect.DeepEqual(old.LokiHeaders, next.LokiHeaders)
+}
+
+// restartLoki flushes what the current pusher holds to the old endpoint and
+// replaces it with one for the new settings.
+func restartLoki(cfg Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutOr(cfg.ExporterTimeouts.Loki, defaultLokiTimeout))
+	defer cancel()
+	if err := drainLoki(ctx); err != nil {
+		getLogger().Warn("eotel: loki drain before restart", zap.Error(err))
+	}
+	return startLoki(cfg)
+}
+
+// reloadExporters are the collector exporters built for a new endpoint,
+// waiting to be swapped in.
+type reloadExporters struct {
+	traces  sdktrace.SpanExporter
+	metrics sdkmetric.Exporter
+	logs    sdklog.Exporter
+}
+
+func newReloadExporters(old, next Config) (reloadExporters, error) {
+	var e reloadExporters
+	if !collectorChanged(old, next) {
+		return e, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutOr(next.ExporterTimeouts.OTLP, defaultOTLPTimeout))
+	defer cancel()
+
+	var err error
+	if reloadTraces != nil {
+		if e.traces, err = newTraceExporter(ctx, next); err != nil {
+			return e, fmt.Errorf("trace exporter: %w", err)
+		}
+	}
+	if reloadMetrics != nil {
+		if e.metrics, err = newMetricExporter(ctx, next); err != nil {
+			e.shutdown()
+			return e, fmt.Errorf("metric exporter: %w", err)
+		}
+	}
+	if reloadLogs != nil {
+		if e.logs, err = newLogExporter(ctx, next); err != nil {
+			e.shutdown()
+			return e, fmt.Errorf("log exporter: %w", err)
+		}
+	}
+	return e, nil
+}
+
+// swap installs the new exporters and shuts the replaced ones down once
+// their in-flight exports had a chance to finish.
+func (e reloadExporters) swap() {
+	var prev reloadExporters
+	if e.traces != nil {
+		prev.traces = reloadTraces.set(e.traces)
+	}
+	if e.metrics != nil {
+		prev.metrics = reloadMetrics.set(e.metrics)
+	}
+	if e.logs != nil {
+		prev.logs = reloadLogs.set(e.logs)
+	}
+	go prev.shutdown()
+}
+
+func (e reloadExporters) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutOr(currentConfig().ExporterTimeouts.OTLP, defaultOTLPTimeout))
+	defer cancel()
+	if e.traces != nil {
+		_ = e.traces.Shutdown(ctx)
+	}
+	if e.metrics != nil {
+		_ = e.metrics.Shutdown(ctx)
+	}
+	if e.logs != nil {
+		_ = e.logs.Shutdown(ctx)
+	}
+}
+
+// swappable holds a value Reload can replace while exports are running.
+type swappable[T any] struct {
+	cur atomic.Pointer[T]
+}
+
+func (s *swappable[T]) get() T {
+	return *s.cur.Load()
+}
+
+// set installs v and returns the value it replaced.
+func (s *swappable[T]) set(v T) T {
+	var zero T
+	if prev := s.cur.Swap(&v); prev != nil {
+		return *prev
+	}
+	return zero
+}
+
+// traceSampler is the sampler every provider InitEOTEL builds delegates to.
+var traceSampler = &swapSampler{}
+
+type swapSampler struct {
+	swappable[sdktrace.Sampler]
+}
+
+func (s *swapSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.get().ShouldSample(p)
+}
+
+func (s *swapSampler) Description() string { return s.get().Description() }
+
+type swapSpanExporter struct {
+	swappable[sdktrace.SpanExporter]
+}
+
+func newSwapSpanExporter(exp sdktrace.SpanExporter) *swapSpanExporter {
+	s := &swapSpanExporter{}
+	s.set(exp)
+	return s
+}
+
+func (s *swapSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return s.get().ExportSpans(ctx, spans)
+}
+
+func (s *swapSpanExporter) Shutdown(ctx context.Context) error { return s.get().Shutdown(ctx) }
+
+type swapMetricExporter struct {
+	swappable[sdkmetric.Exporter]
+}
+
+func newSwapMetricExporter(exp sdkmetric.Exporter) *swapMetricExporter {
+	s := &swapMetricExporter{}
+	s.set(exp)
+	return s
+}
+
+func (s *swapMetricExporter) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return s.get().Temporality(k)
+}
+
+func (s *swapMetricExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return s.get().Aggregation(k)
+}
+
+func (s *swapMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return s.get().Export(ctx, rm)
+}
+
+func (s *swapMetricExporter) ForceFlush(ctx context.Context) error { return s.get().ForceFlush(ctx) }
+func (s *swapMetricExporter) Shutdown(ctx context.Context) error   { return s.get().Shutdown(ctx) }
+
+type swapLogExporter struct {
+	swappable[sdklog.Exporter]
+}
+
+func newSwapLogExporter(exp sdklog.Exporter) *swapLogExporter {
+	s := &swapLogExporter{}
+	s.set(exp)
+	return s
+}
+
+func (s *swapLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	return s.get().Export(ctx, records)
+}
+
+func (s *swapLogExporter) ForceFlush(ctx context.Context) error { return s.get().ForceFlush(ctx) }
+func (s *swapLogExporter) Shutdown(ctx context.Context) error   { return s.get().Shutdown(ctx) }
+
+// ReloadOnSignal reloads the configuration from path (see LoadConfigFile)
+// whenever the process receives one of sigs, SIGHUP by default (Unix only).
+// Failed reloads are logged. The returned function stops listening.
+func ReloadOnSignal(path string, sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		if reloadSignal == nil {
+			return func() {}
+		}
+		sigs = []os.Signal{reloadSignal}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				reloadFile(path)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// WatchConfigFile polls path every interval (default 5s) and reloads the
+// configuration when its modification time or size changes. The returned
+// function stops watching.
+func WatchConfigFile(path string, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	stat := func() (time.Time, int64) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return info.ModTime(), info.Size()
+	}
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		mod, size := stat()
+		for {
+			select {
+			case <-t.C:
+				m, s := stat()
+				if s < 0 || (m.Equal(mod) && s == size) {
+					continue
+				}
+				mod, size = m, s
+				reloadFile(path)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func reloadFile(path string) {
+	cfg, err := LoadConfigFile(path)
+	if err == nil {
+		err = Reload(cfg)
+	}
+	if err != nil {
+		getLogger().Warn("eotel: configuration reload failed", zap.String("path", path), zap.Error(err))
+	}
+}
@@ -0,0 +1,107 @@
+package eotel
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/samplers/jaegerremote"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	defaultRemoteSamplerURL      = "http://localhost:5778/sampling"
+	defaultRemoteSamplerInterval = time.Minute
+)
+
+// remoteSampler polls per-operation sampling strategies from a Jaeger remote
+// sampling endpoint, such as the collector's jaegerremotesampling extension.
+// Polling starts with the first sampling decision, so building one (e.g. in
+// Config.Validate) has no side effects.
+type remoteSampler struct {
+	service  string
+	url      string
+	interval time.Duration
+	initial  sdktrace.Sampler
+
+	once    sync.Once
+	sampler *jaegerremote.Sampler
+}
+
+func newRemoteSampler(cfg Config) *remoteSampler {
+	r := &remoteSampler{
+		service:  cfg.ServiceName,
+		url:      cfg.SamplerRemoteURL,
+		interval: cfg.SamplerRemoteInterval,
+	}
+	if r.url == "" {
+		r.url = defaultRemoteSamplerURL
+	}
+	if r.interval <= 0 {
+		r.interval = defaultRemoteSamplerInterval
+	}
+	if cfg.SamplerRatio > 0 {
+		r.initial = sdktrace.TraceIDRatioBased(cfg.SamplerRatio)
+	}
+	return r
+}
+
+func (r *remoteSampler) start() *jaegerremote.Sampler {
+	r.once.Do(func() {
+		opts := []jaegerremote.Option{
+			jaegerremote.WithSamplingServerURL(r.url),
+			jaegerremote.WithSamplingRefreshInterval(r.interval),
+		}
+		if r.initial != nil {
+			opts = append(opts, jaegerremote.WithInitialSampler(r.initial))
+		}
+		r.sampler = jaegerremote.New(r.service, opts...)
+	})
+	return r.sampler
+}
+
+func (r *remoteSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return r.start().ShouldSample(p)
+}
+
+func (r *remoteSampler) Description() string {
+	return "JaegerRemoteSampler{" + r.url + "}"
+}
+
+// close stops polling, if it ever started.
+func (r *remoteSampler) close() {
+	r.once.Do(func() {})
+	if r.sampler != nil {
+		r.sampler.Close()
+	}
+}
+
+// parentBasedRemote keeps a handle on the remote root sampler behind
+// sdktrace.ParentBased so it can be closed.
+type parentBasedRemote struct {
+	sdktrace.Sampler
+	remote *remoteSampler
+}
+
+// remoteOf returns the remote sampler inside s, if any.
+func remoteOf(s sdktrace.Sampler) *remoteSampler {
+	if ps, ok := s.(prioritySampler); ok {
+		s = ps.base
+	}
+	switch x := s.(type) {
+	case *remoteSampler:
+		return x
+	case parentBasedRemote:
+		return x.remote
+	}
+	return nil
+}
+
+// closeSampler stops the background work of a sampler that was replaced.
+func closeSampler(s sdktrace.Sampler) {
+	if s == nil {
+		return
+	}
+	if r := remoteOf(s); r != nil {
+		r.close()
+	}
+}
@@ -0,0 +1,66 @@
+package eotel
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultRequestIDHeader = "X-Request-ID"
+	defaultTraceIDHeader   = "X-Trace-ID"
+	maxRequestIDLength     = 128
+)
+
+// RequestID returns the request ID assigned by the middleware, or "" for
+// loggers not bound to a request.
+func (l *Eotel) RequestID() string {
+	if l == nil {
+		return ""
+	}
+	return l.requestID
+}
+
+func headerName(name, def string) string {
+	switch name {
+	case "":
+		return def
+	case "-":
+		return ""
+	}
+	return name
+}
+
+// requestIDFor returns the caller's request ID from Config.RequestIDHeader,
+// or a new random one when it is absent or unreasonably long.
+func requestIDFor(h http.Header) string {
+	if name := headerName(currentConfig().RequestIDHeader, defaultRequestIDHeader); name != "" {
+		if id := h.Get(name); id != "" && len(id) <= maxRequestIDLength {
+			return id
+		}
+	}
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// bindRequestID tags span and logger with the request ID and writes it, with
+// the trace ID, to the response headers so users can quote them to support.
+func bindRequestID(h http.Header, span trace.Span, logger *Eotel, id string) *Eotel {
+	cfg := currentConfig()
+	span.SetAttributes(attribute.String("request_id", id))
+	if name := headerName(cfg.RequestIDHeader, defaultRequestIDHeader); name != "" {
+		h.Set(name, id)
+	}
+	if name := headerName(cfg.TraceIDHeader, defaultTraceIDHeader); name != "" {
+		if sc := span.SpanContext(); sc.HasTraceID() {
+			h.Set(name, sc.TraceID().String())
+		}
+	}
+	cp := logger.WithField("request_id", id)
+	cp.requestID = id
+	return cp
+}
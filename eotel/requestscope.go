@@ -0,0 +1,52 @@
+package eotel
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestScopeKey struct{}
+
+// requestScope is state shared by every logger of one inbound request.
+type requestScope struct {
+	errorCaptured atomic.Bool
+	span          trace.Span
+}
+
+// WithRequestScope starts the state shared by the loggers of one inbound
+// request, so that only its first error is captured. Server middlewares,
+// including those of the integration modules, call it before the request
+// logger is created, with the server span in ctx.
+func WithRequestScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestScopeKey{}, &requestScope{span: trace.SpanFromContext(ctx)})
+}
+
+func requestScopeFrom(ctx context.Context) *requestScope {
+	if ctx == nil {
+		return nil
+	}
+	rs, _ := ctx.Value(requestScopeKey{}).(*requestScope)
+	return rs
+}
+
+// firstErrorOfRequest reports whether err should be captured: always outside
+// a request or with Config.CaptureEveryError, otherwise only for the first
+// error of the request. Later errors are recorded as an exception span event
+// and a Sentry breadcrumb, so a cascading failure yields one event.
+func firstErrorOfRequest(ctx context.Context, err error) bool {
+	rs := requestScopeFrom(ctx)
+	if rs == nil || currentConfig().CaptureEveryError || rs.errorCaptured.CompareAndSwap(false, true) {
+		return true
+	}
+	trace.SpanFromContext(ctx).AddEvent("exception", trace.WithAttributes(
+		attribute.String("exception.message", err.Error()),
+		attribute.Bool("exception.capture_skipped", true),
+	))
+	if b := sentryScope(ctx); b != nil {
+		b.AddBreadcrumb(ctx, Breadcrumb{Type: "error", Category: "error", Level: "error", Message: err.Error()})
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// newResource describes the service and where it runs: host, OS, process,
+// container and, from the downward API environment, the Kubernetes pod.
+// OTEL_RESOURCE_ATTRIBUTES is honoured; explicit config wins over it.
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(cfg.ServiceName)}
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.ServiceVersion))
+	}
+	if cfg.DeploymentEnvironment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentName(cfg.DeploymentEnvironment))
+	}
+	attrs = append(attrs, kubernetesAttrs()...)
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithHost(),
+		resource.WithOS(),
+		resource.WithProcessPID(),
+		resource.WithProcessExecutableName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithContainer(),
+		resource.WithAttributes(attrs...),
+	)
+	// A detector that fails (e.g. no cgroup file outside containers) still
+	// leaves a usable resource.
+	if errors.Is(err, resource.ErrPartialResource) {
+		return res, nil
+	}
+	return res, err
+}
+
+// kubernetesAttrs reads the pod identity exposed through the downward API,
+// e.g. env POD_NAME from fieldRef metadata.name.
+func kubernetesAttrs() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if v := firstEnv("K8S_POD_NAME", "POD_NAME"); v != "" {
+		attrs = append(attrs, semconv.K8SPodName(v))
+	}
+	if v := firstEnv("K8S_NAMESPACE_NAME", "POD_NAMESPACE"); v != "" {
+		attrs = append(attrs, semconv.K8SNamespaceName(v))
+	}
+	if v := firstEnv("K8S_NODE_NAME", "NODE_NAME"); v != "" {
+		attrs = append(attrs, semconv.K8SNodeName(v))
+	}
+	if v := firstEnv("K8S_POD_UID", "POD_UID"); v != "" {
+		attrs = append(attrs, semconv.K8SPodUID(v))
+	}
+	return attrs
+}
+
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func sentryEnvironment(cfg Config) string {
+	if cfg.SentryEnvironment != "" {
+		return cfg.SentryEnvironment
+	}
+	if cfg.DeploymentEnvironment != "" {
+		return cfg.DeploymentEnvironment
+	}
+	return "production"
+}
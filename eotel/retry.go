@@ -0,0 +1,120 @@
+package eotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type RetryPolicy struct {
+	Name           string
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	RetryIf        func(error) bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Name == "" {
+		p.Name = "retry"
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+// Retry runs fn until it succeeds, the policy gives up or ctx is done. Every
+// attempt is recorded as an event on the span active in ctx.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	span := trace.SpanFromContext(ctx)
+	logger := FromContext(ctx, policy.Name).logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	logger = logger.With(
+		zap.String("retry", policy.Name),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+	)
+
+	hist, _ := getMeter().Int64Histogram("retry_attempts")
+
+	var err error
+	attempt := 0
+	cancelled := false
+	backoff := policy.InitialBackoff
+retry:
+	for attempt < policy.MaxAttempts {
+		attempt++
+		err = fn(ctx)
+
+		attrs := []attribute.KeyValue{
+			attribute.String("retry.name", policy.Name),
+			attribute.Int("retry.attempt", attempt),
+		}
+		if err != nil {
+			attrs = append(attrs, attribute.String("error", err.Error()))
+		}
+		span.AddEvent("retry.attempt", trace.WithAttributes(attrs...))
+
+		if err == nil {
+			break
+		}
+		if policy.RetryIf != nil && !policy.RetryIf(err) {
+			logger.Debug("retry aborted: error not retryable", zap.Int("attempt", attempt), zap.Error(err))
+			break
+		}
+		if attempt == policy.MaxAttempts {
+			break
+		}
+
+		logger.Debug("retrying after failure", zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			cancelled = true
+			break retry
+		case <-time.After(backoff):
+		}
+
+		backoff = time.Duration(float64(backoff) * policy.Multiplier)
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	outcome := "success"
+	switch {
+	case cancelled:
+		outcome = "cancelled"
+		logger.Debug("retry cancelled", zap.Int("attempts", attempt), zap.Error(err))
+	case err != nil:
+		outcome = "failure"
+		if attempt >= policy.MaxAttempts {
+			span.SetAttributes(attribute.Bool("retries_exhausted", true))
+			logger.Debug("retries exhausted", zap.Int("attempts", attempt), zap.Error(err))
+		}
+	}
+
+	if hist != nil {
+		hist.Record(ctx, int64(attempt), metric.WithAttributes(
+			attribute.String("retry.name", policy.Name),
+			attribute.String("outcome", outcome),
+		))
+	}
+	return err
+}
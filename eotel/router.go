@@ -0,0 +1,92 @@
+package eotel
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// RouterContext is one request as a router's middleware chain sees it.
+// NewRouterMiddleware and RecoverRouter hold the instrumentation every
+// router shares; the eotelgin module adapts *gin.Context to it, and other
+// routers can do the same.
+type RouterContext interface {
+	Request() *http.Request
+	SetRequest(r *http.Request)
+	// Route is the matched route template, "" when no route matched.
+	Route() string
+	ClientIP() string
+	// Writer is the response writer handlers write to.
+	Writer() http.ResponseWriter
+	Status() int
+	// Size is the number of response body bytes written, -1 before any.
+	Size() int
+	// Next runs the remaining handlers.
+	Next()
+	// Abort keeps the remaining handlers from running.
+	Abort()
+	// TapWrites passes a copy of every later response body write to fn.
+	TapWrites(fn func(p []byte))
+	// OnFlush calls fn after every later flush of the response.
+	OnFlush(fn func())
+	// Errors are the errors handlers attached to the request.
+	Errors() []RouterError
+}
+
+// RouterError is an error a handler attached to the request, with the
+// router's own classification of it.
+type RouterError struct {
+	Err  error
+	Type int64
+}
+
+// WithRouterSpanNameFunc names the request span; the default is the method
+// and the matched route.
+func WithRouterSpanNameFunc(fn func(rc RouterContext) string) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.spanName = fn
+	}
+}
+
+// WithRouterFilter adds a predicate; requests are instrumented only when
+// every filter returns true.
+func WithRouterFilter(fn func(rc RouterContext) bool) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.filters = append(cfg.filters, fn)
+	}
+}
+
+// WithRouterSession tags the request span and logs with session.id, a
+// stable hash of the session identifier returned by extract, so a user's
+// requests can be stitched together without the raw token leaving the
+// process. Requests for which extract returns "" are left untagged.
+func WithRouterSession(extract func(rc RouterContext) string) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.session = extract
+	}
+}
+
+// WithSessionCookie is WithRouterSession reading the session from cookie
+// name.
+func WithSessionCookie(name string) MiddlewareOption {
+	return WithRouterSession(func(rc RouterContext) string {
+		c, err := rc.Request().Cookie(name)
+		if err != nil {
+			return ""
+		}
+		v, _ := url.QueryUnescape(c.Value)
+		return v
+	})
+}
+
+// RecoverRouter handles a panic recovered from a handler behind rc and
+// answers the request through Config.PanicResponse, or with a JSON 500.
+func RecoverRouter(rc RouterContext, rec any) error {
+	route := rc.Route()
+	if route == "" {
+		route = "unmatched"
+	}
+	err := recoverRequest(rc.Request().Context(), rec, route)
+	rc.Abort()
+	writePanicResponse(rc.Writer(), rc.Request(), rec)
+	return err
+}
@@ -0,0 +1,87 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newSampler maps Config.Sampler onto the SDK samplers, using the same names
+// as the OTEL_TRACES_SAMPLER environment variable.
+func newSampler(cfg Config) (sdktrace.Sampler, error) {
+	base, err := newBaseSampler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return prioritySampler{base: base}, nil
+}
+
+func newBaseSampler(cfg Config) (sdktrace.Sampler, error) {
+	ratio := cfg.SamplerRatio
+	if ratio < 0 || ratio > 1 {
+		return nil, fmt.Errorf("sampler ratio %v out of range [0,1]", ratio)
+	}
+
+	switch cfg.Sampler {
+	case "", "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+	case "adaptive":
+		return newAdaptiveSampler(cfg.SamplerTargetTPS), nil
+	case "parentbased_adaptive":
+		a := newAdaptiveSampler(cfg.SamplerTargetTPS)
+		return parentBasedAdaptive{Sampler: sdktrace.ParentBased(a), adaptive: a}, nil
+	case "jaeger_remote":
+		return newRemoteSampler(cfg), nil
+	case "parentbased_jaeger_remote":
+		r := newRemoteSampler(cfg)
+		return parentBasedRemote{Sampler: sdktrace.ParentBased(r), remote: r}, nil
+	default:
+		return nil, fmt.Errorf("unknown sampler %q", cfg.Sampler)
+	}
+}
+
+type samplingPriorityKey struct{}
+
+// WithSamplingPriority attaches a sampling probability in [0,1] to ctx. Root
+// spans started from ctx are sampled with that probability instead of the
+// configured sampler, e.g. 1.0 for enterprise tenants and 0.01 for free tier.
+func WithSamplingPriority(ctx context.Context, probability float64) context.Context {
+	return context.WithValue(ctx, samplingPriorityKey{}, probability)
+}
+
+func samplingPriorityFrom(ctx context.Context) (float64, bool) {
+	p, ok := ctx.Value(samplingPriorityKey{}).(float64)
+	return p, ok
+}
+
+// prioritySampler consults the sampling priority stored in the parent context
+// for root spans and falls back to the configured sampler otherwise.
+type prioritySampler struct {
+	base sdktrace.Sampler
+}
+
+func (s prioritySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if trace.SpanContextFromContext(p.ParentContext).IsValid() {
+		return s.base.ShouldSample(p)
+	}
+	if prob, ok := samplingPriorityFrom(p.ParentContext); ok {
+		return sdktrace.TraceIDRatioBased(prob).ShouldSample(p)
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s prioritySampler) Description() string {
+	return "PrioritySampler{" + s.base.Description() + "}"
+}
@@ -0,0 +1,65 @@
+package eotel
+
+import (
+	"strings"
+)
+
+const maxKeyLength = 128
+
+// KeySanitizer controls how field keys are rewritten before they reach zap,
+// span attributes and Loki labels.
+type KeySanitizer struct {
+	// Replacements are applied first, in no particular order, as literal
+	// substring replacements (e.g. {" ": "_", "/": "."}).
+	Replacements map[string]string `yaml:"replacements"`
+	// Replacement substitutes any remaining character outside
+	// [A-Za-z0-9_.-]; defaults to "_".
+	Replacement string `yaml:"replacement"`
+}
+
+func validKeyChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// sanitizeKey maps an arbitrary, possibly user-derived key onto
+// [A-Za-z0-9_.-]{1,128}. Already clean keys are returned as is.
+func sanitizeKey(key string) string {
+	rules := currentConfig().KeySanitizer
+	clean := len(key) > 0 && len(key) <= maxKeyLength && len(rules.Replacements) == 0
+	for i := 0; clean && i < len(key); i++ {
+		clean = validKeyChar(key[i])
+	}
+	if clean {
+		return key
+	}
+
+	for from, to := range rules.Replacements {
+		if from != "" {
+			key = strings.ReplaceAll(key, from, to)
+		}
+	}
+	repl := rules.Replacement
+	if repl == "" {
+		repl = "_"
+	}
+
+	var b strings.Builder
+	b.Grow(len(key))
+	// Iterate over runes so a multi-byte character yields one replacement.
+	for _, r := range key {
+		if r < 0x80 && validKeyChar(byte(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(repl)
+		}
+	}
+	out := b.String()
+	if len(out) > maxKeyLength {
+		out = out[:maxKeyLength]
+	}
+	if out == "" {
+		return repl
+	}
+	return out
+}
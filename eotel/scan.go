@@ -0,0 +1,100 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Scanner instruments long iterations (cursor scans, bucket listings) on a
+// single span, aggregating item counts instead of one child span per item.
+type Scanner struct {
+	mu            sync.Mutex
+	ctx           context.Context
+	span          trace.Span
+	name          string
+	items         int64
+	pages         int64
+	start         time.Time
+	lastProgress  time.Time
+	progressEvery time.Duration
+	itemCounter   metric.Int64Counter
+}
+
+func Scan(ctx context.Context, name string) *Scanner {
+	ctx, span := getTracer().Start(ctx, name)
+	counter, _ := getMeter().Int64Counter("scan_items_total")
+	now := time.Now()
+	return &Scanner{
+		ctx:           ctx,
+		span:          span,
+		name:          name,
+		start:         now,
+		lastProgress:  now,
+		progressEvery: 5 * time.Second,
+		itemCounter:   counter,
+	}
+}
+
+func (s *Scanner) ProgressEvery(d time.Duration) *Scanner {
+	s.mu.Lock()
+	s.progressEvery = d
+	s.mu.Unlock()
+	return s
+}
+
+func (s *Scanner) Ctx() context.Context {
+	return s.ctx
+}
+
+// Page records one batch of n items, e.g. a page returned by a cursor.
+func (s *Scanner) Page(n int) {
+	s.mu.Lock()
+	s.pages++
+	s.mu.Unlock()
+	s.Add(n)
+}
+
+func (s *Scanner) Add(n int) {
+	s.mu.Lock()
+	s.items += int64(n)
+	emit := s.progressEvery > 0 && time.Since(s.lastProgress) >= s.progressEvery
+	if emit {
+		s.lastProgress = time.Now()
+	}
+	items, pages := s.items, s.pages
+	s.mu.Unlock()
+
+	if s.itemCounter != nil {
+		s.itemCounter.Add(s.ctx, int64(n), metric.WithAttributes(attribute.String("scan.name", s.name)))
+	}
+	if emit {
+		s.span.AddEvent("scan.progress", trace.WithAttributes(
+			attribute.Int64("scan.items", items),
+			attribute.Int64("scan.pages", pages),
+			attribute.Float64("scan.elapsed_ms", time.Since(s.start).Seconds()*1000),
+		))
+	}
+}
+
+func (s *Scanner) End(err error) {
+	s.mu.Lock()
+	items, pages := s.items, s.pages
+	s.mu.Unlock()
+
+	s.span.SetAttributes(
+		attribute.Int64("scan.items", items),
+		attribute.Int64("scan.pages", pages),
+		attribute.Float64("duration_ms", time.Since(s.start).Seconds()*1000),
+	)
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,93 @@
+package eotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scope is an isolated instrumentation scope for shared libraries: it has its
+// own tracer/meter name, level and exporter but reuses the process pipeline.
+type Scope struct {
+	name     string
+	version  string
+	level    *zapcore.Level
+	exporter Exporter
+}
+
+type ScopeOption func(*Scope)
+
+func WithScopeVersion(version string) ScopeOption {
+	return func(s *Scope) {
+		s.version = version
+	}
+}
+
+// WithScopeLevel raises the minimum level for the scope; it cannot go below
+// the level of the host logger.
+func WithScopeLevel(level zapcore.Level) ScopeOption {
+	return func(s *Scope) {
+		s.level = &level
+	}
+}
+
+// WithScopeExporter sends the scope's entries to exp instead of the
+// registered exporters; calls into it are bounded like theirs.
+func WithScopeExporter(exp Exporter, opts ...ExporterOption) ScopeOption {
+	return func(s *Scope) {
+		if exp == nil {
+			return
+		}
+		r := registeredExporter{exp: exp}
+		for _, opt := range opts {
+			opt(&r)
+		}
+		s.exporter = r
+	}
+}
+
+func NewScope(name string, opts ...ScopeOption) *Scope {
+	s := &Scope{name: name}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Scope) Name() string {
+	return s.name
+}
+
+func (s *Scope) New(ctx context.Context, name string) *Eotel {
+	ensureInit("Scope.New")
+	tracer := TracerProvider().Tracer(s.name, trace.WithInstrumentationVersion(s.version))
+	meter := MeterProvider().Meter(s.name, metric.WithInstrumentationVersion(s.version))
+	logCounter, durationHist := logMetrics(meter)
+
+	logger := getLogger().Named(s.name)
+	if s.level != nil {
+		logger = logger.WithOptions(zap.IncreaseLevel(*s.level))
+	}
+
+	exporter := s.exporter
+	if exporter == nil {
+		exporter = globalExporter
+	}
+
+	return &Eotel{
+		ctx:          ctx,
+		logger:       logger,
+		tracer:       tracer,
+		meter:        meter,
+		logCounter:   logCounter,
+		durationHist: durationHist,
+		start:        time.Now(),
+		exporter:     exporter,
+		name:         name,
+		aggs:         &aggregator{},
+	}
+}
@@ -0,0 +1,77 @@
+package eotel
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// spanScope is shared by a child logger and every logger derived from it,
+// and marks when that child was ended.
+type spanScope struct {
+	ended atomic.Bool
+}
+
+type scopedField struct {
+	zap   zap.Field
+	attr  attribute.KeyValue
+	scope *spanScope
+}
+
+// WithScopedField is WithField for per-iteration context: the field is bound
+// to l's child span and stops appearing, on l and on anything derived from
+// it, once that span is ended. On a logger that is not a Child it behaves
+// like WithField.
+//
+//	for _, item := range items {
+//		c := l.Child("process").WithScopedField("item.id", item.ID)
+//		...
+//		c.End()
+//	}
+func (l *Eotel) WithScopedField(key string, value any) *Eotel {
+	if l == nil {
+		return Noop("WithScopedField")
+	}
+	if key == "" {
+		return l
+	}
+	if l.scope == nil {
+		return l.WithField(key, value)
+	}
+	var acc Eotel
+	acc.addField(key, value)
+	cp := l.clone()
+	for i := range acc.fields {
+		cp.scoped = append(cp.scoped, scopedField{zap: acc.fields[i], attr: acc.attrs[i], scope: l.scope})
+	}
+	return cp
+}
+
+// logFields returns l's fields plus the scoped ones still in scope.
+func (l *Eotel) logFields() []zap.Field {
+	if len(l.scoped) == 0 {
+		return l.fields
+	}
+	fields := l.fields[:len(l.fields):len(l.fields)]
+	for _, s := range l.scoped {
+		if !s.scope.ended.Load() {
+			fields = append(fields, s.zap)
+		}
+	}
+	return fields
+}
+
+// spanAttrs is logFields for span attributes.
+func (l *Eotel) spanAttrs() []attribute.KeyValue {
+	if len(l.scoped) == 0 {
+		return l.attrs
+	}
+	attrs := l.attrs[:len(l.attrs):len(l.attrs)]
+	for _, s := range l.scoped {
+		if !s.scope.ended.Load() {
+			attrs = append(attrs, s.attr)
+		}
+	}
+	return attrs
+}
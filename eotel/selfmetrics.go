@@ -0,0 +1,66 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// selfMetricsProcessor records the shape of produced traces (spans per trace,
+// attributes and events per span) so instrumentation explosions show up in
+// metrics before they show up on the collector bill.
+type selfMetricsProcessor struct {
+	mu     sync.Mutex
+	counts map[trace.TraceID]int64
+
+	spansPerTrace metric.Int64Histogram
+	attrsPerSpan  metric.Int64Histogram
+	eventsPerSpan metric.Int64Histogram
+	droppedAttrs  metric.Int64Counter
+	droppedEvents metric.Int64Counter
+}
+
+func newSelfMetricsProcessor(m metric.Meter) *selfMetricsProcessor {
+	p := &selfMetricsProcessor{counts: map[trace.TraceID]int64{}}
+	p.spansPerTrace, _ = m.Int64Histogram("eotel_trace_spans")
+	p.attrsPerSpan, _ = m.Int64Histogram("eotel_span_attributes")
+	p.eventsPerSpan, _ = m.Int64Histogram("eotel_span_events")
+	p.droppedAttrs, _ = m.Int64Counter("eotel_span_dropped_attributes_total")
+	p.droppedEvents, _ = m.Int64Counter("eotel_span_dropped_events_total")
+	return p
+}
+
+func (p *selfMetricsProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	p.mu.Lock()
+	p.counts[s.SpanContext().TraceID()]++
+	p.mu.Unlock()
+}
+
+func (p *selfMetricsProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	ctx := context.Background()
+	p.attrsPerSpan.Record(ctx, int64(len(s.Attributes())))
+	p.eventsPerSpan.Record(ctx, int64(len(s.Events())))
+	if n := s.DroppedAttributes(); n > 0 {
+		p.droppedAttrs.Add(ctx, int64(n))
+	}
+	if n := s.DroppedEvents(); n > 0 {
+		p.droppedEvents.Add(ctx, int64(n))
+	}
+
+	// The local root closes the trace for this process.
+	if s.Parent().IsValid() && !s.Parent().IsRemote() {
+		return
+	}
+	tid := s.SpanContext().TraceID()
+	p.mu.Lock()
+	n := p.counts[tid]
+	delete(p.counts, tid)
+	p.mu.Unlock()
+	p.spansPerTrace.Record(ctx, n)
+}
+
+func (p *selfMetricsProcessor) Shutdown(context.Context) error   { return nil }
+func (p *selfMetricsProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,107 @@
+package eotel
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+func sentryOptions(cfg Config) SentryOptions {
+	release := cfg.SentryRelease
+	if release == "" {
+		release = cfg.ServiceVersion
+	}
+	tracesRate := 1.0
+	if cfg.SentryTracesSampleRate != nil {
+		tracesRate = *cfg.SentryTracesSampleRate
+	}
+	return SentryOptions{
+		DSN:              cfg.SentryDSN,
+		EnableTracing:    cfg.EnableTracing,
+		SampleRate:       cfg.SentrySampleRate,
+		TracesSampleRate: tracesRate,
+		Environment:      sentryEnvironment(cfg),
+		Release:          release,
+		Debug:            cfg.SentryDebug,
+		BeforeSend:       cfg.SentryBeforeSend,
+		HTTPClient:       sentryHTTPClient(cfg),
+		Logger:           getLogger(),
+	}
+}
+
+func CaptureError(err error, tags map[string]string, extras map[string]interface{}) {
+	CaptureErrorContext(context.Background(), err, tags, extras)
+}
+
+// CaptureErrorContext queues err for Sentry without waiting for delivery,
+// using the Sentry scope bound to ctx by the HTTP middleware so the event
+// carries the request, client IP, trace ID and the request's log lines as
+// breadcrumbs. Grouping follows Config.SentryFingerprintRules; repeats within
+// Config.SentryDedupWindow are suppressed.
+func CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	if err == nil || !currentConfig().EnableSentry {
+		return
+	}
+	w := sentryClient.Load()
+	if w == nil {
+		return
+	}
+	rd := activeRedactor.Load()
+	ev := SentryEvent{Err: err, Tags: map[string]string{}, Extras: map[string]any{}}
+	for k, v := range staticLabels() {
+		ev.Tags[k] = v
+	}
+	for k, v := range tags {
+		ev.Tags[k] = rd.redactValue(k, v).(string)
+	}
+	for k, v := range rd.redactMap(extras) {
+		if str, ok := v.(string); ok {
+			if cut, truncated := truncateValue(str); truncated {
+				ev.Extras[k] = cut
+				ev.Extras[k+"_truncated"] = true
+				continue
+			}
+		}
+		ev.Extras[k] = v
+	}
+	if code := errorCode(err); code != "" {
+		ev.Tags["error.code"] = code
+	}
+	if route, ok := tenantRoute(ctx); ok {
+		ev.DSN = route.SentryDSN
+	}
+	w.capture(ctx, ev)
+}
+
+// sensitiveHeaders are never attached to Sentry events.
+var sensitiveHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie",
+	"X-Api-Key", "X-Auth-Token", "X-Csrf-Token",
+}
+
+// withSentryRequest binds a per-request Sentry scope to ctx holding the
+// request, the client IP and the trace ID.
+func withSentryRequest(ctx context.Context, r *http.Request, clientIP string) context.Context {
+	if !currentConfig().EnableSentry {
+		return ctx
+	}
+	w := sentryClient.Load()
+	if w == nil {
+		return ctx
+	}
+	// The clone only gets a copy of the headers; the body is not read.
+	req := r.Clone(ctx)
+	for _, h := range sensitiveHeaders {
+		req.Header.Del(h)
+	}
+	ctx = w.backend.WithRequest(ctx, req, clientIP)
+	return context.WithValue(ctx, sentryScopeKey{}, w.backend)
+}
+
+// addBreadcrumb records a log line on the request's Sentry scope, if any.
+func addBreadcrumb(ctx context.Context, category, level, msg string, fields []zap.Field) {
+	if b := sentryScope(ctx); b != nil {
+		b.AddBreadcrumb(ctx, Breadcrumb{Type: "default", Category: category, Level: level, Message: msg, Fields: fields})
+	}
+}
@@ -0,0 +1,102 @@
+package eotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SentryOptions are the Sentry client options InitEOTEL derives from
+// Config.
+type SentryOptions struct {
+	DSN              string
+	Environment      string
+	Release          string
+	SampleRate       float64
+	TracesSampleRate float64
+	EnableTracing    bool
+	Debug            bool
+	// BeforeSend is Config.SentryBeforeSend.
+	BeforeSend any
+	HTTPClient *http.Client
+	// Logger receives the backend's own warnings.
+	Logger *zap.Logger
+}
+
+// SentryEvent is a captured error with the tags, extras and grouping eotel
+// computed for it. DSN, when set, is the tenant's project to send it to
+// instead of the shared one.
+type SentryEvent struct {
+	Err         error
+	Tags        map[string]string
+	Extras      map[string]any
+	Fingerprint []string
+	DSN         string
+}
+
+// Breadcrumb is a log line or skipped error recorded on a request's Sentry
+// scope. Level is an eotel level name.
+type Breadcrumb struct {
+	Type     string
+	Category string
+	Level    string
+	Message  string
+	Fields   []zap.Field
+}
+
+// SentryBackend is the Sentry SDK as eotel uses it. The eotelsentry module
+// provides it and registers it when imported, as the root package does; the
+// core only depends on this interface.
+type SentryBackend interface {
+	// Install makes the backend's client the process-wide one.
+	Install()
+	// WithRequest binds a request scope holding r and clientIP to ctx.
+	WithRequest(ctx context.Context, r *http.Request, clientIP string) context.Context
+	// AddBreadcrumb records b on the request scope bound to ctx, if any.
+	AddBreadcrumb(ctx context.Context, b Breadcrumb)
+	// Prepare applies ev to a copy of the scope bound to ctx and returns the
+	// function sending it. It runs on the capturing goroutine so later
+	// changes to the request scope do not leak into the event; send runs on
+	// the worker.
+	Prepare(ctx context.Context, ev SentryEvent) (send func())
+	// Flush waits up to timeout for sent events to be delivered.
+	Flush(timeout time.Duration) bool
+}
+
+var sentryBackends struct {
+	mu  sync.Mutex
+	new func(SentryOptions) (SentryBackend, error)
+}
+
+// RegisterSentryBackend sets the constructor InitEOTEL uses when
+// Config.EnableSentry is set.
+func RegisterSentryBackend(newBackend func(SentryOptions) (SentryBackend, error)) {
+	sentryBackends.mu.Lock()
+	sentryBackends.new = newBackend
+	sentryBackends.mu.Unlock()
+}
+
+func newSentryBackend(cfg Config) (SentryBackend, error) {
+	sentryBackends.mu.Lock()
+	newBackend := sentryBackends.new
+	sentryBackends.mu.Unlock()
+	if newBackend == nil {
+		return nil, errors.New("no backend registered; import github.com/nicedev97/eotel-v2/eotelsentry")
+	}
+	return newBackend(sentryOptions(cfg))
+}
+
+type sentryScopeKey struct{}
+
+// sentryScope returns the backend that bound a request scope to ctx.
+func sentryScope(ctx context.Context) SentryBackend {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(sentryScopeKey{}).(SentryBackend)
+	return b
+}
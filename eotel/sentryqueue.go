@@ -0,0 +1,250 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const defaultSentryQueueSize = 256
+
+// SentryFingerprintRule groups matching errors under Fingerprint in Sentry.
+// An error matches when its Go type (as printed by %T) equals Type and its
+// message matches the Match regexp; empty conditions always match.
+// Fingerprint entries may use Sentry's "{{ default }}" placeholder.
+type SentryFingerprintRule struct {
+	Type        string   `yaml:"type"`
+	Match       string   `yaml:"match"`
+	Fingerprint []string `yaml:"fingerprint"`
+}
+
+type fingerprintRule struct {
+	typ         string
+	match       *regexp.Regexp
+	fingerprint []string
+}
+
+func compileFingerprintRules(rules []SentryFingerprintRule) ([]fingerprintRule, error) {
+	out := make([]fingerprintRule, 0, len(rules))
+	for i, r := range rules {
+		fr := fingerprintRule{typ: r.Type, fingerprint: r.Fingerprint}
+		if r.Match != "" {
+			re, err := regexp.Compile(r.Match)
+			if err != nil {
+				return nil, fmt.Errorf("sentry fingerprint rule %d: %w", i, err)
+			}
+			fr.match = re
+		}
+		out = append(out, fr)
+	}
+	return out, nil
+}
+
+// fingerprint returns the grouping of err: the first matching rule, else the
+// error code, else nil for Sentry's default grouping.
+func fingerprint(rules []fingerprintRule, err error) []string {
+	for _, r := range rules {
+		if r.typ != "" && r.typ != fmt.Sprintf("%T", err) {
+			continue
+		}
+		if r.match != nil && !r.match.MatchString(err.Error()) {
+			continue
+		}
+		return r.fingerprint
+	}
+	if code := errorCode(err); code != "" {
+		return []string{"{{ default }}", code}
+	}
+	return nil
+}
+
+var sentryClient atomic.Pointer[sentryWorker]
+
+// sentryWorker sends captured errors from a single goroutine so callers never
+// wait on Sentry. Errors with the same grouping seen again within the dedup
+// window are counted instead of sent; the count is attached to the next
+// event of the group as duplicates_suppressed.
+type sentryWorker struct {
+	backend SentryBackend
+	rules   []fingerprintRule
+	window  time.Duration
+	queue   *exportQueue[func()]
+	flushCh chan chan struct{}
+	quit    chan struct{}
+	pending atomic.Int64
+
+	mu   sync.Mutex
+	seen map[string]*dedupEntry
+
+	dropped metric.Int64Counter
+}
+
+type dedupEntry struct {
+	until      time.Time
+	suppressed int
+}
+
+// newSentryWorker builds a worker sending through backend without starting
+// it; start installs it in place of the running one and stop releases it
+// either way.
+func newSentryWorker(cfg Config, backend SentryBackend, meter metric.Meter) (*sentryWorker, error) {
+	rules, err := compileFingerprintRules(cfg.SentryFingerprintRules)
+	if err != nil {
+		return nil, err
+	}
+	size := cfg.SentryQueueSize
+	if size <= 0 {
+		size = defaultSentryQueueSize
+	}
+	w := &sentryWorker{
+		backend: backend,
+		rules:   rules,
+		window:  cfg.SentryDedupWindow,
+		flushCh: make(chan chan struct{}),
+		quit:    make(chan struct{}),
+		seen:    map[string]*dedupEntry{},
+	}
+	w.queue, err = newExportQueue[func()]("sentry", size, cfg.SentryQueue, cfg.DiskBuffer, nil, meter)
+	if err != nil {
+		return nil, err
+	}
+	w.dropped, _ = meter.Int64Counter("sentry_events_dropped_total")
+	return w, nil
+}
+
+func (w *sentryWorker) start() {
+	w.backend.Install()
+	go w.run()
+	if old := sentryClient.Swap(w); old != nil {
+		old.stop()
+	}
+}
+
+// capture groups ev, drops it when a duplicate, and queues it prepared
+// against the scope of ctx.
+func (w *sentryWorker) capture(ctx context.Context, ev SentryEvent) {
+	ev.Fingerprint = fingerprint(w.rules, ev.Err)
+	suppressed, send := w.admit(dedupKey(ev.Fingerprint, ev.Err))
+	if !send {
+		w.dropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", "duplicate")))
+		return
+	}
+	if suppressed > 0 {
+		ev.Extras["duplicates_suppressed"] = suppressed
+	}
+
+	ok, evicted := w.queue.push(w.backend.Prepare(ctx, ev))
+	if evicted > 0 {
+		sentryStatus.drop(evicted)
+		w.dropped.Add(context.Background(), int64(evicted), metric.WithAttributes(attribute.String("reason", "evicted")))
+	}
+	if !ok {
+		sentryStatus.drop(1)
+		w.dropped.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", "queue_full")))
+		return
+	}
+	w.pending.Add(int64(1 - evicted))
+}
+
+func dedupKey(fp []string, err error) string {
+	if fp != nil {
+		return strings.Join(fp, "\x00")
+	}
+	return fmt.Sprintf("%T\x00%s", err, err.Error())
+}
+
+// admit reports whether an error of group key should be sent, and how many
+// were suppressed since the group was last sent.
+func (w *sentryWorker) admit(key string) (int, bool) {
+	if w.window <= 0 {
+		return 0, true
+	}
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if e, ok := w.seen[key]; ok && now.Before(e.until) {
+		e.suppressed++
+		return 0, false
+	}
+	suppressed := 0
+	if e, ok := w.seen[key]; ok {
+		suppressed = e.suppressed
+	}
+	if len(w.seen) > 1000 {
+		for k, e := range w.seen {
+			if now.After(e.until) {
+				delete(w.seen, k)
+			}
+		}
+	}
+	w.seen[key] = &dedupEntry{until: now.Add(w.window)}
+	return suppressed, true
+}
+
+func (w *sentryWorker) run() {
+	for {
+		select {
+		case <-w.quit:
+			return
+		case <-w.queue.ready:
+			w.send()
+		case done := <-w.flushCh:
+			w.send()
+			close(done)
+		}
+	}
+}
+
+func (w *sentryWorker) send() {
+	for {
+		events := w.queue.take(defaultSentryQueueSize)
+		if len(events) == 0 {
+			return
+		}
+		for _, send := range events {
+			send()
+			w.pending.Add(-1)
+		}
+	}
+}
+
+func (w *sentryWorker) stop() {
+	w.queue.close()
+	close(w.quit)
+}
+
+// drainSentry hands every queued error to the Sentry backend; callers still
+// need its Flush to wait for delivery.
+func drainSentry(ctx context.Context) error {
+	w := sentryClient.Load()
+	if w == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	select {
+	case w.flushCh <- done:
+	case <-ctx.Done():
+		return fmt.Errorf("%d sentry events not sent: %w", w.pending.Load(), ctx.Err())
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("%d sentry events not sent: %w", w.pending.Load(), ctx.Err())
+	}
+	return nil
+}
+
+// flushSentry waits up to timeout for the running backend to deliver what
+// drainSentry handed it.
+func flushSentry(timeout time.Duration) bool {
+	w := sentryClient.Load()
+	return w == nil || w.backend.Flush(timeout)
+}
@@ -0,0 +1,50 @@
+package eotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithService attributes telemetry of the returned logger to an embedded
+// sub-component: its logs carry service and job set to name, spans started
+// from it get peer.service, and its metrics a service attribute.
+func (l *Eotel) WithService(name string) *Eotel {
+	if l == nil {
+		return Noop("WithService")
+	}
+	cp := l.clone()
+	cp.service = name
+	if cp.span != nil {
+		cp.span.SetAttributes(attribute.String("peer.service", name))
+	}
+	return cp
+}
+
+func (l *Eotel) serviceName() string {
+	if l.service != "" {
+		return l.service
+	}
+	if l.provider != nil {
+		return l.provider.cfg.ServiceName
+	}
+	return currentConfig().ServiceName
+}
+
+func (l *Eotel) jobName() string {
+	if l.service != "" {
+		return l.service
+	}
+	if l.provider != nil {
+		return l.provider.cfg.JobName
+	}
+	return currentConfig().JobName
+}
+
+// serviceAttrs adds the service override, if any, and the correlated span
+// attributes to metric attributes.
+func (l *Eotel) serviceAttrs(attrs []attribute.KeyValue) []attribute.KeyValue {
+	attrs = l.correlatedAttrs(attrs)
+	if l.service == "" {
+		return attrs
+	}
+	return append(attrs[:len(attrs):len(attrs)], attribute.String("service", l.service))
+}
@@ -0,0 +1,24 @@
+package eotel
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashSessionID returns the session.id value for a raw session identifier:
+// HMAC-SHA256 keyed with Config.SessionHashKey (plain SHA-256 when unset),
+// truncated to 128 bits and hex encoded. Every service configured with the
+// same key produces the same value.
+func HashSessionID(id string) string {
+	var sum []byte
+	if key := currentConfig().SessionHashKey; key != "" {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(id))
+		sum = mac.Sum(nil)
+	} else {
+		h := sha256.Sum256([]byte(id))
+		sum = h[:]
+	}
+	return hex.EncodeToString(sum[:16])
+}
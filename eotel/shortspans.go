@@ -0,0 +1,140 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ShortSpans actions.
+const (
+	ShortSpanDrop  = "drop"
+	ShortSpanMerge = "merge"
+)
+
+// ShortSpans removes spans shorter than Threshold, such as sub-millisecond
+// cache hits, from exported traces. Action ShortSpanDrop (the default)
+// discards them; ShortSpanMerge records each as an event on its parent,
+// with its duration and attributes. Spans that failed, recorded an
+// exception, have no local parent, or are the parent of a kept span are
+// always exported. Removed spans are counted in eotel.span.denoised.
+type ShortSpans struct {
+	Threshold time.Duration `yaml:"threshold"`
+	Action    string        `yaml:"action"`
+}
+
+func (s ShortSpans) validate() error {
+	switch s.Action {
+	case "", ShortSpanDrop, ShortSpanMerge:
+		return nil
+	}
+	return fmt.Errorf("short spans: unknown action %q", s.Action)
+}
+
+type shortSpanProcessor struct {
+	next      sdktrace.SpanProcessor
+	threshold time.Duration
+	merge     bool
+
+	mu sync.Mutex
+	// open holds the spans still running, the merge and pin targets.
+	open map[trace.SpanID]sdktrace.ReadWriteSpan
+	// pinned holds the running parents of exported spans, which must be
+	// exported too so the trace stays connected.
+	pinned map[trace.SpanID]struct{}
+
+	denoised metric.Int64Counter
+}
+
+func newShortSpanProcessor(next sdktrace.SpanProcessor, cfg ShortSpans, meter metric.Meter) *shortSpanProcessor {
+	p := &shortSpanProcessor{
+		next:      next,
+		threshold: cfg.Threshold,
+		merge:     cfg.Action == ShortSpanMerge,
+		open:      map[trace.SpanID]sdktrace.ReadWriteSpan{},
+		pinned:    map[trace.SpanID]struct{}{},
+	}
+	p.denoised, _ = meter.Int64Counter("eotel.span.denoised",
+		metric.WithUnit("{span}"),
+		metric.WithDescription("Spans below the short span threshold that were dropped or merged into their parent."))
+	return p
+}
+
+func (p *shortSpanProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	p.mu.Lock()
+	p.open[s.SpanContext().SpanID()] = s
+	p.mu.Unlock()
+	p.next.OnStart(ctx, s)
+}
+
+func (p *shortSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	id, parent := s.SpanContext().SpanID(), s.Parent()
+	local := parent.IsValid() && !parent.IsRemote()
+
+	p.mu.Lock()
+	_, pinned := p.pinned[id]
+	delete(p.pinned, id)
+	delete(p.open, id)
+	remove := local && !pinned && p.short(s)
+	target, running := p.open[parent.SpanID()]
+	if remove && p.merge {
+		// Without a running parent there is nothing to merge into.
+		remove = running
+	}
+	if !remove && running {
+		p.pinned[parent.SpanID()] = struct{}{}
+	}
+	p.mu.Unlock()
+
+	switch {
+	case !remove:
+		p.next.OnEnd(s)
+	case p.merge:
+		mergeSpan(target, s)
+		p.count(ShortSpanMerge)
+	default:
+		p.count(ShortSpanDrop)
+	}
+}
+
+// short reports whether s is a removal candidate: under the threshold and
+// without an error.
+func (p *shortSpanProcessor) short(s sdktrace.ReadOnlySpan) bool {
+	if s.EndTime().Sub(s.StartTime()) >= p.threshold || s.Status().Code == codes.Error {
+		return false
+	}
+	for _, e := range s.Events() {
+		if e.Name == "exception" {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeSpan records s as an event on parent.
+func mergeSpan(parent sdktrace.ReadWriteSpan, s sdktrace.ReadOnlySpan) {
+	attrs := append([]attribute.KeyValue{
+		attribute.Bool("span.merged", true),
+		attribute.Float64("span.duration_ms", float64(s.EndTime().Sub(s.StartTime()))/float64(time.Millisecond)),
+	}, s.Attributes()...)
+	parent.AddEvent(s.Name(), trace.WithTimestamp(s.StartTime()), trace.WithAttributes(attrs...))
+}
+
+func (p *shortSpanProcessor) count(action string) {
+	p.denoised.Add(context.Background(), 1, metric.WithAttributes(attribute.String("action", action)))
+}
+
+func (p *shortSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *shortSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package eotel
+
+import "os"
+
+// Without SIGHUP, SIGUSR1 and SIGUSR2 the signal handlers are unavailable;
+// Reload and WriteDiagnostics still work.
+var reloadSignal, debugSignal, dumpSignal os.Signal
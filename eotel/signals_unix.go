@@ -0,0 +1,14 @@
+//go:build unix
+
+package eotel
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	reloadSignal os.Signal = syscall.SIGHUP
+	debugSignal  os.Signal = syscall.SIGUSR1
+	dumpSignal   os.Signal = syscall.SIGUSR2
+)
@@ -0,0 +1,39 @@
+package eotel
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newNativeSink returns a zap core writing to the OS log facility selected by
+// Config.NativeSink, or nil when no native sink is configured.
+func newNativeSink(cfg Config) (zapcore.Core, error) {
+	switch cfg.NativeSink {
+	case "":
+		return nil, nil
+	case "journald":
+		return newJournaldCore(cfg.ServiceName, zapcore.InfoLevel)
+	case "eventlog":
+		return newEventLogCore(cfg.ServiceName, zapcore.InfoLevel)
+	default:
+		return nil, fmt.Errorf("unknown native sink %q", cfg.NativeSink)
+	}
+}
+
+func teeNativeSink(logger *zap.Logger, sink zapcore.Core) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, sink)
+	}))
+}
+
+// fieldMap flattens zap fields for sinks and exporters that take key/value
+// pairs.
+func fieldMap(fields []zapcore.Field) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
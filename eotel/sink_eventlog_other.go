@@ -0,0 +1,13 @@
+//go:build !windows
+
+package eotel
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newEventLogCore(string, zapcore.Level) (zapcore.Core, error) {
+	return nil, errors.New("eventlog sink is only available on windows")
+}
@@ -0,0 +1,59 @@
+package eotel
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+const eventLogID = 1
+
+type eventLogCore struct {
+	zapcore.LevelEnabler
+	log    *eventlog.Log
+	fields []zapcore.Field
+}
+
+// newEventLogCore writes to the Windows Event Log under the service name as
+// source. The source must be registered (eventlog.InstallAsEventCreate) by
+// the installer since that requires administrator rights.
+func newEventLogCore(source string, level zapcore.Level) (zapcore.Core, error) {
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("eventlog: %w", err)
+	}
+	return &eventLogCore{LevelEnabler: level, log: l}, nil
+}
+
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	cp := *c
+	cp.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &cp
+}
+
+func (c *eventLogCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+	return ce
+}
+
+func (c *eventLogCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	msg := e.Message
+	if m := fieldMap(append(c.fields[:len(c.fields):len(c.fields)], fields...)); len(m) > 0 {
+		if data, err := json.Marshal(m); err == nil {
+			msg += " " + string(data)
+		}
+	}
+	switch {
+	case e.Level >= zapcore.ErrorLevel:
+		return c.log.Error(eventLogID, msg)
+	case e.Level >= zapcore.WarnLevel:
+		return c.log.Warning(eventLogID, msg)
+	}
+	return c.log.Info(eventLogID, msg)
+}
+
+func (c *eventLogCore) Sync() error { return nil }
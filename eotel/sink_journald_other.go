@@ -0,0 +1,13 @@
+//go:build !linux
+
+package eotel
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newJournaldCore(string, zapcore.Level) (zapcore.Core, error) {
+	return nil, errors.New("journald sink is only available on linux")
+}
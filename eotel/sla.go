@@ -0,0 +1,60 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	slaOnce     sync.Once
+	slaBreaches metric.Int64Counter
+)
+
+// routeSLA returns the latency objective for a route, looked up in
+// Config.RouteSLAs as "METHOD route" first and then as the bare route.
+func routeSLA(method, route string) (time.Duration, bool) {
+	slas := currentConfig().RouteSLAs
+	if len(slas) == 0 {
+		return 0, false
+	}
+	if d, ok := slas[method+" "+route]; ok {
+		return d, true
+	}
+	d, ok := slas[route]
+	return d, ok
+}
+
+// checkSLA emits an sla.breached span event, a warning and a breach count when
+// a request to route took longer than its SLA.
+func checkSLA(span trace.Span, logger *Eotel, method, route string, elapsed time.Duration) {
+	sla, ok := routeSLA(method, route)
+	if !ok || sla <= 0 || elapsed <= sla {
+		return
+	}
+	slaMs := sla.Seconds() * 1000
+	elapsedMs := elapsed.Seconds() * 1000
+	span.AddEvent("sla.breached", trace.WithAttributes(
+		attribute.Float64("sla.threshold_ms", slaMs),
+		attribute.Float64("duration_ms", elapsedMs),
+	))
+
+	slaOnce.Do(func() {
+		slaBreaches, _ = getMeter().Int64Counter("http_server_sla_breaches_total",
+			metric.WithDescription("Requests slower than their route's configured SLA."))
+	})
+	if slaBreaches != nil {
+		slaBreaches.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("http.route", route),
+		))
+	}
+
+	logger.WithField("sla.threshold_ms", slaMs).
+		WithField("duration_ms", elapsedMs).
+		Warn("route SLA breached")
+}
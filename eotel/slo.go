@@ -0,0 +1,188 @@
+package eotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	defaultSLOTarget = 0.99
+	defaultSLOWindow = time.Hour
+	sloSlots         = 60
+)
+
+// Objective is a latency SLO: a share Target of operations must succeed
+// within Threshold. The HTTP middlewares observe objectives named after the
+// route ("GET /users/:id", then "/users/:id") and Job those named after the
+// job; Observe covers anything else.
+//
+// Every observation counts in eotel.slo.events by outcome (good, slow or
+// error), and eotel.slo.burn_rate reports how fast the error budget is
+// being spent over the objective's window: 1 spends it exactly by the end
+// of the window, above 1 sooner.
+type Objective struct {
+	name      string
+	threshold time.Duration
+	target    float64
+	window    sloWindow
+}
+
+// SLOOption configures an Objective.
+type SLOOption func(*Objective)
+
+// SLOTarget sets the share of good operations, 0.99 by default.
+func SLOTarget(target float64) SLOOption {
+	return func(o *Objective) {
+		if target > 0 && target < 1 {
+			o.target = target
+		}
+	}
+}
+
+// SLOWindow sets the window the burn rate is computed over, an hour by
+// default.
+func SLOWindow(d time.Duration) SLOOption {
+	return func(o *Objective) {
+		if d > 0 {
+			o.window.width = d / sloSlots
+		}
+	}
+}
+
+var slos struct {
+	mu     sync.RWMutex
+	byName map[string]*Objective
+}
+
+// SLO declares the objective name, replacing any earlier one of that name.
+func SLO(name string, threshold time.Duration, opts ...SLOOption) *Objective {
+	o := &Objective{name: name, threshold: threshold, target: defaultSLOTarget}
+	o.window.width = defaultSLOWindow / sloSlots
+	for _, opt := range opts {
+		opt(o)
+	}
+	slos.mu.Lock()
+	if slos.byName == nil {
+		slos.byName = map[string]*Objective{}
+	}
+	slos.byName[name] = o
+	slos.mu.Unlock()
+	return o
+}
+
+func lookupSLO(names ...string) *Objective {
+	slos.mu.RLock()
+	defer slos.mu.RUnlock()
+	for _, n := range names {
+		if o, ok := slos.byName[n]; ok {
+			return o
+		}
+	}
+	return nil
+}
+
+// observeRouteSLO is called by the HTTP middlewares once the response is
+// written.
+func observeRouteSLO(ctx context.Context, method, route string, elapsed time.Duration, status int) {
+	if route == "" {
+		return
+	}
+	if o := lookupSLO(method+" "+route, route); o != nil {
+		o.Observe(ctx, elapsed, status >= 500)
+	}
+}
+
+var (
+	sloOnce   sync.Once
+	sloEvents metric.Int64Counter
+)
+
+func initSLOMetrics() {
+	m := getMeter()
+	sloEvents, _ = m.Int64Counter("eotel.slo.events",
+		metric.WithUnit("{event}"),
+		metric.WithDescription("Operations observed against a latency SLO, by outcome."))
+	_, _ = m.Float64ObservableGauge("eotel.slo.burn_rate",
+		metric.WithDescription("Error budget burn rate of each SLO over its window."),
+		metric.WithFloat64Callback(func(_ context.Context, obs metric.Float64Observer) error {
+			now := time.Now()
+			slos.mu.RLock()
+			defer slos.mu.RUnlock()
+			for _, o := range slos.byName {
+				bad, total := o.window.sum(now)
+				if total == 0 {
+					continue
+				}
+				rate := float64(bad) / float64(total) / (1 - o.target)
+				obs.Observe(rate, metric.WithAttributes(attribute.String("slo.name", o.name)))
+			}
+			return nil
+		}))
+}
+
+// Observe records one operation that took elapsed and failed or not. A
+// failed operation is an error regardless of its latency.
+func (o *Objective) Observe(ctx context.Context, elapsed time.Duration, failed bool) {
+	if o == nil {
+		return
+	}
+	sloOnce.Do(initSLOMetrics)
+	outcome := "good"
+	switch {
+	case failed:
+		outcome = "error"
+	case elapsed > o.threshold:
+		outcome = "slow"
+	}
+	o.window.add(time.Now(), outcome != "good")
+	if sloEvents != nil {
+		sloEvents.Add(context.WithoutCancel(ctx), 1, metric.WithAttributes(
+			attribute.String("slo.name", o.name),
+			attribute.String("slo.outcome", outcome),
+		))
+	}
+}
+
+// sloWindow counts good and bad events in sloSlots rotating slots of width
+// each.
+type sloWindow struct {
+	width time.Duration
+
+	mu    sync.Mutex
+	slots [sloSlots]struct {
+		at        int64
+		good, bad int64
+	}
+}
+
+func (w *sloWindow) add(now time.Time, bad bool) {
+	at := now.UnixNano() / int64(w.width)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s := &w.slots[at%sloSlots]
+	if s.at != at {
+		s.at, s.good, s.bad = at, 0, 0
+	}
+	if bad {
+		s.bad++
+	} else {
+		s.good++
+	}
+}
+
+func (w *sloWindow) sum(now time.Time) (bad, total int64) {
+	at := now.UnixNano() / int64(w.width)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, s := range w.slots {
+		if at-s.at < sloSlots {
+			bad += s.bad
+			total += s.good + s.bad
+		}
+	}
+	return bad, total
+}
@@ -0,0 +1,80 @@
+package eotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Snapshot is the part of a logger worth keeping past the request: the
+// span context, baggage and fields. Unlike the logger itself it holds no
+// reference to the request context, so it is safe to retain in callbacks
+// and goroutines that outlive the request.
+type Snapshot struct {
+	spanContext trace.SpanContext
+	baggage     baggage.Baggage
+	fields      []zap.Field
+	attrs       []attribute.KeyValue
+	name        string
+	service     string
+	requestID   string
+	exporter    Exporter
+	provider    *Provider
+}
+
+// Snapshot captures l's trace context and fields. The slices are capped, not
+// copied, so taking a snapshot costs no allocation beyond the struct.
+func (l *Eotel) Snapshot() Snapshot {
+	if l == nil {
+		return Snapshot{}
+	}
+	return Snapshot{
+		spanContext: l.activeSpan().SpanContext(),
+		baggage:     baggage.FromContext(l.ctx),
+		fields:      l.fields[:len(l.fields):len(l.fields)],
+		attrs:       l.attrs[:len(l.attrs):len(l.attrs)],
+		name:        l.name,
+		service:     l.service,
+		requestID:   l.requestID,
+		exporter:    l.exporter,
+		provider:    l.provider,
+	}
+}
+
+// Logger rebuilds a logger on ctx carrying the snapshot's trace context and
+// fields. Logs correlate with the original span; span events are dropped
+// since that span may have ended.
+func (s Snapshot) Logger(ctx context.Context) *Eotel {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.spanContext.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, s.spanContext)
+	}
+	if s.baggage.Len() > 0 {
+		ctx = baggage.ContextWithBaggage(ctx, s.baggage)
+	}
+	name := s.name
+	if name == "" {
+		name = "snapshot"
+	}
+	var l *Eotel
+	if s.provider != nil {
+		l = s.provider.New(ctx, name)
+	} else {
+		l = New(ctx, name)
+	}
+	l.fields = s.fields[:len(s.fields):len(s.fields)]
+	l.attrs = s.attrs[:len(s.attrs):len(s.attrs)]
+	l.service = s.service
+	l.requestID = s.requestID
+	if s.exporter != nil {
+		l.exporter = s.exporter
+	}
+	l.start = time.Now()
+	return l
+}
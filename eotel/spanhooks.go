@@ -0,0 +1,36 @@
+package eotel
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// onEndProcessor adapts Config.OnSpanEnd to a span processor.
+type onEndProcessor struct {
+	fn func(sdktrace.ReadOnlySpan)
+}
+
+func (p onEndProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p onEndProcessor) OnEnd(s sdktrace.ReadOnlySpan) { p.fn(s) }
+
+func (p onEndProcessor) Shutdown(context.Context) error { return nil }
+
+func (p onEndProcessor) ForceFlush(context.Context) error { return nil }
+
+// userSpanProcessors returns the processors from Config.SpanProcessors and
+// Config.OnSpanEnd. They are registered ahead of the exporting processor so
+// attributes set in OnStart make it into the export.
+func userSpanProcessors(cfg Config) []sdktrace.SpanProcessor {
+	sps := make([]sdktrace.SpanProcessor, 0, len(cfg.SpanProcessors)+1)
+	for _, sp := range cfg.SpanProcessors {
+		if sp != nil {
+			sps = append(sps, sp)
+		}
+	}
+	if cfg.OnSpanEnd != nil {
+		sps = append(sps, onEndProcessor{fn: cfg.OnSpanEnd})
+	}
+	return sps
+}
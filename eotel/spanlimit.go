@@ -0,0 +1,101 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanLimiter caps the number of spans recorded per trace. As the sampler it
+// drops every span past the limit, so they are never recorded, and marks the
+// local root span as truncated while it is still open; as a span processor
+// it tracks the open spans of each trace so the budget is released once the
+// trace ends.
+type spanLimiter struct {
+	next   sdktrace.Sampler
+	limit  int
+	mu     sync.Mutex
+	traces map[trace.TraceID]*traceBudget
+}
+
+type traceBudget struct {
+	root    sdktrace.ReadWriteSpan
+	spans   int
+	open    int
+	dropped int
+}
+
+func newSpanLimiter(next sdktrace.Sampler, limit int) *spanLimiter {
+	return &spanLimiter{
+		next:   next,
+		limit:  limit,
+		traces: map[trace.TraceID]*traceBudget{},
+	}
+}
+
+func (l *spanLimiter) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := l.next.ShouldSample(p)
+	if res.Decision == sdktrace.Drop {
+		return res
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.traces[p.TraceID]
+	if !ok {
+		b = &traceBudget{}
+		l.traces[p.TraceID] = b
+	}
+	if b.spans < l.limit {
+		b.spans++
+		return res
+	}
+	b.dropped++
+	if b.root != nil {
+		b.root.SetAttributes(
+			attribute.Bool("trace.truncated", true),
+			attribute.Int("trace.dropped_spans", b.dropped),
+		)
+	}
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.Drop,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (l *spanLimiter) Description() string {
+	return fmt.Sprintf("SpanLimiter{limit=%d,%s}", l.limit, l.next.Description())
+}
+
+func (l *spanLimiter) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.traces[s.SpanContext().TraceID()]
+	if !ok {
+		return
+	}
+	if b.root == nil {
+		b.root = s
+	}
+	b.open++
+}
+
+func (l *spanLimiter) OnEnd(s sdktrace.ReadOnlySpan) {
+	id := s.SpanContext().TraceID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.traces[id]; ok {
+		b.open--
+		if b.open <= 0 {
+			delete(l.traces, id)
+		}
+	}
+}
+
+func (l *spanLimiter) Shutdown(context.Context) error { return nil }
+
+func (l *spanLimiter) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,48 @@
+package eotel
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanLimiterDropsInSampler(t *testing.T) {
+	limiter := newSpanLimiter(sdktrace.AlwaysSample(), 2)
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(limiter),
+		sdktrace.WithSpanProcessor(limiter),
+		sdktrace.WithSpanProcessor(rec),
+	)
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "root")
+	for range 3 {
+		_, child := tracer.Start(ctx, "child")
+		child.End()
+	}
+	_, late := tracer.Start(ctx, "late")
+	if late.IsRecording() {
+		t.Error("span past the limit is recording, want it dropped by the sampler")
+	}
+	late.End()
+	root.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[1].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if !attrs["trace.truncated"].AsBool() || attrs["trace.dropped_spans"].AsInt64() != 3 {
+		t.Errorf("root attributes = %v, want truncated with 3 dropped spans", spans[1].Attributes())
+	}
+	if n := len(limiter.traces); n != 0 {
+		t.Errorf("%d trace budgets left after the trace ended", n)
+	}
+}
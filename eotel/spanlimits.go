@@ -0,0 +1,112 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const truncatedMarker = "...[truncated]"
+
+// SpanLimits caps what one span carries. Zero keeps the SDK default (and
+// its OTEL_SPAN_* environment variables): 128 attributes, 128 events, 128
+// attributes per event and unlimited value length; negative lifts a limit.
+//
+// String attributes added through the logger are cut with a truncation
+// marker and flagged with "<key>_truncated"; the SDK cuts other values
+// silently and reports dropped attributes and events as dropped counts on
+// the span. eotel.span.truncated counts the values the logger cut and
+// everything the SDK dropped.
+type SpanLimits struct {
+	AttributeValueLength   int `yaml:"attribute_value_length"`
+	AttributeCount         int `yaml:"attribute_count"`
+	EventCount             int `yaml:"event_count"`
+	AttributePerEventCount int `yaml:"attribute_per_event_count"`
+}
+
+func (l SpanLimits) enabled() bool {
+	return l != SpanLimits{}
+}
+
+func (l SpanLimits) sdk() sdktrace.SpanLimits {
+	out := sdktrace.NewSpanLimits()
+	set := func(dst *int, v int) {
+		if v != 0 {
+			*dst = v
+		}
+	}
+	set(&out.AttributeValueLengthLimit, l.AttributeValueLength)
+	set(&out.AttributeCountLimit, l.AttributeCount)
+	set(&out.EventCountLimit, l.EventCount)
+	set(&out.AttributePerEventCountLimit, l.AttributePerEventCount)
+	return out
+}
+
+func (l SpanLimits) validate() error {
+	if n := l.AttributeValueLength; n > 0 && n <= len(truncatedMarker) {
+		return fmt.Errorf("span limits: attribute value length %d leaves no room for the truncation marker", n)
+	}
+	return nil
+}
+
+var (
+	spanTruncatedOnce    sync.Once
+	spanTruncatedCounter metric.Int64Counter
+)
+
+// spanTruncated counts n items of kind (value, attribute, event,
+// event_attribute, link) cut from spans.
+func spanTruncated(kind string, n int) {
+	if n <= 0 {
+		return
+	}
+	spanTruncatedOnce.Do(func() {
+		spanTruncatedCounter, _ = getMeter().Int64Counter("eotel.span.truncated",
+			metric.WithUnit("{item}"),
+			metric.WithDescription("Span attribute values cut and attributes, events and links dropped by span limits."))
+	})
+	if spanTruncatedCounter != nil {
+		spanTruncatedCounter.Add(context.Background(), int64(n), metric.WithAttributes(attribute.String("kind", kind)))
+	}
+}
+
+// limitAttr cuts a string attribute to SpanLimits.AttributeValueLength,
+// marker included, and reports whether it did.
+func limitAttr(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+	limit := currentConfig().SpanLimits.AttributeValueLength
+	if limit <= 0 || kv.Value.Type() != attribute.STRING {
+		return kv, false
+	}
+	s := kv.Value.AsString()
+	if len(s) <= limit {
+		return kv, false
+	}
+	cut := limit - len(truncatedMarker)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	spanTruncated("value", 1)
+	return attribute.String(string(kv.Key), s[:cut]+truncatedMarker), true
+}
+
+// spanLimitsProcessor counts what the SDK dropped from each span.
+type spanLimitsProcessor struct{}
+
+func (spanLimitsProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (spanLimitsProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	spanTruncated("attribute", s.DroppedAttributes())
+	spanTruncated("event", s.DroppedEvents())
+	spanTruncated("link", s.DroppedLinks())
+	for _, e := range s.Events() {
+		spanTruncated("event_attribute", e.DroppedAttributeCount)
+	}
+}
+
+func (spanLimitsProcessor) Shutdown(context.Context) error   { return nil }
+func (spanLimitsProcessor) ForceFlush(context.Context) error { return nil }
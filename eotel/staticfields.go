@@ -0,0 +1,119 @@
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// staticFieldSet holds the process-wide fields from Config.StaticFields and
+// WithGlobalFields in every shape they are needed in, built once.
+type staticFieldSet struct {
+	values map[string]any
+	fields []zap.Field
+	attrs  []attribute.KeyValue
+	labels map[string]string
+}
+
+var (
+	staticFields atomic.Pointer[staticFieldSet]
+
+	staticFieldsMu   sync.Mutex
+	configFields     map[string]any
+	registeredFields = map[string]any{}
+)
+
+// WithGlobalFields adds fields attached to every log line, span, Loki stream
+// and Sentry event of the process (env, region, instance ID, ...). They are
+// kept across InitEOTEL and override Config.StaticFields on conflict.
+func WithGlobalFields(m map[string]any) {
+	staticFieldsMu.Lock()
+	defer staticFieldsMu.Unlock()
+	for k, v := range m {
+		registeredFields[k] = v
+	}
+	rebuildStaticFields()
+}
+
+// setStaticFields installs Config.StaticFields.
+func setStaticFields(m map[string]any) {
+	staticFieldsMu.Lock()
+	defer staticFieldsMu.Unlock()
+	configFields = m
+	rebuildStaticFields()
+}
+
+func rebuildStaticFields() {
+	merged := make(map[string]any, len(configFields)+len(registeredFields))
+	for k, v := range configFields {
+		merged[k] = v
+	}
+	for k, v := range registeredFields {
+		merged[k] = v
+	}
+	staticFields.Store(newStaticFieldSet(merged))
+}
+
+func newStaticFieldSet(m map[string]any) *staticFieldSet {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var acc Eotel
+	labels := make(map[string]string, len(keys))
+	for _, k := range keys {
+		acc.addField(k, m[k])
+		labels[sanitizeLokiLabel(k)] = fmt.Sprintf("%v", activeRedactor.Load().redactValue(k, m[k]))
+	}
+	return &staticFieldSet{values: m, fields: acc.fields, attrs: acc.attrs, labels: labels}
+}
+
+// staticFieldsOf appends the global fields to fields.
+func staticFieldsOf(fields []zap.Field) []zap.Field {
+	s := staticFields.Load()
+	if s == nil || len(s.fields) == 0 {
+		return fields
+	}
+	return append(fields[:len(fields):len(fields)], s.fields...)
+}
+
+func staticLabels() map[string]string {
+	if s := staticFields.Load(); s != nil {
+		return s.labels
+	}
+	return nil
+}
+
+func isStaticField(key string) bool {
+	s := staticFields.Load()
+	if s == nil {
+		return false
+	}
+	_, ok := s.values[key]
+	return ok
+}
+
+// staticFieldsProcessor sets the global fields as attributes on every span.
+type staticFieldsProcessor struct{}
+
+func (staticFieldsProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	if sf := staticFields.Load(); sf != nil && len(sf.attrs) > 0 {
+		s.SetAttributes(sf.attrs...)
+	}
+}
+
+func (staticFieldsProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (staticFieldsProcessor) Shutdown(context.Context) error { return nil }
+
+func (staticFieldsProcessor) ForceFlush(context.Context) error { return nil }
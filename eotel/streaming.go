@@ -0,0 +1,167 @@
+package eotel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultStreamHeartbeat = 30 * time.Second
+
+// WithStreaming treats WebSocket upgrades and Server-Sent Events requests
+// (Accept: text/event-stream) as long-lived streams. Their span stays open
+// for the whole connection and gets a stream.heartbeat event every
+// heartbeat (30s when zero) with the messages so far. On close the span gets
+// the totals and the connection time goes to eotel.stream.duration rather
+// than the request latency histogram, and SLA and SLO checks skip it.
+//
+// SSE messages are counted on each flush; WebSocket handlers, which write to
+// the hijacked connection, count theirs with StreamSent and StreamReceived.
+func WithStreaming(heartbeat time.Duration) MiddlewareOption {
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	return func(cfg *middlewareConfig) {
+		cfg.streamHeartbeat = heartbeat
+	}
+}
+
+func isStream(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func streamKind(r *http.Request) string {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return "websocket"
+	}
+	return "sse"
+}
+
+var (
+	streamMetricsOnce sync.Once
+	streamMessages    metric.Int64Counter
+	streamDuration    metric.Float64Histogram
+	streamActive      metric.Int64UpDownCounter
+)
+
+func initStreamMetrics() {
+	streamMetricsOnce.Do(func() {
+		m := getMeter()
+		streamMessages, _ = m.Int64Counter("eotel.stream.messages",
+			metric.WithUnit("{message}"),
+			metric.WithDescription("Messages sent and received on streaming connections."))
+		streamDuration, _ = m.Float64Histogram("eotel.stream.duration",
+			metric.WithUnit("ms"),
+			metric.WithDescription("Lifetime of streaming connections."))
+		streamActive, _ = m.Int64UpDownCounter("eotel.stream.active",
+			metric.WithUnit("{connection}"),
+			metric.WithDescription("Streaming connections currently open."))
+	})
+}
+
+type streamCtxKey struct{}
+
+// stream tracks one streaming connection.
+type stream struct {
+	span     trace.Span
+	attrs    metric.MeasurementOption
+	start    time.Time
+	sent     atomic.Int64
+	received atomic.Int64
+	done     chan struct{}
+}
+
+// startStream registers the stream on ctx and starts its heartbeat.
+func startStream(ctx context.Context, span trace.Span, r *http.Request, route string, heartbeat time.Duration) (context.Context, *stream) {
+	initStreamMetrics()
+	kind := streamKind(r)
+	span.SetAttributes(attribute.String("stream.kind", kind))
+	s := &stream{
+		span: span,
+		attrs: metric.WithAttributeSet(attribute.NewSet(
+			attribute.String("http.route", route),
+			attribute.String("stream.kind", kind),
+		)),
+		start: time.Now(),
+		done:  make(chan struct{}),
+	}
+	if streamActive != nil {
+		streamActive.Add(context.WithoutCancel(ctx), 1, s.attrs)
+	}
+	go s.beat(heartbeat)
+	return context.WithValue(ctx, streamCtxKey{}, s), s
+}
+
+func (s *stream) beat(every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.span.AddEvent("stream.heartbeat", trace.WithAttributes(s.totals()...))
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *stream) totals() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("stream.messages.sent", s.sent.Load()),
+		attribute.Int64("stream.messages.received", s.received.Load()),
+		attribute.Float64("stream.duration_ms", time.Since(s.start).Seconds()*1000),
+	}
+}
+
+func (s *stream) count(ctx context.Context, direction string) {
+	n := &s.sent
+	if direction == "received" {
+		n = &s.received
+	}
+	n.Add(1)
+	if streamMessages != nil {
+		streamMessages.Add(context.WithoutCancel(ctx), 1, s.attrs,
+			metric.WithAttributes(attribute.String("direction", direction)))
+	}
+}
+
+// finish stops the heartbeat and records the connection totals. It is safe
+// on a nil stream.
+func (s *stream) finish(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	close(s.done)
+	s.span.SetAttributes(s.totals()...)
+	ctx = context.WithoutCancel(ctx)
+	if streamActive != nil {
+		streamActive.Add(ctx, -1, s.attrs)
+	}
+	if streamDuration != nil {
+		streamDuration.Record(ctx, time.Since(s.start).Seconds()*1000, s.attrs)
+	}
+}
+
+// StreamSent counts a message sent on the streaming connection of ctx. It
+// does nothing outside a request handled WithStreaming.
+func StreamSent(ctx context.Context) {
+	if s, _ := ctx.Value(streamCtxKey{}).(*stream); s != nil {
+		s.count(ctx, "sent")
+	}
+}
+
+// StreamReceived counts a message received on the streaming connection of
+// ctx.
+func StreamReceived(ctx context.Context) {
+	if s, _ := ctx.Value(streamCtxKey{}).(*stream); s != nil {
+		s.count(ctx, "received")
+	}
+}
@@ -0,0 +1,124 @@
+package eotel
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+// WithStruct flattens the exported fields of a struct into log fields. Keys
+// come from the `eotel:"name"` tag (falling back to the Go field name), `-`
+// skips a field and the `redact` option masks its value:
+//
+//	type Order struct {
+//		ID    string `eotel:"order_id"`
+//		Card  string `eotel:"card,redact"`
+//		Notes string `eotel:"-"`
+//	}
+//
+// Nested structs are flattened with dotted keys.
+func (l *Eotel) WithStruct(v any) *Eotel {
+	if l == nil {
+		return Noop("WithStruct")
+	}
+	cp := l.clone()
+	flattenStruct(reflect.ValueOf(v), "", cp.addField)
+	return cp
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// flattenValue flattens a struct or a string-keyed map into add, nesting
+// with dotted keys.
+func flattenValue(rv reflect.Value, prefix string, add func(key string, value any)) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	switch {
+	case rv.Kind() == reflect.Struct && rv.Type() != timeType:
+		flattenStruct(rv, prefix, add)
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			key := k.String()
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			v := rv.MapIndex(k)
+			for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+				if v.IsNil() {
+					break
+				}
+				v = v.Elem()
+			}
+			switch {
+			case v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface:
+			case (v.Kind() == reflect.Struct && v.Type() != timeType) || v.Kind() == reflect.Map:
+				flattenValue(v, key, add)
+			default:
+				add(key, v.Interface())
+			}
+		}
+	}
+}
+
+func flattenStruct(rv reflect.Value, prefix string, add func(key string, value any)) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(sf.Tag.Get("eotel"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		if opts == "redact" {
+			add(key, redactedValue)
+			continue
+		}
+
+		fv := rv.Field(i)
+		ft := fv.Type()
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			flattenStruct(fv, key, add)
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		add(key, fv.Interface())
+	}
+}
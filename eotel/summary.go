@@ -0,0 +1,53 @@
+package eotel
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+type Summary struct {
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id,omitempty"`
+	Name       string         `json:"name"`
+	DurationMs float64        `json:"duration_ms"`
+	Fields     map[string]any `json:"fields,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Summary returns a serializable snapshot of the logger state, meant to be
+// handed to clients ("include this trace ID when contacting support").
+func (l *Eotel) Summary() Summary {
+	if l == nil {
+		return Summary{}
+	}
+
+	sc := l.activeSpan().SpanContext()
+
+	s := Summary{
+		Name:       l.name,
+		DurationMs: time.Since(l.start).Seconds() * 1000,
+	}
+	if sc.HasTraceID() {
+		s.TraceID = sc.TraceID().String()
+	}
+	if sc.HasSpanID() {
+		s.SpanID = sc.SpanID().String()
+	}
+
+	if fields := l.logFields(); len(fields) > 0 {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		s.Fields = enc.Fields
+	}
+
+	if l.err != nil {
+		s.Error = l.err.Error()
+	}
+
+	l.SpanEvent("eotel.summary", attribute.String("trace_id", s.TraceID))
+	return s
+}
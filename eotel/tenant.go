@@ -0,0 +1,72 @@
+package eotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+const defaultTenantKey = "tenant_id"
+
+// TenantRoute names the dedicated backends of one tenant. Empty fields keep
+// the shared backend.
+type TenantRoute struct {
+	// LokiTenantID is sent as X-Scope-OrgID instead of Config.LokiTenantID.
+	LokiTenantID string `yaml:"loki_tenant_id"`
+	// SentryDSN sends the tenant's errors to its own Sentry project.
+	SentryDSN string `yaml:"sentry_dsn"`
+}
+
+// TenantRouter resolves a tenant's backends when telemetry is emitted;
+// ok=false keeps the shared backends. Route is called for every Loki line
+// and Sentry event of a tenant, so it should be cheap.
+type TenantRouter interface {
+	Route(tenant string) (route TenantRoute, ok bool)
+}
+
+// TenantRoutes is a static TenantRouter keyed by tenant.
+type TenantRoutes map[string]TenantRoute
+
+func (r TenantRoutes) Route(tenant string) (TenantRoute, bool) {
+	route, ok := r[tenant]
+	return route, ok
+}
+
+// tenantOf returns the tenant carried in ctx's baggage under
+// Config.TenantKey.
+func tenantOf(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key := currentConfig().TenantKey
+	if key == "" {
+		key = defaultTenantKey
+	}
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// tenantRoute resolves the route for the tenant in ctx, if any.
+func tenantRoute(ctx context.Context) (TenantRoute, bool) {
+	cfg := currentConfig()
+	var router TenantRouter
+	switch {
+	case cfg.TenantRouter != nil:
+		router = cfg.TenantRouter
+	case len(cfg.TenantRoutes) > 0:
+		router = cfg.TenantRoutes
+	default:
+		return TenantRoute{}, false
+	}
+	tenant := tenantOf(ctx)
+	if tenant == "" {
+		return TenantRoute{}, false
+	}
+	return router.Route(tenant)
+}
+
+// lokiOrgID is the X-Scope-OrgID for entries emitted from ctx; empty keeps
+// Config.LokiTenantID.
+func lokiOrgID(ctx context.Context) string {
+	route, _ := tenantRoute(ctx)
+	return route.LokiTenantID
+}
@@ -0,0 +1,129 @@
+package eotel
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Timer times a section of code started with Eotel.Start. Only the first
+// Stop or StopWithError counts.
+type Timer interface {
+	Stop()
+	// StopWithError stops the timer and attaches err to the section; a nil
+	// err is the same as Stop.
+	StopWithError(err error)
+}
+
+type TimerOption func(*timerConfig)
+
+type timerConfig struct {
+	span   bool
+	events bool
+}
+
+// TimerSpan makes the timer cover the section with a child span named after
+// it, ended by Stop, instead of a single event.
+func TimerSpan() TimerOption {
+	return func(c *timerConfig) {
+		c.span = true
+	}
+}
+
+// TimerEvents records "<name>.start" when the timer starts and "<name>.end"
+// when it stops, so the section shows up on the span's timeline.
+func TimerEvents() TimerOption {
+	return func(c *timerConfig) {
+		c.events = true
+	}
+}
+
+var (
+	timerMetricsOnce sync.Once
+	timerDuration    metric.Float64Histogram
+)
+
+type eotelTimer struct {
+	name    string
+	logger  *Eotel
+	start   time.Time
+	child   *Eotel
+	events  bool
+	stopped atomic.Bool
+}
+
+// Start times a section named name. By default Stop adds one span event
+// carrying custom.duration_ms; see TimerSpan and TimerEvents. Every timer
+// also records eotel.timer.duration by timer.name and timer.outcome.
+func (l *Eotel) Start(name string, opts ...TimerOption) Timer {
+	if l == nil {
+		l = Noop(name)
+	}
+	var cfg timerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	t := &eotelTimer{name: name, logger: l, start: time.Now()}
+	switch {
+	case cfg.span:
+		t.child = l.Child(name)
+	case cfg.events:
+		t.events = true
+		l.SpanEvent(name + ".start")
+	}
+	return t
+}
+
+func (t *eotelTimer) Stop() {
+	t.StopWithError(nil)
+}
+
+func (t *eotelTimer) StopWithError(err error) {
+	if !t.stopped.CompareAndSwap(false, true) {
+		return
+	}
+	duration := time.Since(t.start).Seconds() * 1000
+
+	switch {
+	case t.child != nil:
+		t.child.err = err
+		t.child.End()
+	default:
+		attrs := []attribute.KeyValue{attribute.Float64("custom.duration_ms", duration)}
+		if err != nil {
+			attrs = append(attrs, attribute.String("error", err.Error()))
+			t.logger.activeSpan().RecordError(err, trace.WithAttributes(attribute.String("timer.name", t.name)))
+		}
+		name := t.name
+		if t.events {
+			name += ".end"
+		}
+		t.logger.SpanEvent(name, attrs...)
+	}
+
+	timerMetricsOnce.Do(func() {
+		timerDuration, _ = getMeter().Float64Histogram("eotel.timer.duration",
+			metric.WithUnit("ms"),
+			metric.WithDescription("Duration of sections timed with Start and Stop."))
+	})
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	// The section's own span, if any, is the better exemplar.
+	ctx := t.logger.metricCtx()
+	if t.child != nil {
+		ctx = t.child.metricCtx()
+	}
+	if timerDuration != nil {
+		timerDuration.Record(ctx, duration,
+			metric.WithAttributes(
+				attribute.String("timer.name", t.name),
+				attribute.String("timer.outcome", outcome),
+			))
+	}
+}
@@ -0,0 +1,65 @@
+package eotel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceFileSuffix is appended to a data file's path to name its trace
+// sidecar.
+const TraceFileSuffix = ".trace"
+
+// TraceMetadata returns the trace context of ctx (traceparent, tracestate,
+// baggage) as a string map, to store as object metadata (S3, GCS) or in a
+// job manifest between the stages of a batch pipeline.
+func TraceMetadata(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// ContextFromMetadata returns ctx carrying the trace context stored by
+// TraceMetadata; spans started from it join the upstream stage's trace.
+// Keys are matched case-insensitively, as object stores lower-case them.
+func ContextFromMetadata(ctx context.Context, md map[string]string) context.Context {
+	carrier := make(propagation.MapCarrier, len(md))
+	for k, v := range md {
+		carrier.Set(strings.ToLower(k), v)
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// WriteTraceFile writes the trace context of ctx next to the file at path,
+// as path+TraceFileSuffix.
+func WriteTraceFile(ctx context.Context, path string) error {
+	data, err := json.Marshal(TraceMetadata(ctx))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+TraceFileSuffix, data, 0o644)
+}
+
+// ReadTraceFile returns ctx carrying the trace context written by
+// WriteTraceFile for path. A missing sidecar is not an error: ctx is
+// returned unchanged.
+func ReadTraceFile(ctx context.Context, path string) (context.Context, error) {
+	data, err := os.ReadFile(path + TraceFileSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return ctx, nil
+	}
+	if err != nil {
+		return ctx, err
+	}
+	var md map[string]string
+	if err := json.Unmarshal(data, &md); err != nil {
+		return ctx, fmt.Errorf("trace file %s: %w", path+TraceFileSuffix, err)
+	}
+	return ContextFromMetadata(ctx, md), nil
+}
@@ -0,0 +1,135 @@
+package eotel
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceStateVendor is eotel's member key in the W3C tracestate header. Its
+// value carries key:value pairs separated by ';', e.g.
+// "eotel=tenant:acme;tier:gold".
+const traceStateVendor = "eotel"
+
+type traceStateCtxKey struct{}
+
+// WithTraceState returns a context whose outgoing requests carry key=value in
+// eotel's tracestate entry, alongside the values received from upstream.
+// Keys and values must not contain ',', '=', ';', ':' or spaces; invalid
+// pairs are ignored.
+func WithTraceState(ctx context.Context, key, value string) context.Context {
+	if !validTraceStateToken(key) || !validTraceStateToken(value) {
+		return ctx
+	}
+	vals := map[string]string{}
+	for k, v := range traceStateOverlay(ctx) {
+		vals[k] = v
+	}
+	vals[key] = value
+	return context.WithValue(ctx, traceStateCtxKey{}, vals)
+}
+
+// TraceStateValue returns the value of key in eotel's tracestate entry.
+func TraceStateValue(ctx context.Context, key string) string {
+	return TraceStateValues(ctx)[key]
+}
+
+// TraceStateValues returns every pair of eotel's tracestate entry: those
+// propagated with the span context merged with the ones set locally with
+// WithTraceState.
+func TraceStateValues(ctx context.Context) map[string]string {
+	vals := decodeTraceState(trace.SpanContextFromContext(ctx).TraceState().Get(traceStateVendor))
+	for k, v := range traceStateOverlay(ctx) {
+		if vals == nil {
+			vals = map[string]string{}
+		}
+		vals[k] = v
+	}
+	return vals
+}
+
+func traceStateOverlay(ctx context.Context) map[string]string {
+	vals, _ := ctx.Value(traceStateCtxKey{}).(map[string]string)
+	return vals
+}
+
+func validTraceStateToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c > '~' || c == ',' || c == '=' || c == ';' || c == ':' {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeTraceState(vals map[string]string) string {
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + ":" + vals[k]
+	}
+	return strings.Join(pairs, ";")
+}
+
+func decodeTraceState(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	vals := map[string]string{}
+	for _, pair := range strings.Split(s, ";") {
+		if k, v, ok := strings.Cut(pair, ":"); ok && k != "" {
+			vals[k] = v
+		}
+	}
+	return vals
+}
+
+// traceContext is the W3C trace context propagator with eotel's tracestate
+// entry merged in on inject.
+type traceContext struct {
+	propagation.TraceContext
+}
+
+func (p traceContext) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	if overlay := traceStateOverlay(ctx); len(overlay) > 0 {
+		sc := trace.SpanContextFromContext(ctx)
+		if ts, err := sc.TraceState().Insert(traceStateVendor, encodeTraceState(TraceStateValues(ctx))); err == nil {
+			ctx = trace.ContextWithSpanContext(ctx, sc.WithTraceState(ts))
+		}
+	}
+	p.TraceContext.Inject(ctx, carrier)
+}
+
+// traceStateProcessor sets eotel's tracestate pairs as eotel.tracestate.<key>
+// attributes on spans continuing a remote trace, so receiving services see
+// them without extra code.
+type traceStateProcessor struct{}
+
+func (traceStateProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	if !s.Parent().IsRemote() {
+		return
+	}
+	vals := decodeTraceState(s.SpanContext().TraceState().Get(traceStateVendor))
+	for k, v := range vals {
+		s.SetAttributes(attribute.String("eotel.tracestate."+k, v))
+	}
+}
+
+func (traceStateProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (traceStateProcessor) Shutdown(context.Context) error { return nil }
+
+func (traceStateProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,30 @@
+package eotel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const defaultMaxValueBytes = 16 << 10
+
+func maxValueBytes() int {
+	cfg := currentConfig()
+	switch {
+	case cfg.MaxValueBytes < 0:
+		return 0
+	case cfg.MaxValueBytes == 0:
+		return defaultMaxValueBytes
+	}
+	return cfg.MaxValueBytes
+}
+
+// truncateValue cuts values over the configured limit and appends a short
+// hash of the full value, so identical large payloads stay correlatable.
+func truncateValue(s string) (string, bool) {
+	limit := maxValueBytes()
+	if limit == 0 || len(s) <= limit {
+		return s, false
+	}
+	sum := sha256.Sum256([]byte(s))
+	return s[:limit] + "...sha256:" + hex.EncodeToString(sum[:8]), true
+}
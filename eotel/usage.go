@@ -0,0 +1,184 @@
+package eotel
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	usageOTLPTraces  = "otlp.traces"
+	usageOTLPMetrics = "otlp.metrics"
+	usageOTLPLogs    = "otlp.logs"
+	usageLoki        = "loki"
+	usageSentry      = "sentry"
+)
+
+// UsageReport is the number of bytes sent to each backend since midnight
+// (UTC).
+type UsageReport struct {
+	Day   string           `json:"day"`
+	Bytes map[string]int64 `json:"bytes"`
+}
+
+// usageTracker accounts for the bytes eotel puts on the wire per backend so
+// observability spend can be attributed to the service (and the
+// Config.UsageLabels team labels) emitting it.
+type usageTracker struct {
+	mu    sync.Mutex
+	day   string
+	bytes map[string]int64
+
+	once    sync.Once
+	counter metric.Int64Counter
+}
+
+var usage = &usageTracker{bytes: map[string]int64{}}
+
+func usageDay(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func recordUsage(backend string, n int64) {
+	cfg := currentConfig()
+	if !cfg.EnableUsageReporting || n <= 0 {
+		return
+	}
+	u := usage
+	u.mu.Lock()
+	if day := usageDay(time.Now()); day != u.day {
+		u.day = day
+		u.bytes = map[string]int64{}
+	}
+	u.bytes[backend] += n
+	u.mu.Unlock()
+
+	u.once.Do(func() {
+		u.counter, _ = getMeter().Int64Counter("eotel.export.bytes",
+			metric.WithUnit("By"),
+			metric.WithDescription("Bytes sent to telemetry backends."))
+	})
+	if u.counter == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("backend", backend),
+		attribute.String("service", cfg.ServiceName),
+	}
+	for k, v := range cfg.UsageLabels {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	u.counter.Add(context.Background(), n, metric.WithAttributes(attrs...))
+}
+
+// Usage returns the bytes sent per backend today. It is empty unless
+// Config.EnableUsageReporting is set.
+func Usage() UsageReport {
+	u := usage
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	r := UsageReport{Day: usageDay(time.Now()), Bytes: map[string]int64{}}
+	if u.day == r.Day {
+		for k, v := range u.bytes {
+			r.Bytes[k] = v
+		}
+	}
+	return r
+}
+
+// startUsageReport logs the running daily totals every interval until the
+// returned stop function is called.
+func startUsageReport(interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r := Usage()
+				fields := []zap.Field{zap.String("day", r.Day)}
+				for k, v := range r.Bytes {
+					fields = append(fields, zap.Int64("bytes."+k, v))
+				}
+				for k, v := range currentConfig().UsageLabels {
+					fields = append(fields, zap.String(k, v))
+				}
+				getLogger().Info("telemetry usage", fields...)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// usageTransport counts request body bytes for backend.
+type usageTransport struct {
+	base    http.RoundTripper
+	backend string
+}
+
+func (t usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body = &countingBody{ReadCloser: req.Body, backend: t.backend}
+	}
+	return t.base.RoundTrip(req)
+}
+
+type countingBody struct {
+	io.ReadCloser
+	backend string
+	n       int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	recordUsage(b.backend, b.n)
+	b.n = 0
+	return b.ReadCloser.Close()
+}
+
+// usageHTTPClient is the HTTP client given to OTLP/HTTP exporters when usage
+// reporting is on. It carries the TLS settings itself because a custom
+// client overrides the exporter's own TLS option.
+func usageHTTPClient(tlsCfg *tls.Config, backend string) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsCfg != nil {
+		base.TLSClientConfig = tlsCfg
+	}
+	return &http.Client{Transport: usageTransport{base: base, backend: backend}}
+}
+
+// usageInterceptor counts the serialized size of OTLP/gRPC export requests.
+func usageInterceptor(backend string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if m, ok := req.(proto.Message); ok {
+			recordUsage(backend, int64(proto.Size(m)))
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func sentryHTTPClient(cfg Config) *http.Client {
+	c := &http.Client{Timeout: timeoutOr(cfg.ExporterTimeouts.Sentry, defaultSentryTimeout)}
+	if cfg.EnableUsageReporting {
+		c.Transport = usageTransport{base: http.DefaultTransport, backend: usageSentry}
+	}
+	return c
+}
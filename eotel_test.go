@@ -0,0 +1,74 @@
+package eotel_test
+
+import (
+	"context"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+
+	eotel "github.com/nicedev97/eotel-v2"
+)
+
+// benchInit sets eotel up with real SDK providers that export nowhere, so the
+// benchmarks measure eotel's own work rather than an exporter's.
+func benchInit(b *testing.B) {
+	b.Helper()
+	tp := sdktrace.NewTracerProvider()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+	shutdown, err := eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:    "bench",
+		JobName:        "bench",
+		TracerProvider: tp,
+		MeterProvider:  mp,
+		Logger:         zap.NewNop(),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		ctx := context.Background()
+		_ = shutdown(ctx)
+		_ = tp.Shutdown(ctx)
+		_ = mp.Shutdown(ctx)
+	})
+	b.ReportAllocs()
+}
+
+func BenchmarkNew(b *testing.B) {
+	benchInit(b)
+	ctx := context.Background()
+	for b.Loop() {
+		eotel.New(ctx, "bench")
+	}
+}
+
+func BenchmarkInfo(b *testing.B) {
+	benchInit(b)
+	log := eotel.New(context.Background(), "bench")
+	for b.Loop() {
+		log.Info("message")
+	}
+}
+
+func BenchmarkInfoWithFields(b *testing.B) {
+	benchInit(b)
+	log := eotel.New(context.Background(), "bench").
+		WithField("user_id", 42).
+		WithField("tenant", "acme").
+		WithField("route", "/orders/:id")
+	for b.Loop() {
+		log.Info("message")
+	}
+}
+
+func BenchmarkChildEnd(b *testing.B) {
+	benchInit(b)
+	log := eotel.New(context.Background(), "bench").WithField("user_id", 42)
+	for b.Loop() {
+		child := log.Child("step").WithField("attempt", 1)
+		child.Info("message")
+		child.End()
+	}
+}
@@ -0,0 +1,40 @@
+// Package eoteldb builds OTel database semantic-convention attributes, so
+// hand instrumented clients use the same keys as eotelsql.
+package eoteldb
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/nicedev97/eotel-v2/eotelsql"
+)
+
+// Attrs returns the attributes of a call to the database system (postgresql,
+// mysql, redis, mongodb, ...) running stmt. The statement is sanitized
+// before it is attached, and its first word becomes the operation name.
+func Attrs(system, stmt string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 3)
+	if system != "" {
+		attrs = append(attrs, attribute.String("db.system.name", system))
+	}
+	if op := Operation(stmt); op != "" {
+		attrs = append(attrs, attribute.String("db.operation.name", op))
+	}
+	if stmt != "" {
+		attrs = append(attrs, attribute.String("db.query.text", eotelsql.SanitizeSQL(stmt)))
+	}
+	return attrs
+}
+
+// Operation returns the upper-cased first word of stmt.
+func Operation(stmt string) string {
+	op, _, _ := strings.Cut(strings.TrimSpace(stmt), " ")
+	return strings.ToUpper(op)
+}
+
+// Collection returns the db.collection.name attribute for a table or
+// collection.
+func Collection(name string) attribute.KeyValue {
+	return attribute.String("db.collection.name", name)
+}
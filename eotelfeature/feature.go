@@ -0,0 +1,80 @@
+// Package eotelfeature provides an OpenFeature hook recording flag
+// evaluations on eotel traces:
+//
+//	openfeature.AddHooks(eotelfeature.NewHook())
+//
+// Each evaluation becomes a feature_flag.evaluation event on the current span,
+// and the resolved variant is set as feature_flag.<key> on the request span
+// started by eotel's HTTP middleware, so traces can be split by flag cohort.
+package eotelfeature
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelfeature"
+
+type Option func(*Hook)
+
+// WithValues records the evaluated value when the provider returns no
+// variant. Off by default since flag values may carry user data.
+func WithValues() Option {
+	return func(h *Hook) {
+		h.values = true
+	}
+}
+
+// Hook is an openfeature.Hook; create it with NewHook.
+type Hook struct {
+	openfeature.UnimplementedHook
+	values bool
+}
+
+func NewHook(opts ...Option) *Hook {
+	h := &Hook{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Hook) Finally(ctx context.Context, hc openfeature.HookContext, details openfeature.InterfaceEvaluationDetails, _ openfeature.HookHints) {
+	variant := details.Variant
+	if variant == "" && h.values && details.ErrorCode == "" {
+		variant = fmt.Sprint(details.Value)
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("feature_flag.key", hc.FlagKey()),
+		attribute.String("feature_flag.provider.name", hc.ProviderMetadata().Name),
+	}
+	if variant != "" {
+		attrs = append(attrs, attribute.String("feature_flag.result.variant", variant))
+	}
+	if details.Reason != "" {
+		attrs = append(attrs, attribute.String("feature_flag.result.reason", string(details.Reason)))
+	}
+	if details.ErrorCode != "" {
+		attrs = append(attrs, attribute.String("error.type", string(details.ErrorCode)))
+	}
+	if domain := hc.ClientMetadata().Domain(); domain != "" {
+		attrs = append(attrs, attribute.String("feature_flag.set.id", domain))
+	}
+	trace.SpanFromContext(ctx).AddEvent("feature_flag.evaluation", trace.WithAttributes(attrs...))
+
+	if variant == "" {
+		return
+	}
+	// The middleware's logger carries the request span; child spans in ctx
+	// only get the event.
+	if span := eotel.FromContext(ctx, ScopeName).Span(); span != nil {
+		span.SetAttributes(attribute.String("feature_flag."+hc.FlagKey(), variant))
+	}
+}
@@ -0,0 +1,155 @@
+// Package eotelgin mirrors the otelgin API on top of eotel so that services
+// migrating from otelgin only need to change the import path.
+package eotelgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelgin"
+
+type Filter func(*http.Request) bool
+
+type GinFilter func(*gin.Context) bool
+
+type SpanNameFormatter func(r *http.Request) string
+
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (f optionFunc) apply(c *config) { f(c) }
+
+type config struct {
+	TracerProvider    trace.TracerProvider
+	Propagators       propagation.TextMapPropagator
+	Filters           []Filter
+	GinFilters        []GinFilter
+	SpanNameFormatter SpanNameFormatter
+}
+
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return optionFunc(func(c *config) {
+		if provider != nil {
+			c.TracerProvider = provider
+		}
+	})
+}
+
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(c *config) {
+		if propagators != nil {
+			c.Propagators = propagators
+		}
+	})
+}
+
+// WithFilter adds filters; a request is traced only if every filter returns true.
+func WithFilter(f ...Filter) Option {
+	return optionFunc(func(c *config) {
+		c.Filters = append(c.Filters, f...)
+	})
+}
+
+func WithGinFilter(f ...GinFilter) Option {
+	return optionFunc(func(c *config) {
+		c.GinFilters = append(c.GinFilters, f...)
+	})
+}
+
+func WithSpanNameFormatter(f func(r *http.Request) string) Option {
+	return optionFunc(func(c *config) {
+		c.SpanNameFormatter = f
+	})
+}
+
+func Middleware(service string, opts ...Option) gin.HandlerFunc {
+	cfg := config{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = eotel.TracerProvider()
+	}
+	if cfg.Propagators == nil {
+		cfg.Propagators = otel.GetTextMapPropagator()
+	}
+	tracer := cfg.TracerProvider.Tracer(ScopeName)
+
+	return func(c *gin.Context) {
+		for _, f := range cfg.Filters {
+			if !f(c.Request) {
+				c.Next()
+				return
+			}
+		}
+		for _, f := range cfg.GinFilters {
+			if !f(c) {
+				c.Next()
+				return
+			}
+		}
+
+		ctx := cfg.Propagators.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		spanName := c.FullPath()
+		if cfg.SpanNameFormatter != nil {
+			spanName = cfg.SpanNameFormatter(c.Request)
+		} else if spanName == "" {
+			spanName = "HTTP " + c.Request.Method + " route not found"
+		}
+
+		ctx, span := tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("server.name", service),
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		// Deferred after span.End so a panic is recovered, and the span
+		// given the response status, while the span is still recording.
+		defer func() {
+			if rec := recover(); rec != nil {
+				err := eotel.RecoverRouter(RouterContext(c), rec)
+				span.SetAttributes(attribute.Int("http.response.status_code", c.Writer.Status()))
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}()
+
+		logger := eotel.Safe(eotel.New(ctx, service)).
+			TraceName(spanName).
+			WithHTTPRequest(c.Request, c.ClientIP())
+		defer logger.FlushAggregates()
+
+		c.Request = c.Request.WithContext(eotel.Inject(ctx, logger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if len(c.Errors) > 0 {
+			span.SetAttributes(attribute.String("gin.errors", c.Errors.String()))
+		}
+
+		logger.Info("request completed")
+	}
+}
@@ -0,0 +1,74 @@
+package eotelgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+// RouterContext adapts c for eotel.NewRouterMiddleware and
+// eotel.RecoverRouter.
+func RouterContext(c *gin.Context) eotel.RouterContext {
+	return router{c}
+}
+
+// GinContext returns the *gin.Context behind rc, nil when rc was not made
+// by RouterContext.
+func GinContext(rc eotel.RouterContext) *gin.Context {
+	if r, ok := rc.(router); ok {
+		return r.c
+	}
+	return nil
+}
+
+// router adapts *gin.Context to eotel.RouterContext.
+type router struct {
+	c *gin.Context
+}
+
+func (g router) Request() *http.Request      { return g.c.Request }
+func (g router) SetRequest(r *http.Request)  { g.c.Request = r }
+func (g router) Route() string               { return g.c.FullPath() }
+func (g router) ClientIP() string            { return g.c.ClientIP() }
+func (g router) Writer() http.ResponseWriter { return g.c.Writer }
+func (g router) Status() int                 { return g.c.Writer.Status() }
+func (g router) Size() int                   { return g.c.Writer.Size() }
+func (g router) Next()                       { g.c.Next() }
+func (g router) Abort()                      { g.c.Abort() }
+func (g router) TapWrites(fn func(p []byte)) { g.c.Writer = &tapGinWriter{g.c.Writer, fn} }
+func (g router) OnFlush(fn func())           { g.c.Writer = &flushGinWriter{g.c.Writer, fn} }
+
+func (g router) Errors() []eotel.RouterError {
+	errs := make([]eotel.RouterError, 0, len(g.c.Errors))
+	for _, e := range g.c.Errors {
+		errs = append(errs, eotel.RouterError{Err: e.Err, Type: int64(e.Type)})
+	}
+	return errs
+}
+
+type tapGinWriter struct {
+	gin.ResponseWriter
+	tap func(p []byte)
+}
+
+func (w *tapGinWriter) Write(b []byte) (int, error) {
+	w.tap(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *tapGinWriter) WriteString(s string) (int, error) {
+	w.tap([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+type flushGinWriter struct {
+	gin.ResponseWriter
+	flushed func()
+}
+
+func (w *flushGinWriter) Flush() {
+	w.ResponseWriter.Flush()
+	w.flushed()
+}
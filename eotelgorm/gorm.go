@@ -0,0 +1,181 @@
+// Package eotelgorm is a GORM plugin emitting eotel spans, metrics and slow
+// query logs. Queries run with db.WithContext(ctx) nest under the span of the
+// logger injected into ctx, typically the HTTP request span.
+package eotelgorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+	"github.com/nicedev97/eotel-v2/eotelsql"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelgorm"
+
+const (
+	spanKey  = "eotel:span"
+	startKey = "eotel:start"
+)
+
+type Option func(*plugin)
+
+// WithSlowQueryThreshold logs operations taking longer than d as warnings.
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(p *plugin) {
+		p.slowThreshold = d
+	}
+}
+
+// WithDBSystem overrides db.system.name, which defaults to the dialector name.
+func WithDBSystem(system string) Option {
+	return func(p *plugin) {
+		p.system = system
+	}
+}
+
+// WithTracerProvider sets the provider operation spans are started from. It
+// defaults to eotel.TracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(p *plugin) {
+		p.tracerProvider = tp
+	}
+}
+
+type plugin struct {
+	system         string
+	slowThreshold  time.Duration
+	tracerProvider trace.TracerProvider
+
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// New returns the plugin, to be registered with db.Use(eotelgorm.New()).
+func New(opts ...Option) gorm.Plugin {
+	p := &plugin{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *plugin) Name() string { return "eotel" }
+
+func (p *plugin) Initialize(db *gorm.DB) error {
+	if p.system == "" && db.Dialector != nil {
+		p.system = db.Dialector.Name()
+	}
+	if p.tracerProvider == nil {
+		p.tracerProvider = eotel.TracerProvider()
+	}
+	p.tracer = p.tracerProvider.Tracer(ScopeName)
+
+	var err error
+	if p.duration, err = eotel.Float64Histogram("eotel.db.query.duration", "ms", "Duration of database queries."); err != nil {
+		p.duration = noop.Float64Histogram{}
+	}
+	if p.errors, err = eotel.Int64Counter("eotel.db.query.errors", "{error}", "Number of failed database queries."); err != nil {
+		p.errors = noop.Int64Counter{}
+	}
+
+	cb := db.Callback()
+	hooks := []struct {
+		op     string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, h := range hooks {
+		if err := h.before("eotel:before_"+h.op, p.before(h.op)); err != nil {
+			return err
+		}
+		if err := h.after("eotel:after_"+h.op, p.after(h.op)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) before(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, span := p.tracer.Start(ctx, "gorm."+op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(p.attrs(db, op)...),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+		db.InstanceSet(startKey, time.Now())
+	}
+}
+
+func (p *plugin) after(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span := v.(trace.Span)
+		defer span.End()
+
+		var elapsed time.Duration
+		if v, ok := db.InstanceGet(startKey); ok {
+			elapsed = time.Since(v.(time.Time))
+		}
+
+		ctx := db.Statement.Context
+		statement := eotelsql.SanitizeSQL(db.Statement.SQL.String())
+		attrs := p.attrs(db, op)
+		span.SetAttributes(
+			attribute.String("db.query.text", statement),
+			attribute.Int64("db.response.returned_rows", db.RowsAffected),
+		)
+
+		mctx := context.WithoutCancel(ctx)
+		p.duration.Record(mctx, elapsed.Seconds()*1000, metric.WithAttributes(attrs...))
+
+		if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			p.errors.Add(mctx, 1, metric.WithAttributes(attrs...))
+		}
+
+		if p.slowThreshold > 0 && elapsed >= p.slowThreshold {
+			eotel.FromContext(ctx, "gorm").
+				WithField("db.query.text", statement).
+				WithField("db.collection.name", db.Statement.Table).
+				WithDuration("duration", elapsed).
+				Warn("slow query")
+		}
+	}
+}
+
+func (p *plugin) attrs(db *gorm.DB, op string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("db.operation.name", op)}
+	if p.system != "" {
+		attrs = append(attrs, attribute.String("db.system.name", p.system))
+	}
+	if db.Statement.Table != "" {
+		attrs = append(attrs, attribute.String("db.collection.name", db.Statement.Table))
+	}
+	return attrs
+}
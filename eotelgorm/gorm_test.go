@@ -0,0 +1,53 @@
+package eotelgorm_test
+
+import (
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/nicedev97/eotel-v2/eotelgorm"
+)
+
+// dryDialector registers the default callbacks and nothing else; the tests
+// run in DryRun mode so no connection is needed.
+type dryDialector struct{}
+
+func (dryDialector) Name() string { return "dry" }
+
+func (dryDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+
+func (dryDialector) Migrator(*gorm.DB) gorm.Migrator                { return nil }
+func (dryDialector) DataTypeOf(*schema.Field) string                { return "" }
+func (dryDialector) DefaultValueOf(*schema.Field) clause.Expression { return clause.Expr{} }
+func (dryDialector) BindVarTo(w clause.Writer, _ *gorm.Statement, _ any) {
+	_ = w.WriteByte('?')
+}
+func (dryDialector) QuoteTo(w clause.Writer, s string)   { _, _ = w.WriteString(s) }
+func (dryDialector) Explain(sql string, _ ...any) string { return sql }
+
+func TestWithTracerProvider(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	db, err := gorm.Open(dryDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(eotelgorm.New(eotelgorm.WithTracerProvider(tp))); err != nil {
+		t.Fatal(err)
+	}
+	db.Exec("UPDATE users SET name = ? WHERE id = ?", "bob", 7)
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "gorm.raw" {
+		t.Fatalf("spans = %v, want one gorm.raw span", spans)
+	}
+}
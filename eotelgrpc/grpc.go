@@ -0,0 +1,175 @@
+// Package eotelgrpc instruments gRPC servers and clients: each RPC gets a
+// span continuing the caller's trace from metadata, rpc_* request and
+// duration metrics, and on the server an eotel logger in the handler
+// context.
+package eotelgrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelgrpc"
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type rpcMetrics struct {
+	once     sync.Once
+	requests metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+var (
+	rpcServerMetrics rpcMetrics
+	rpcClientMetrics rpcMetrics
+)
+
+func (m *rpcMetrics) record(ctx context.Context, kind, method string, err error, start time.Time) {
+	m.once.Do(func() {
+		meter := eotel.MeterProvider().Meter(ScopeName)
+		m.requests, _ = meter.Int64Counter("rpc_" + kind + "_requests_total")
+		m.duration, _ = meter.Float64Histogram("rpc_"+kind+"_duration_ms", metric.WithUnit("ms"))
+	})
+	attrs := metric.WithAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.grpc.status_code", status.Code(err).String()),
+	)
+	ctx = context.WithoutCancel(ctx)
+	m.requests.Add(ctx, 1, attrs)
+	m.duration.Record(ctx, time.Since(start).Seconds()*1000, attrs)
+}
+
+func finishRPCSpan(span trace.Span, err error) {
+	s := status.Convert(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(s.Code())))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, s.Message())
+	}
+	span.End()
+}
+
+// startServerRPC extracts the caller's trace context from incoming metadata,
+// starts the server span and injects an Eotel logger into the handler context.
+func startServerRPC(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md.Copy()))
+	ctx, span := eotel.TracerProvider().Tracer(ScopeName).Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+		),
+	)
+	ctx = eotel.WithRequestScope(ctx)
+	logger := eotel.Safe(eotel.New(ctx, method)).WithField("rpc.method", method)
+	return eotel.Inject(ctx, logger), span
+}
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, span := startServerRPC(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		eotel.FromContext(ctx, info.FullMethod).FlushAggregates()
+		finishRPCSpan(span, err)
+		rpcServerMetrics.record(ctx, "server", info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, span := startServerRPC(ss.Context(), info.FullMethod)
+		err := handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
+		eotel.FromContext(ctx, info.FullMethod).FlushAggregates()
+		finishRPCSpan(span, err)
+		rpcServerMetrics.record(ctx, "server", info.FullMethod, err, start)
+		return err
+	}
+}
+
+func startClientRPC(ctx context.Context, method string) (context.Context, trace.Span) {
+	ctx, span := eotel.TracerProvider().Tracer(ScopeName).Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+		),
+	)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		ctx, span := startClientRPC(ctx, method)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		finishRPCSpan(span, err)
+		rpcClientMetrics.record(ctx, "client", method, err, start)
+		return err
+	}
+}
+
+// StreamClientInterceptor ends the client span once the stream is
+// established; per-message tracing is left to the caller.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		ctx, span := startClientRPC(ctx, method)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		finishRPCSpan(span, err)
+		rpcClientMetrics.record(ctx, "client", method, err, start)
+		return cs, err
+	}
+}
@@ -0,0 +1,292 @@
+package eotelharness
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	tracesFile  = "/eotel/traces.json"
+	metricsFile = "/eotel/metrics.json"
+)
+
+const collectorConfig = `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 0.0.0.0:4318
+exporters:
+  file/traces:
+    path: ` + tracesFile + `
+    flush_interval: 100ms
+  file/metrics:
+    path: ` + metricsFile + `
+    flush_interval: 100ms
+  debug: {}
+extensions:
+  health_check:
+    endpoint: 0.0.0.0:13133
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [file/traces]
+    metrics:
+      receivers: [otlp]
+      exporters: [file/metrics]
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`
+
+func startCollector(ctx context.Context, image string) (testcontainers.Container, string, error) {
+	empty := func(path string) testcontainers.ContainerFile {
+		return testcontainers.ContainerFile{Reader: strings.NewReader(""), ContainerFilePath: path, FileMode: 0o666}
+	}
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        image,
+			ExposedPorts: []string{"4317/tcp", "4318/tcp", "13133/tcp"},
+			Cmd:          []string{"--config=/etc/otelcol-contrib/config.yaml"},
+			Files: []testcontainers.ContainerFile{
+				{Reader: strings.NewReader(collectorConfig), ContainerFilePath: "/etc/otelcol-contrib/config.yaml", FileMode: 0o644},
+				empty(tracesFile),
+				empty(metricsFile),
+			},
+			WaitingFor: wait.ForHTTP("/").WithPort("13133/tcp").WithStartupTimeout(2 * time.Minute),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return c, "", fmt.Errorf("collector container: %w", err)
+	}
+	host, err := c.Host(ctx)
+	if err != nil {
+		return c, "", fmt.Errorf("collector container: %w", err)
+	}
+	port, err := c.MappedPort(ctx, "4317/tcp")
+	if err != nil {
+		return c, "", fmt.Errorf("collector container: %w", err)
+	}
+	return c, fmt.Sprintf("%s:%s", host, port.Port()), nil
+}
+
+// Span is a span as received by the collector.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Kind         int
+	Service      string
+	Attributes   map[string]any
+	StatusCode   int
+	Events       []string
+}
+
+// Spans returns every span the collector has written so far.
+func (h *Harness) Spans(ctx context.Context) ([]Span, error) {
+	var spans []Span
+	err := h.readCollectorFile(ctx, tracesFile, func(line []byte) error {
+		var doc otlpTraces
+		if err := json.Unmarshal(line, &doc); err != nil {
+			return err
+		}
+		for _, rs := range doc.ResourceSpans {
+			service, _ := otlpAttrs(rs.Resource.Attributes)["service.name"].(string)
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					span := Span{
+						TraceID:      s.TraceID,
+						SpanID:       s.SpanID,
+						ParentSpanID: s.ParentSpanID,
+						Name:         s.Name,
+						Kind:         s.Kind,
+						Service:      service,
+						Attributes:   otlpAttrs(s.Attributes),
+						StatusCode:   s.Status.Code,
+					}
+					for _, e := range s.Events {
+						span.Events = append(span.Events, e.Name)
+					}
+					spans = append(spans, span)
+				}
+			}
+		}
+		return nil
+	})
+	return spans, err
+}
+
+// MetricNames returns the names of the metrics the collector has received.
+func (h *Harness) MetricNames(ctx context.Context) ([]string, error) {
+	seen := map[string]bool{}
+	var names []string
+	err := h.readCollectorFile(ctx, metricsFile, func(line []byte) error {
+		var doc otlpMetrics
+		if err := json.Unmarshal(line, &doc); err != nil {
+			return err
+		}
+		for _, rm := range doc.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					if !seen[m.Name] {
+						seen[m.Name] = true
+						names = append(names, m.Name)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	return names, err
+}
+
+// WaitForSpan polls until a span named name arrives.
+func (h *Harness) WaitForSpan(ctx context.Context, name string) (Span, error) {
+	var found Span
+	err := poll(ctx, fmt.Sprintf("span %q", name), func() (bool, error) {
+		spans, err := h.Spans(ctx)
+		for _, s := range spans {
+			if s.Name == name {
+				found = s
+				return true, nil
+			}
+		}
+		return false, err
+	})
+	return found, err
+}
+
+// WaitForMetric polls until a metric named name arrives.
+func (h *Harness) WaitForMetric(ctx context.Context, name string) error {
+	return poll(ctx, fmt.Sprintf("metric %q", name), func() (bool, error) {
+		names, err := h.MetricNames(ctx)
+		for _, n := range names {
+			if n == name {
+				return true, nil
+			}
+		}
+		return false, err
+	})
+}
+
+// RequireSpan is WaitForSpan bounded by DefaultWait, failing t on timeout.
+func (h *Harness) RequireSpan(t testing.TB, name string) Span {
+	t.Helper()
+	ctx, cancel := waitCtx()
+	defer cancel()
+	s, err := h.WaitForSpan(ctx, name)
+	require(t, err)
+	return s
+}
+
+// RequireMetric is WaitForMetric bounded by DefaultWait.
+func (h *Harness) RequireMetric(t testing.TB, name string) {
+	t.Helper()
+	ctx, cancel := waitCtx()
+	defer cancel()
+	require(t, h.WaitForMetric(ctx, name))
+}
+
+func (h *Harness) readCollectorFile(ctx context.Context, path string, fn func(line []byte) error) error {
+	rc, err := h.collector.CopyFileFromContainer(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	r := bufio.NewReader(rc)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if perr := fn(line); perr != nil {
+				return perr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// OTLP/JSON, as written by the collector's file exporter.
+type otlpTraces struct {
+	ResourceSpans []struct {
+		Resource   otlpResource `json:"resource"`
+		ScopeSpans []struct {
+			Spans []struct {
+				TraceID      string     `json:"traceId"`
+				SpanID       string     `json:"spanId"`
+				ParentSpanID string     `json:"parentSpanId"`
+				Name         string     `json:"name"`
+				Kind         int        `json:"kind"`
+				Attributes   []otlpAttr `json:"attributes"`
+				Status       struct {
+					Code int `json:"code"`
+				} `json:"status"`
+				Events []struct {
+					Name string `json:"name"`
+				} `json:"events"`
+			} `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpMetrics struct {
+	ResourceMetrics []struct {
+		ScopeMetrics []struct {
+			Metrics []struct {
+				Name string `json:"name"`
+			} `json:"metrics"`
+		} `json:"scopeMetrics"`
+	} `json:"resourceMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttr `json:"attributes"`
+}
+
+type otlpAttr struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string  `json:"stringValue"`
+		IntValue    *string  `json:"intValue"`
+		DoubleValue *float64 `json:"doubleValue"`
+		BoolValue   *bool    `json:"boolValue"`
+	} `json:"value"`
+}
+
+func otlpAttrs(in []otlpAttr) map[string]any {
+	out := make(map[string]any, len(in))
+	for _, a := range in {
+		v := a.Value
+		switch {
+		case v.StringValue != nil:
+			out[a.Key] = *v.StringValue
+		case v.IntValue != nil:
+			n, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+			out[a.Key] = n
+		case v.DoubleValue != nil:
+			out[a.Key] = *v.DoubleValue
+		case v.BoolValue != nil:
+			out[a.Key] = *v.BoolValue
+		}
+	}
+	return out
+}
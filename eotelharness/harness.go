@@ -0,0 +1,207 @@
+// Package eotelharness runs end-to-end observability tests: Start brings up
+// an OpenTelemetry collector and Loki with testcontainers, plus a fake
+// Sentry in-process, and returns clients to assert on what they received.
+//
+// It is a separate module so the testcontainers dependency stays out of
+// applications that only use eotel. Docker must be reachable.
+//
+//	h, err := eotelharness.Start(ctx)
+//	if err != nil {
+//		t.Skip(err)
+//	}
+//	defer h.Terminate(ctx)
+//	shutdown, _ := eotel.InitEOTEL(ctx, h.Config("checkout"))
+//	...
+//	_ = shutdown(ctx)
+//	h.RequireSpan(t, "GET /orders")
+package eotelharness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/nicedev97/eotel-v2"
+)
+
+const (
+	DefaultCollectorImage = "otel/opentelemetry-collector-contrib:0.130.0"
+	DefaultLokiImage      = "grafana/loki:3.5.2"
+
+	// DefaultWait bounds the Require helpers.
+	DefaultWait = 30 * time.Second
+
+	pollInterval = 250 * time.Millisecond
+)
+
+// Option configures Start.
+type Option func(*options)
+
+type options struct {
+	collectorImage string
+	lokiImage      string
+	loki           bool
+}
+
+// WithCollectorImage overrides DefaultCollectorImage. The image must be a
+// contrib build, for the file exporter.
+func WithCollectorImage(image string) Option {
+	return func(o *options) { o.collectorImage = image }
+}
+
+// WithLokiImage overrides DefaultLokiImage.
+func WithLokiImage(image string) Option {
+	return func(o *options) { o.lokiImage = image }
+}
+
+// WithoutLoki skips the Loki container.
+func WithoutLoki() Option {
+	return func(o *options) { o.loki = false }
+}
+
+// Harness is a running set of backends.
+type Harness struct {
+	// OTLPEndpoint is the collector's OTLP/gRPC host:port; LokiURL its push
+	// URL and SentryDSN the fake Sentry's DSN.
+	OTLPEndpoint string
+	LokiURL      string
+	SentryDSN    string
+
+	collector testcontainers.Container
+	loki      testcontainers.Container
+	lokiBase  string
+	sentry    *fakeSentry
+}
+
+// Start launches the backends. On error whatever was started is stopped
+// again. A missing Docker daemon is reported as an error, not a panic.
+func Start(ctx context.Context, opts ...Option) (h *Harness, err error) {
+	defer func() {
+		// testcontainers panics when it cannot find a Docker host.
+		if r := recover(); r != nil {
+			if h != nil {
+				_ = h.Terminate(ctx)
+			}
+			h, err = nil, fmt.Errorf("docker: %v", r)
+		}
+	}()
+	o := options{collectorImage: DefaultCollectorImage, lokiImage: DefaultLokiImage, loki: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h = &Harness{sentry: newFakeSentry()}
+	h.SentryDSN = h.sentry.dsn()
+
+	if h.collector, h.OTLPEndpoint, err = startCollector(ctx, o.collectorImage); err != nil {
+		_ = h.Terminate(ctx)
+		return nil, err
+	}
+	if o.loki {
+		if h.loki, h.lokiBase, err = startLoki(ctx, o.lokiImage); err != nil {
+			_ = h.Terminate(ctx)
+			return nil, err
+		}
+		h.LokiURL = h.lokiBase + "/loki/api/v1/push"
+	}
+	return h, nil
+}
+
+func startLoki(ctx context.Context, image string) (testcontainers.Container, string, error) {
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        image,
+			ExposedPorts: []string{"3100/tcp"},
+			Cmd:          []string{"-config.file=/etc/loki/local-config.yaml"},
+			WaitingFor:   wait.ForHTTP("/ready").WithPort("3100/tcp").WithStartupTimeout(2 * time.Minute),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return c, "", fmt.Errorf("loki container: %w", err)
+	}
+	base, err := c.PortEndpoint(ctx, "3100/tcp", "http")
+	if err != nil {
+		return c, "", fmt.Errorf("loki container: %w", err)
+	}
+	return c, base, nil
+}
+
+// Config returns an eotel configuration exporting everything to the
+// harness, with spans exported synchronously so they are visible as soon
+// as they end.
+func (h *Harness) Config(service string) eotel.Config {
+	cfg := eotel.Config{
+		ServiceName:   service,
+		JobName:       service,
+		OtelCollector: h.OTLPEndpoint,
+		OtelInsecure:  true,
+		EnableTracing: true,
+		EnableMetrics: true,
+		EnableSentry:  true,
+		SentryDSN:     h.SentryDSN,
+		SyncExport:    true,
+	}
+	if h.LokiURL != "" {
+		cfg.EnableLoki = true
+		cfg.LokiURL = h.LokiURL
+	}
+	return cfg
+}
+
+// Terminate stops the containers and the fake Sentry.
+func (h *Harness) Terminate(ctx context.Context) error {
+	var errs []error
+	for _, c := range []testcontainers.Container{h.collector, h.loki} {
+		if c != nil {
+			errs = append(errs, c.Terminate(ctx))
+		}
+	}
+	h.sentry.close()
+	return errors.Join(errs...)
+}
+
+// poll calls fn until it reports done, fails, or ctx ends.
+func poll(ctx context.Context, what string, fn func() (bool, error)) error {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	var last error
+	for {
+		done, err := fn()
+		if done {
+			return err
+		}
+		if err != nil {
+			last = err
+		}
+		select {
+		case <-ctx.Done():
+			if last != nil {
+				return fmt.Errorf("%s: %w (last error: %v)", what, ctx.Err(), last)
+			}
+			return fmt.Errorf("%s: %w", what, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+func require(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitCtx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), DefaultWait)
+}
+
+func containsFold(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
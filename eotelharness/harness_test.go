@@ -0,0 +1,67 @@
+package eotelharness_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eotelharness"
+)
+
+// TestExportersEndToEnd sends a request through the Gin middleware and
+// checks that its span, request metric, log line and captured error reach
+// the collector, Loki and Sentry. It is skipped when Docker is unavailable.
+func TestExportersEndToEnd(t *testing.T) {
+	ctx := context.Background()
+	h, err := eotelharness.Start(ctx)
+	if err != nil {
+		t.Skipf("harness: %v", err)
+	}
+	t.Cleanup(func() { _ = h.Terminate(context.Background()) })
+
+	shutdown, err := eotel.InitEOTEL(ctx, h.Config("e2e"))
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(eotel.Middleware("orders"))
+	r.GET("/orders/:id", func(c *gin.Context) {
+		log := eotel.FromGin(c, "orders")
+		log.Info("loading order")
+		eotel.CaptureErrorContext(c.Request.Context(), errors.New("order store unavailable"), nil, nil)
+		c.Status(http.StatusServiceUnavailable)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	span := h.RequireSpan(t, "GET /orders/:id")
+	if span.Service != "e2e" {
+		t.Errorf("span service = %q, want e2e", span.Service)
+	}
+	if got := span.Attributes["http.response.status_code"]; got != int64(http.StatusServiceUnavailable) {
+		t.Errorf("span status code = %v, want 503", got)
+	}
+	h.RequireMetric(t, "http_server_requests_total")
+	line := h.RequireLog(t, `{service="e2e"}`, "loading order")
+	if line.Labels["level"] != "info" {
+		t.Errorf("log level label = %q, want info", line.Labels["level"])
+	}
+	ev := h.RequireSentryEvent(t, "order store unavailable")
+	if ev.Tags["trace_id"] != span.TraceID {
+		t.Errorf("sentry event trace_id tag = %q, want the span's %q", ev.Tags["trace_id"], span.TraceID)
+	}
+}
@@ -0,0 +1,89 @@
+package eotelharness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// LokiLine is a log line returned by a Loki query.
+type LokiLine struct {
+	Labels map[string]string
+	Time   time.Time
+	Line   string
+}
+
+// QueryLoki runs a LogQL stream selector over the last hour.
+func (h *Harness) QueryLoki(ctx context.Context, query string) ([]LokiLine, error) {
+	if h.lokiBase == "" {
+		return nil, errors.New("loki is not running")
+	}
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("start", strconv.FormatInt(time.Now().Add(-time.Hour).UnixNano(), 10))
+	q.Set("limit", "1000")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.lokiBase+"/loki/api/v1/query_range?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki query: %s", resp.Status)
+	}
+	var body struct {
+		Data struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	var lines []LokiLine
+	for _, r := range body.Data.Result {
+		for _, v := range r.Values {
+			ns, _ := strconv.ParseInt(v[0], 10, 64)
+			lines = append(lines, LokiLine{Labels: r.Stream, Time: time.Unix(0, ns), Line: v[1]})
+		}
+	}
+	return lines, nil
+}
+
+// WaitForLog polls until query returns a line containing substr
+// (case-insensitively).
+func (h *Harness) WaitForLog(ctx context.Context, query, substr string) (LokiLine, error) {
+	var found LokiLine
+	err := poll(ctx, fmt.Sprintf("log %q in %s", substr, query), func() (bool, error) {
+		lines, err := h.QueryLoki(ctx, query)
+		for _, l := range lines {
+			if containsFold(l.Line, substr) {
+				found = l
+				return true, nil
+			}
+		}
+		return false, err
+	})
+	return found, err
+}
+
+// RequireLog is WaitForLog bounded by DefaultWait.
+func (h *Harness) RequireLog(t testing.TB, query, substr string) LokiLine {
+	t.Helper()
+	ctx, cancel := waitCtx()
+	defer cancel()
+	l, err := h.WaitForLog(ctx, query, substr)
+	require(t, err)
+	return l
+}
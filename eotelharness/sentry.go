@@ -0,0 +1,202 @@
+package eotelharness
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// SentryEvent is an error event received by the fake Sentry.
+type SentryEvent struct {
+	EventID    string
+	Level      string
+	Message    string
+	Exceptions []SentryException
+	Tags       map[string]string
+	Extra      map[string]any
+	TraceID    string
+}
+
+// SentryException is one entry of an event's exception chain.
+type SentryException struct {
+	Type  string
+	Value string
+}
+
+// fakeSentry accepts envelopes the way Sentry's ingestion endpoint does and
+// keeps the events. It runs in-process: an HTTP server is all a DSN needs.
+type fakeSentry struct {
+	srv *httptest.Server
+
+	mu     sync.Mutex
+	events []SentryEvent
+}
+
+func newFakeSentry() *fakeSentry {
+	f := &fakeSentry{}
+	f.srv = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+func (f *fakeSentry) dsn() string {
+	return strings.Replace(f.srv.URL, "://", "://public@", 1) + "/1"
+}
+
+func (f *fakeSentry) close() {
+	if f != nil {
+		f.srv.Close()
+	}
+}
+
+func (f *fakeSentry) handle(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var payloads [][]byte
+	if strings.HasSuffix(r.URL.Path, "/envelope/") {
+		payloads = envelopeEvents(data)
+	} else {
+		payloads = [][]byte{data}
+	}
+	for _, p := range payloads {
+		if ev, ok := parseSentryEvent(p); ok {
+			f.mu.Lock()
+			f.events = append(f.events, ev)
+			f.mu.Unlock()
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{}`))
+}
+
+// envelopeEvents returns the payloads of the event items of an envelope.
+func envelopeEvents(data []byte) [][]byte {
+	r := bufio.NewReader(bytes.NewReader(data))
+	if _, err := r.ReadBytes('\n'); err != nil {
+		return nil
+	}
+	var out [][]byte
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return out
+			}
+			continue
+		}
+		var item struct {
+			Type   string `json:"type"`
+			Length int    `json:"length"`
+		}
+		if json.Unmarshal(line, &item) != nil {
+			return out
+		}
+		var payload []byte
+		if item.Length > 0 {
+			payload = make([]byte, item.Length)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return out
+			}
+			_, _ = r.ReadBytes('\n')
+		} else {
+			payload, _ = r.ReadBytes('\n')
+		}
+		if item.Type == "event" {
+			out = append(out, payload)
+		}
+	}
+}
+
+func parseSentryEvent(data []byte) (SentryEvent, bool) {
+	var raw struct {
+		EventID   string            `json:"event_id"`
+		Level     string            `json:"level"`
+		Message   string            `json:"message"`
+		Tags      map[string]string `json:"tags"`
+		Extra     map[string]any    `json:"extra"`
+		Exception []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"exception"`
+		Contexts struct {
+			Trace struct {
+				TraceID string `json:"trace_id"`
+			} `json:"trace"`
+		} `json:"contexts"`
+	}
+	if json.Unmarshal(bytes.TrimSpace(data), &raw) != nil || raw.EventID == "" {
+		return SentryEvent{}, false
+	}
+	ev := SentryEvent{
+		EventID: raw.EventID,
+		Level:   raw.Level,
+		Message: raw.Message,
+		Tags:    raw.Tags,
+		Extra:   raw.Extra,
+		TraceID: raw.Contexts.Trace.TraceID,
+	}
+	for _, e := range raw.Exception {
+		ev.Exceptions = append(ev.Exceptions, SentryException{Type: e.Type, Value: e.Value})
+	}
+	return ev, true
+}
+
+// SentryEvents returns the events received so far.
+func (h *Harness) SentryEvents() []SentryEvent {
+	h.sentry.mu.Lock()
+	defer h.sentry.mu.Unlock()
+	return append([]SentryEvent(nil), h.sentry.events...)
+}
+
+// WaitForSentryEvent polls until an event whose message or exception value
+// contains substr arrives.
+func (h *Harness) WaitForSentryEvent(ctx context.Context, substr string) (SentryEvent, error) {
+	var found SentryEvent
+	err := poll(ctx, fmt.Sprintf("sentry event %q", substr), func() (bool, error) {
+		for _, ev := range h.SentryEvents() {
+			if containsFold(ev.Message, substr) {
+				found = ev
+				return true, nil
+			}
+			for _, e := range ev.Exceptions {
+				if containsFold(e.Value, substr) {
+					found = ev
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	return found, err
+}
+
+// RequireSentryEvent is WaitForSentryEvent bounded by DefaultWait.
+func (h *Harness) RequireSentryEvent(t testing.TB, substr string) SentryEvent {
+	t.Helper()
+	ctx, cancel := waitCtx()
+	defer cancel()
+	ev, err := h.WaitForSentryEvent(ctx, substr)
+	require(t, err)
+	return ev
+}
@@ -0,0 +1,56 @@
+package eotelharness
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+func TestEnvelopeEvents(t *testing.T) {
+	envelope := "{\"event_id\":\"a1\"}\n" +
+		"{\"type\":\"session\"}\n{\"sid\":\"s\"}\n" +
+		"{\"type\":\"event\",\"length\":38}\n" +
+		"{\"event_id\":\"a1\",\"message\":\"with len\"}\n" +
+		"{\"type\":\"event\"}\n{\"event_id\":\"b2\",\"message\":\"no length\"}\n"
+
+	payloads := envelopeEvents([]byte(envelope))
+	if len(payloads) != 2 {
+		t.Fatalf("got %d event payloads, want 2: %q", len(payloads), payloads)
+	}
+	for i, want := range []string{"with len", "no length"} {
+		ev, ok := parseSentryEvent(payloads[i])
+		if !ok || ev.Message != want {
+			t.Errorf("payload %d = %+v (ok=%v), want message %q", i, ev, ok, want)
+		}
+	}
+}
+
+func TestFakeSentryReceivesClientEvents(t *testing.T) {
+	fake := newFakeSentry()
+	defer fake.close()
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: fake.dsn()})
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+	hub.Scope().SetTag("tenant", "acme")
+	hub.CaptureException(errors.New("disk full"))
+	if !client.Flush(5 * time.Second) {
+		t.Fatal("sentry client did not flush")
+	}
+
+	h := &Harness{sentry: fake}
+	events := h.SentryEvents()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	ev := events[0]
+	if len(ev.Exceptions) == 0 || ev.Exceptions[0].Value != "disk full" {
+		t.Errorf("exceptions = %+v, want disk full", ev.Exceptions)
+	}
+	if ev.Tags["tenant"] != "acme" {
+		t.Errorf("tags = %v, want tenant=acme", ev.Tags)
+	}
+}
@@ -0,0 +1,82 @@
+// Package eotelhttp builds OTel HTTP semantic-convention attributes, so hand
+// instrumented code uses the same keys as the eotel middlewares.
+package eotelhttp
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Attrs returns the request attributes of req and, when resp is not nil, the
+// response status. Credentials in the URL are redacted.
+func Attrs(req *http.Request, resp *http.Response) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if req != nil {
+		attrs = append(attrs, attribute.String("http.request.method", req.Method))
+		if req.URL != nil {
+			attrs = append(attrs,
+				attribute.String("url.full", req.URL.Redacted()),
+				attribute.String("url.scheme", scheme(req)),
+				attribute.String("url.path", req.URL.Path),
+			)
+		}
+		if host, port := hostPort(req); host != "" {
+			attrs = append(attrs, attribute.String("server.address", host))
+			if port > 0 {
+				attrs = append(attrs, attribute.Int("server.port", port))
+			}
+		}
+		if ua := req.UserAgent(); ua != "" {
+			attrs = append(attrs, attribute.String("user_agent.original", ua))
+		}
+		if req.ContentLength > 0 {
+			attrs = append(attrs, attribute.Int64("http.request.body.size", req.ContentLength))
+		}
+		if req.ProtoMajor > 0 {
+			attrs = append(attrs, attribute.String("network.protocol.version", protoVersion(req.ProtoMajor, req.ProtoMinor)))
+		}
+	}
+	if resp != nil {
+		attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.ContentLength > 0 {
+			attrs = append(attrs, attribute.Int64("http.response.body.size", resp.ContentLength))
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			attrs = append(attrs, attribute.String("error.type", strconv.Itoa(resp.StatusCode)))
+		}
+	}
+	return attrs
+}
+
+func scheme(req *http.Request) string {
+	if req.URL.Scheme != "" {
+		return req.URL.Scheme
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func hostPort(req *http.Request) (string, int) {
+	hostport := req.Host
+	if req.URL != nil && req.URL.Host != "" {
+		hostport = req.URL.Host
+	}
+	host, p, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return hostport, 0
+	}
+	port, _ := strconv.Atoi(p)
+	return host, port
+}
+
+func protoVersion(major, minor int) string {
+	if minor == 0 && major >= 2 {
+		return strconv.Itoa(major)
+	}
+	return strconv.Itoa(major) + "." + strconv.Itoa(minor)
+}
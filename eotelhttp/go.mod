@@ -0,0 +1,5 @@
+module github.com/nicedev97/eotel-v2/eotelhttp
+
+go 1.24.4
+
+require go.opentelemetry.io/otel v1.37.0
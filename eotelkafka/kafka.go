@@ -0,0 +1,207 @@
+// Package eotelkafka instruments segmentio/kafka-go. Producer spans inject
+// the trace context into message headers and consumer spans continue it, so
+// an asynchronous flow stays in one trace.
+package eotelkafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelkafka"
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+// headerCarrier adapts kafka-go message headers to a TextMapCarrier.
+type headerCarrier struct {
+	msg *kafka.Message
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+// Option configures an Instrumentation.
+type Option func(*Instrumentation)
+
+// WithTracerProvider sets the provider message spans are started from. It
+// defaults to eotel.TracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(i *Instrumentation) {
+		i.tracerProvider = tp
+	}
+}
+
+// Instrumentation carries the options of WriteMessages and ProcessMessage; the
+// package-level functions use a zero Instrumentation.
+type Instrumentation struct {
+	tracerProvider trace.TracerProvider
+}
+
+func New(opts ...Option) *Instrumentation {
+	i := &Instrumentation{}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+var defaultInstrumentation = &Instrumentation{}
+
+// The default provider is looked up per call so the package-level functions
+// follow a provider injected by InitEOTEL after import.
+func (i *Instrumentation) tracer() trace.Tracer {
+	tp := i.tracerProvider
+	if tp == nil {
+		tp = eotel.TracerProvider()
+	}
+	return tp.Tracer(ScopeName)
+}
+
+var (
+	metricsOnce sync.Once
+	messages    metric.Int64Counter
+	lag         metric.Float64Histogram
+)
+
+func initMetrics() {
+	var err error
+	if messages, err = eotel.Int64Counter("eotel.messaging.messages", "{message}", "Number of messages produced or consumed."); err != nil {
+		messages = noop.Int64Counter{}
+	}
+	if lag, err = eotel.Float64Histogram("eotel.messaging.consumer.lag", "{message}", "Messages between the consumed offset and the partition high watermark."); err != nil {
+		lag = noop.Float64Histogram{}
+	}
+}
+
+// WriteMessages writes msgs through w inside a producer span whose context is
+// injected into every message's headers.
+func WriteMessages(ctx context.Context, w *kafka.Writer, msgs ...kafka.Message) error {
+	return defaultInstrumentation.WriteMessages(ctx, w, msgs...)
+}
+
+// WriteMessages is the package-level WriteMessages using i's options.
+func (i *Instrumentation) WriteMessages(ctx context.Context, w *kafka.Writer, msgs ...kafka.Message) error {
+	metricsOnce.Do(initMetrics)
+
+	topic := w.Topic
+	if topic == "" && len(msgs) > 0 {
+		topic = msgs[0].Topic
+	}
+	ctx, span := i.tracer().Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "send"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.Int("messaging.batch.message_count", len(msgs)),
+		),
+	)
+	defer span.End()
+
+	prop := otel.GetTextMapPropagator()
+	for i := range msgs {
+		prop.Inject(ctx, headerCarrier{&msgs[i]})
+	}
+
+	err := w.WriteMessages(ctx, msgs...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		eotel.FromContext(ctx, "kafka.producer").
+			WithField("messaging.destination.name", topic).
+			WithError(err).
+			Error("kafka write failed")
+	}
+
+	// Writers with a topic reject messages setting their own, so a batch
+	// always targets a single topic.
+	messages.Add(context.WithoutCancel(ctx), int64(len(msgs)), metric.WithAttributes(
+		attribute.String("messaging.destination.name", topic),
+		attribute.String("messaging.operation.type", "send"),
+		attribute.Bool("error", err != nil),
+	))
+	return err
+}
+
+// ProcessMessage runs fn inside a consumer span continuing the trace found in
+// msg's headers. fn's context carries an eotel logger bound to that span.
+func ProcessMessage(ctx context.Context, msg kafka.Message, fn func(context.Context, kafka.Message) error) error {
+	return defaultInstrumentation.ProcessMessage(ctx, msg, fn)
+}
+
+// ProcessMessage is the package-level ProcessMessage using i's options.
+func (i *Instrumentation) ProcessMessage(ctx context.Context, msg kafka.Message, fn func(context.Context, kafka.Message) error) error {
+	metricsOnce.Do(initMetrics)
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{&msg})
+	ctx, span := i.tracer().Start(ctx, "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "process"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.destination.partition.id", msg.Partition),
+			attribute.Int64("messaging.kafka.offset", msg.Offset),
+		),
+	)
+	defer span.End()
+
+	logger := eotel.Safe(eotel.New(ctx, "kafka.consumer")).
+		WithField("messaging.destination.name", msg.Topic).
+		WithField("messaging.kafka.offset", msg.Offset)
+	ctx = eotel.Inject(ctx, logger)
+
+	err := fn(ctx, msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.WithError(err).Error("kafka message processing failed")
+	}
+
+	mctx := context.WithoutCancel(ctx)
+	topicAttr := attribute.String("messaging.destination.name", msg.Topic)
+	messages.Add(mctx, 1, metric.WithAttributes(
+		topicAttr,
+		attribute.String("messaging.operation.type", "process"),
+		attribute.Bool("error", err != nil),
+	))
+	if msg.HighWaterMark > 0 {
+		lag.Record(mctx, float64(msg.HighWaterMark-msg.Offset-1), metric.WithAttributes(topicAttr))
+	}
+	return err
+}
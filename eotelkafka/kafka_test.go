@@ -0,0 +1,29 @@
+package eotelkafka_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nicedev97/eotel-v2/eotelkafka"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	inst := eotelkafka.New(eotelkafka.WithTracerProvider(tp))
+
+	msg := kafka.Message{Topic: "orders", Offset: 3}
+	err := inst.ProcessMessage(context.Background(), msg, func(context.Context, kafka.Message) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "process orders" {
+		t.Fatalf("spans = %v, want one process orders span", spans)
+	}
+}
@@ -0,0 +1,26 @@
+// Package eotelloki is the import path for the Loki pipeline. The pipeline
+// only needs net/http and shares the core's export queue, tenants and health
+// reporting, so it is implemented in the core package; this module adds no
+// dependency beyond it.
+package eotelloki
+
+import (
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+// Entry is a log line queued for Loki.
+type Entry = eotel.LokiEntry
+
+// Exporter ships log lines to Loki; see eotel.LokiExporter.
+type Exporter = eotel.LokiExporter
+
+// Send queues a log line for Loki.
+func Send(level, msg, traceID, spanID string) {
+	eotel.SendLokiAsync(level, msg, traceID, spanID)
+}
+
+// SendFields queues a log line with fields for Loki; see
+// eotel.SendLokiFields for how fields map onto stream labels.
+func SendFields(level, msg, traceID, spanID string, fields map[string]any) {
+	eotel.SendLokiFields(level, msg, traceID, spanID, fields)
+}
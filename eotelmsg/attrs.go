@@ -0,0 +1,44 @@
+// Package eotelmsg builds OTel messaging semantic-convention attributes, so
+// hand instrumented producers and consumers use the same keys as eotelkafka
+// and eotelsarama.
+package eotelmsg
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Operation types for messaging.operation.type.
+const (
+	Send    = "send"
+	Receive = "receive"
+	Process = "process"
+)
+
+// Attrs returns the attributes of a messaging system (kafka, rabbitmq,
+// aws_sqs, ...) acting on topic.
+func Attrs(system, topic string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2)
+	if system != "" {
+		attrs = append(attrs, attribute.String("messaging.system", system))
+	}
+	if topic != "" {
+		attrs = append(attrs, attribute.String("messaging.destination.name", topic))
+	}
+	return attrs
+}
+
+// OperationType returns the messaging.operation.type attribute; see Send,
+// Receive and Process.
+func OperationType(op string) attribute.KeyValue {
+	return attribute.String("messaging.operation.type", op)
+}
+
+// MessageID returns the messaging.message.id attribute.
+func MessageID(id string) attribute.KeyValue {
+	return attribute.String("messaging.message.id", id)
+}
+
+// BatchCount returns the messaging.batch.message_count attribute.
+func BatchCount(n int) attribute.KeyValue {
+	return attribute.Int("messaging.batch.message_count", n)
+}
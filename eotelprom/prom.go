@@ -0,0 +1,45 @@
+// Package eotelprom builds eotel's Prometheus exporter and push gateway on
+// client_golang. Importing it registers the backend InitEOTEL uses for
+// MetricsExporter "prometheus" and MetricsPushGatewayURL; the root eotel
+// package imports it, so only services built on the core package need to.
+package eotelprom
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+func init() {
+	eotel.RegisterPrometheusBackend(Backend{})
+}
+
+// Backend is the eotel.PrometheusBackend built on client_golang. Each
+// reader gets a registry of its own.
+type Backend struct{}
+
+func (Backend) NewReader() (sdkmetric.Reader, http.Handler, error) {
+	reg := prometheus.NewRegistry()
+	reader, err := otelprom.New(otelprom.WithRegisterer(reg))
+	if err != nil {
+		return nil, nil, err
+	}
+	// OpenMetrics is the only exposition format carrying exemplars.
+	return reader, promhttp.HandlerFor(reg, promhttp.HandlerOpts{EnableOpenMetrics: true}), nil
+}
+
+func (Backend) NewPusher(url, job string) (sdkmetric.Reader, func(context.Context) error, error) {
+	reg := prometheus.NewRegistry()
+	reader, err := otelprom.New(otelprom.WithRegisterer(reg))
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, push.New(url, job).Gatherer(reg).PushContext, nil
+}
@@ -0,0 +1,192 @@
+// Package eotelredis provides a go-redis hook emitting eotel spans, latency
+// metrics and slow command logs:
+//
+//	rdb.AddHook(eotelredis.NewHook(eotelredis.WithSlowThreshold(50 * time.Millisecond)))
+package eotelredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelredis"
+
+type Option func(*Hook)
+
+// WithSlowThreshold logs commands taking longer than d as warnings.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(h *Hook) {
+		h.slowThreshold = d
+	}
+}
+
+// WithoutKeys stops the command key from being attached to spans and logs,
+// for deployments where keys carry user data.
+func WithoutKeys() Option {
+	return func(h *Hook) {
+		h.omitKeys = true
+	}
+}
+
+// WithTracerProvider sets the provider command spans are started from. It
+// defaults to eotel.TracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(h *Hook) {
+		h.tracerProvider = tp
+	}
+}
+
+// Hook implements redis.Hook.
+type Hook struct {
+	slowThreshold  time.Duration
+	omitKeys       bool
+	tracerProvider trace.TracerProvider
+
+	once     sync.Once
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+func NewHook(opts ...Option) *Hook {
+	h := &Hook{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Hook) init() {
+	tp := h.tracerProvider
+	if tp == nil {
+		tp = eotel.TracerProvider()
+	}
+	h.tracer = tp.Tracer(ScopeName)
+
+	var err error
+	if h.duration, err = eotel.Float64Histogram("eotel.redis.command.duration", "ms", "Duration of Redis commands."); err != nil {
+		h.duration = noop.Float64Histogram{}
+	}
+	if h.errors, err = eotel.Int64Counter("eotel.redis.command.errors", "{error}", "Number of failed Redis commands."); err != nil {
+		h.errors = noop.Int64Counter{}
+	}
+}
+
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		h.once.Do(h.init)
+		ctx, span := h.tracer.Start(ctx, "redis.dial",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("server.address", addr)),
+		)
+		defer span.End()
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return conn, err
+	}
+}
+
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, done := h.start(ctx, cmd.FullName(), h.commandAttrs(cmd))
+		err := next(ctx, cmd)
+		done(err, cmd)
+		return err
+	}
+}
+
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			names = append(names, cmd.FullName())
+		}
+		attrs := []attribute.KeyValue{
+			attribute.String("db.operation.name", "pipeline"),
+			attribute.Int("db.operation.batch.size", len(cmds)),
+			attribute.String("db.query.text", strings.Join(names, " ")),
+		}
+		ctx, done := h.start(ctx, "pipeline", attrs)
+		err := next(ctx, cmds)
+		done(err, nil)
+		return err
+	}
+}
+
+func (h *Hook) commandAttrs(cmd redis.Cmder) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("db.operation.name", cmd.FullName())}
+	if key := commandKey(cmd); key != "" && !h.omitKeys {
+		attrs = append(attrs, attribute.String("db.redis.key", key))
+	}
+	return attrs
+}
+
+func (h *Hook) start(ctx context.Context, name string, attrs []attribute.KeyValue) (context.Context, func(error, redis.Cmder)) {
+	h.once.Do(h.init)
+
+	start := time.Now()
+	ctx, span := h.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system.name", "redis")),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, func(err error, cmd redis.Cmder) {
+		defer span.End()
+		elapsed := time.Since(start)
+
+		// Keys are high-cardinality; metrics only carry the operation.
+		mattrs := metric.WithAttributes(attribute.String("db.operation.name", name))
+		mctx := context.WithoutCancel(ctx)
+		h.duration.Record(mctx, elapsed.Seconds()*1000, mattrs)
+
+		if err != nil && !errors.Is(err, redis.Nil) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			h.errors.Add(mctx, 1, mattrs)
+		}
+
+		if h.slowThreshold > 0 && elapsed >= h.slowThreshold {
+			log := eotel.FromContext(ctx, "redis").
+				WithField("db.operation.name", name).
+				WithDuration("duration", elapsed)
+			if key := commandKey(cmd); key != "" && !h.omitKeys {
+				log = log.WithField("db.redis.key", key)
+			}
+			log.Warn("slow redis command")
+		}
+	}
+}
+
+// commandKey returns the first argument after the command (and subcommand)
+// name, which is the key for the vast majority of commands.
+func commandKey(cmd redis.Cmder) string {
+	if cmd == nil {
+		return ""
+	}
+	i := len(strings.Fields(cmd.FullName()))
+	args := cmd.Args()
+	if len(args) <= i {
+		return ""
+	}
+	return fmt.Sprint(args[i])
+}
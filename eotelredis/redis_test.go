@@ -0,0 +1,35 @@
+package eotelredis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nicedev97/eotel-v2/eotelredis"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	hook := eotelredis.NewHook(eotelredis.WithTracerProvider(tp))
+
+	process := hook.ProcessHook(func(context.Context, redis.Cmder) error { return nil })
+	ctx := context.Background()
+	if err := process(ctx, redis.NewStringCmd(ctx, "get", "user:7")); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "get" {
+		t.Fatalf("spans = %v, want one get span", spans)
+	}
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "db.redis.key" && kv.Value != attribute.StringValue("user:7") {
+			t.Errorf("db.redis.key = %v, want user:7", kv.Value.Emit())
+		}
+	}
+}
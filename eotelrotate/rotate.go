@@ -0,0 +1,27 @@
+// Package eotelrotate rotates eotel's log files with lumberjack according to
+// LoggerConfig.Rotation. Importing it registers the rotator; the root eotel
+// package imports it, so only services built on the core package need to.
+package eotelrotate
+
+import (
+	"io"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+func init() {
+	eotel.RegisterLogRotator(New)
+}
+
+// New returns a writer to path rotated according to rot.
+func New(path string, rot eotel.RotationConfig) io.Writer {
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    rot.MaxSizeMB,
+		MaxAge:     rot.MaxAgeDays,
+		MaxBackups: rot.MaxBackups,
+		Compress:   rot.Compress,
+	}
+}
@@ -0,0 +1,230 @@
+// Package eotelsarama instruments IBM/sarama producers and consumer group
+// handlers with the same spans, header propagation and metrics as eotelkafka.
+package eotelsarama
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelsarama"
+
+type producerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func (c producerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c producerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c producerCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = string(h.Key)
+	}
+	return keys
+}
+
+type consumerCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+func (c consumerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set is a no-op: consumed messages are read-only.
+func (c consumerCarrier) Set(string, string) {}
+
+func (c consumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
+
+var (
+	_ propagation.TextMapCarrier = producerCarrier{}
+	_ propagation.TextMapCarrier = consumerCarrier{}
+)
+
+// Option configures an Instrumentation.
+type Option func(*Instrumentation)
+
+// WithTracerProvider sets the provider message spans are started from. It
+// defaults to eotel.TracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(i *Instrumentation) {
+		i.tracerProvider = tp
+	}
+}
+
+// Instrumentation carries the options of SendMessage and ProcessMessage; the
+// package-level functions use a zero Instrumentation.
+type Instrumentation struct {
+	tracerProvider trace.TracerProvider
+}
+
+func New(opts ...Option) *Instrumentation {
+	i := &Instrumentation{}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+var defaultInstrumentation = &Instrumentation{}
+
+// The default provider is looked up per call so the package-level functions
+// follow a provider injected by InitEOTEL after import.
+func (i *Instrumentation) tracer() trace.Tracer {
+	tp := i.tracerProvider
+	if tp == nil {
+		tp = eotel.TracerProvider()
+	}
+	return tp.Tracer(ScopeName)
+}
+
+var (
+	metricsOnce sync.Once
+	messages    metric.Int64Counter
+	lag         metric.Float64Histogram
+)
+
+func initMetrics() {
+	var err error
+	if messages, err = eotel.Int64Counter("eotel.messaging.messages", "{message}", "Number of messages produced or consumed."); err != nil {
+		messages = noop.Int64Counter{}
+	}
+	if lag, err = eotel.Float64Histogram("eotel.messaging.consumer.lag", "{message}", "Messages between the consumed offset and the partition high watermark."); err != nil {
+		lag = noop.Float64Histogram{}
+	}
+}
+
+// SendMessage sends msg through p inside a producer span whose context is
+// injected into the message headers.
+func SendMessage(ctx context.Context, p sarama.SyncProducer, msg *sarama.ProducerMessage) (int32, int64, error) {
+	return defaultInstrumentation.SendMessage(ctx, p, msg)
+}
+
+// SendMessage is the package-level SendMessage using i's options.
+func (i *Instrumentation) SendMessage(ctx context.Context, p sarama.SyncProducer, msg *sarama.ProducerMessage) (int32, int64, error) {
+	metricsOnce.Do(initMetrics)
+
+	ctx, span := i.tracer().Start(ctx, "publish "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "send"),
+			attribute.String("messaging.destination.name", msg.Topic),
+		),
+	)
+	defer span.End()
+
+	otel.GetTextMapPropagator().Inject(ctx, producerCarrier{msg})
+
+	partition, offset, err := p.SendMessage(msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		eotel.FromContext(ctx, "kafka.producer").
+			WithField("messaging.destination.name", msg.Topic).
+			WithError(err).
+			Error("kafka send failed")
+	} else {
+		span.SetAttributes(
+			attribute.Int("messaging.destination.partition.id", int(partition)),
+			attribute.Int64("messaging.kafka.offset", offset),
+		)
+	}
+
+	messages.Add(context.WithoutCancel(ctx), 1, metric.WithAttributes(
+		attribute.String("messaging.destination.name", msg.Topic),
+		attribute.String("messaging.operation.type", "send"),
+		attribute.Bool("error", err != nil),
+	))
+	return partition, offset, err
+}
+
+// ProcessMessage runs fn inside a consumer span continuing the trace found in
+// msg's headers; call it from ConsumeClaim. claim may be nil, in which case
+// lag is not recorded.
+func ProcessMessage(ctx context.Context, claim sarama.ConsumerGroupClaim, msg *sarama.ConsumerMessage, fn func(context.Context, *sarama.ConsumerMessage) error) error {
+	return defaultInstrumentation.ProcessMessage(ctx, claim, msg, fn)
+}
+
+// ProcessMessage is the package-level ProcessMessage using i's options.
+func (i *Instrumentation) ProcessMessage(ctx context.Context, claim sarama.ConsumerGroupClaim, msg *sarama.ConsumerMessage, fn func(context.Context, *sarama.ConsumerMessage) error) error {
+	metricsOnce.Do(initMetrics)
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, consumerCarrier{msg})
+	ctx, span := i.tracer().Start(ctx, "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "process"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.destination.partition.id", int(msg.Partition)),
+			attribute.Int64("messaging.kafka.offset", msg.Offset),
+		),
+	)
+	defer span.End()
+
+	logger := eotel.Safe(eotel.New(ctx, "kafka.consumer")).
+		WithField("messaging.destination.name", msg.Topic).
+		WithField("messaging.kafka.offset", msg.Offset)
+	ctx = eotel.Inject(ctx, logger)
+
+	err := fn(ctx, msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.WithError(err).Error("kafka message processing failed")
+	}
+
+	mctx := context.WithoutCancel(ctx)
+	topicAttr := attribute.String("messaging.destination.name", msg.Topic)
+	messages.Add(mctx, 1, metric.WithAttributes(
+		topicAttr,
+		attribute.String("messaging.operation.type", "process"),
+		attribute.Bool("error", err != nil),
+	))
+	if claim != nil {
+		lag.Record(mctx, float64(claim.HighWaterMarkOffset()-msg.Offset-1), metric.WithAttributes(topicAttr))
+	}
+	return err
+}
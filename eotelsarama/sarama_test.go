@@ -0,0 +1,29 @@
+package eotelsarama_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nicedev97/eotel-v2/eotelsarama"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	inst := eotelsarama.New(eotelsarama.WithTracerProvider(tp))
+
+	msg := &sarama.ConsumerMessage{Topic: "orders", Offset: 3}
+	err := inst.ProcessMessage(context.Background(), nil, msg, func(context.Context, *sarama.ConsumerMessage) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "process orders" {
+		t.Fatalf("spans = %v, want one process orders span", spans)
+	}
+}
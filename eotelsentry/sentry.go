@@ -0,0 +1,201 @@
+// Package eotelsentry reports eotel's captured errors to Sentry through
+// sentry-go. Importing it registers the backend InitEOTEL uses when
+// Config.EnableSentry is set; the root eotel package imports it, so only
+// services built on the core package need to.
+package eotelsentry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+func init() {
+	eotel.RegisterSentryBackend(New)
+}
+
+// Exporter reports errors to Sentry; see eotel.SentryExporter.
+type Exporter = eotel.SentryExporter
+
+// FingerprintRule groups matching errors into one Sentry issue.
+type FingerprintRule = eotel.SentryFingerprintRule
+
+// CaptureError queues err for Sentry.
+func CaptureError(err error, tags map[string]string, extras map[string]any) {
+	eotel.CaptureError(err, tags, extras)
+}
+
+// CaptureErrorContext queues err for Sentry with the request scope bound to
+// ctx; see eotel.CaptureErrorContext.
+func CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	eotel.CaptureErrorContext(ctx, err, tags, extras)
+}
+
+// sentrySDK is the eotel.SentryBackend built on sentry-go. Tenant clients are
+// created on first use with the options of the shared client.
+type sentrySDK struct {
+	opts   sentry.ClientOptions
+	client *sentry.Client
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	tenants map[string]*sentry.Client
+}
+
+// New returns the backend for o. BeforeSend, when set, must be a
+// func(*sentry.Event, *sentry.EventHint) *sentry.Event.
+func New(o eotel.SentryOptions) (eotel.SentryBackend, error) {
+	opts := sentry.ClientOptions{
+		Dsn:              o.DSN,
+		EnableTracing:    o.EnableTracing,
+		SampleRate:       o.SampleRate,
+		TracesSampleRate: o.TracesSampleRate,
+		Environment:      o.Environment,
+		Release:          o.Release,
+		Debug:            o.Debug,
+		HTTPClient:       o.HTTPClient,
+	}
+	switch fn := o.BeforeSend.(type) {
+	case nil:
+	case func(*sentry.Event, *sentry.EventHint) *sentry.Event:
+		opts.BeforeSend = fn
+	default:
+		return nil, fmt.Errorf("SentryBeforeSend is %T, want func(*sentry.Event, *sentry.EventHint) *sentry.Event", o.BeforeSend)
+	}
+	client, err := sentry.NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	logger := o.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &sentrySDK{opts: opts, client: client, logger: logger}, nil
+}
+
+func (s *sentrySDK) Install() {
+	sentry.CurrentHub().BindClient(s.client)
+}
+
+func (s *sentrySDK) WithRequest(ctx context.Context, r *http.Request, clientIP string) context.Context {
+	hub := sentry.CurrentHub().Clone()
+	scope := hub.Scope()
+	scope.SetRequest(r)
+	scope.SetUser(sentry.User{IPAddress: clientIP})
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		scope.SetTag("trace_id", sc.TraceID().String())
+	}
+	return sentry.SetHubOnContext(ctx, hub)
+}
+
+func (s *sentrySDK) AddBreadcrumb(ctx context.Context, b eotel.Breadcrumb) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		return
+	}
+	var data map[string]any
+	if b.Fields != nil {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range b.Fields {
+			f.AddTo(enc)
+		}
+		data = enc.Fields
+	}
+	hub.AddBreadcrumb(&sentry.Breadcrumb{
+		Type:      b.Type,
+		Category:  b.Category,
+		Message:   b.Message,
+		Level:     sentryLevel(b.Level),
+		Data:      data,
+		Timestamp: time.Now(),
+	}, nil)
+}
+
+func (s *sentrySDK) Prepare(ctx context.Context, ev eotel.SentryEvent) func() {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	h := hub.Clone()
+	if ev.DSN != "" {
+		if c := s.tenantClient(ev.DSN); c != nil {
+			h.BindClient(c)
+		}
+	}
+	scope := h.Scope()
+	for k, v := range ev.Tags {
+		scope.SetTag(k, v)
+	}
+	for k, v := range ev.Extras {
+		scope.SetExtra(k, v)
+	}
+	if ev.Fingerprint != nil {
+		scope.SetFingerprint(ev.Fingerprint)
+	}
+	return func() { h.CaptureException(ev.Err) }
+}
+
+// Flush waits for the shared client and then the tenant clients, all
+// within timeout.
+func (s *sentrySDK) Flush(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	ok := s.client.Flush(timeout)
+	s.mu.Lock()
+	clients := make([]*sentry.Client, 0, len(s.tenants))
+	for _, c := range s.tenants {
+		if c != nil {
+			clients = append(clients, c)
+		}
+	}
+	s.mu.Unlock()
+	for _, c := range clients {
+		if !c.Flush(time.Until(deadline)) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (s *sentrySDK) tenantClient(dsn string) *sentry.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.tenants[dsn]; ok {
+		return c
+	}
+	opts := s.opts
+	opts.Dsn = dsn
+	c, err := sentry.NewClient(opts)
+	if err != nil {
+		s.logger.Warn("eotel: tenant sentry client", zap.Error(err))
+		c = nil
+	}
+	if s.tenants == nil {
+		s.tenants = map[string]*sentry.Client{}
+	}
+	// A bad DSN is remembered as nil so it is reported once.
+	s.tenants[dsn] = c
+	return c
+}
+
+func sentryLevel(level string) sentry.Level {
+	switch level {
+	case "debug":
+		return sentry.LevelDebug
+	case "warn":
+		return sentry.LevelWarning
+	case "error":
+		return sentry.LevelError
+	case "fatal":
+		return sentry.LevelFatal
+	}
+	return sentry.LevelInfo
+}
@@ -0,0 +1,282 @@
+// Package eotelsql instruments database/sql: every query gets a client span,
+// duration and error metrics, and slow queries are logged through the eotel
+// logger found in the query context.
+package eotelsql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2/eotel"
+)
+
+const ScopeName = "github.com/nicedev97/eotel-v2/eotelsql"
+
+type Option func(*config)
+
+type config struct {
+	system         string
+	slowThreshold  time.Duration
+	tracerProvider trace.TracerProvider
+}
+
+// WithDBSystem sets db.system.name on spans and metrics (postgresql, mysql, ...).
+func WithDBSystem(system string) Option {
+	return func(c *config) {
+		c.system = system
+	}
+}
+
+// WithSlowQueryThreshold logs queries taking longer than d as warnings.
+// Zero disables slow query logging.
+func WithSlowQueryThreshold(d time.Duration) Option {
+	return func(c *config) {
+		c.slowThreshold = d
+	}
+}
+
+// WithTracerProvider sets the provider query spans are started from. It
+// defaults to eotel.TracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// DB wraps *sql.DB; the query methods below are instrumented, everything
+// else is the embedded *sql.DB.
+type DB struct {
+	*sql.DB
+	inst *instrumenter
+}
+
+// Open is sql.Open followed by Wrap.
+func Open(driverName, dsn string, opts ...Option) (*DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(db, opts...), nil
+}
+
+func Wrap(db *sql.DB, opts ...Option) *DB {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &DB{DB: db, inst: &instrumenter{cfg: cfg}}
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, done := db.inst.start(ctx, query)
+	res, err := db.DB.ExecContext(ctx, query, args...)
+	done(err)
+	return res, err
+}
+
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, done := db.inst.start(ctx, query)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRowContext ends the span once the row is returned; errors surfacing
+// later from Scan are not recorded.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, done := db.inst.start(ctx, query)
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, inst: db.inst}, nil
+}
+
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// Tx wraps *sql.Tx with the same instrumentation as DB.
+type Tx struct {
+	*sql.Tx
+	inst *instrumenter
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, done := tx.inst.start(ctx, query)
+	res, err := tx.Tx.ExecContext(ctx, query, args...)
+	done(err)
+	return res, err
+}
+
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, done := tx.inst.start(ctx, query)
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (tx *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, done := tx.inst.start(ctx, query)
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+func (tx *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+type instrumenter struct {
+	cfg config
+
+	once     sync.Once
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+// The tracer and instruments are resolved on first query so Wrap may run
+// before InitEOTEL.
+func (i *instrumenter) init() {
+	tp := i.cfg.tracerProvider
+	if tp == nil {
+		tp = eotel.TracerProvider()
+	}
+	i.tracer = tp.Tracer(ScopeName)
+
+	var err error
+	i.duration, err = eotel.Float64Histogram("eotel.db.query.duration", "ms", "Duration of database queries.")
+	if err != nil {
+		i.duration = noop.Float64Histogram{}
+	}
+	i.errors, err = eotel.Int64Counter("eotel.db.query.errors", "{error}", "Number of failed database queries.")
+	if err != nil {
+		i.errors = noop.Int64Counter{}
+	}
+}
+
+func (i *instrumenter) start(ctx context.Context, query string) (context.Context, func(error)) {
+	i.once.Do(i.init)
+
+	statement := SanitizeSQL(query)
+	op := operation(statement)
+	attrs := []attribute.KeyValue{attribute.String("db.operation.name", op)}
+	if i.cfg.system != "" {
+		attrs = append(attrs, attribute.String("db.system.name", i.cfg.system))
+	}
+
+	start := time.Now()
+	ctx, span := i.tracer.Start(ctx, op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(attribute.String("db.query.text", statement)),
+	)
+
+	return ctx, func(err error) {
+		defer span.End()
+		elapsed := time.Since(start)
+		mctx := context.WithoutCancel(ctx)
+		i.duration.Record(mctx, elapsed.Seconds()*1000, metric.WithAttributes(attrs...))
+
+		if err != nil && err != sql.ErrNoRows {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			i.errors.Add(mctx, 1, metric.WithAttributes(attrs...))
+		}
+
+		if i.cfg.slowThreshold > 0 && elapsed >= i.cfg.slowThreshold {
+			eotel.FromContext(ctx, "sql").
+				WithField("db.query.text", statement).
+				WithDuration("duration", elapsed).
+				Warn("slow query")
+		}
+	}
+}
+
+func operation(statement string) string {
+	op, _, _ := strings.Cut(strings.TrimSpace(statement), " ")
+	if op == "" {
+		return "QUERY"
+	}
+	return strings.ToUpper(op)
+}
+
+// SanitizeSQL replaces string and numeric literals with ? and collapses
+// whitespace, so query text can be attached to spans without leaking values.
+func SanitizeSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	var prev byte = ' '
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			// Skip to the closing quote, honouring '' escapes.
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			c = '?'
+		case isDigit(c) && !isIdent(prev):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			c = '?'
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if prev == ' ' {
+				continue
+			}
+			c = ' '
+		}
+		b.WriteByte(c)
+		prev = c
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,176 @@
+// Package eoteltest wires eotel to in-memory exporters so tests can assert
+// on the spans, metrics and logs produced by their instrumentation.
+package eoteltest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	eotel "github.com/nicedev97/eotel-v2"
+)
+
+const serviceName = "eoteltest"
+
+// Recorder holds everything eotel exported during a test.
+type Recorder struct {
+	Spans  *tracetest.SpanRecorder
+	Reader *sdkmetric.ManualReader
+	Logs   *observer.ObservedLogs
+}
+
+// NewRecorder initialises eotel with in-memory trace and metric pipelines
+// and shuts it down when the test ends. Tests using it must not run in
+// parallel since eotel's configuration is process-wide.
+func NewRecorder(t testing.TB) *Recorder {
+	t.Helper()
+	rec := &Recorder{
+		Spans:  tracetest.NewSpanRecorder(),
+		Reader: sdkmetric.NewManualReader(),
+	}
+	core, logs := observer.New(zapcore.DebugLevel)
+	rec.Logs = logs
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec.Spans))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(rec.Reader))
+
+	shutdown, err := eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:    serviceName,
+		JobName:        serviceName,
+		TracerProvider: tp,
+		MeterProvider:  mp,
+		Logger:         zap.New(core),
+	})
+	if err != nil {
+		t.Fatalf("eoteltest: init: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		_ = shutdown(ctx)
+		_ = tp.Shutdown(ctx)
+		_ = mp.Shutdown(ctx)
+	})
+	return rec
+}
+
+// NewGinEngine returns a Gin engine in test mode with eotel.Middleware
+// installed, and the recorder receiving its telemetry.
+func NewGinEngine(t testing.TB, opts ...eotel.MiddlewareOption) (*gin.Engine, *Recorder) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	rec := NewRecorder(t)
+	r := gin.New()
+	r.Use(eotel.Middleware(serviceName, opts...))
+	return r, rec
+}
+
+// Ended returns the spans ended so far.
+func (r *Recorder) Ended() []sdktrace.ReadOnlySpan {
+	return r.Spans.Ended()
+}
+
+// Logger returns a logger writing into the recorder, for code under test
+// that takes an *eotel.Eotel.
+func (r *Recorder) Logger(name string) *eotel.Eotel {
+	return eotel.New(context.Background(), name)
+}
+
+// Logged returns the log entries written so far.
+func (r *Recorder) Logged() []observer.LoggedEntry {
+	return r.Logs.All()
+}
+
+// Metrics collects the current metric state.
+func (r *Recorder) Metrics(t testing.TB) metricdata.ResourceMetrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := r.Reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("eoteltest: collect metrics: %v", err)
+	}
+	return rm
+}
+
+// AssertHTTPSpan checks that a server span was recorded for route (the
+// http.route attribute, or the span name when the attribute is missing) with
+// the given response status.
+func AssertHTTPSpan(t testing.TB, rec *Recorder, route string, status int) sdktrace.ReadOnlySpan {
+	t.Helper()
+	var seen []string
+	for _, s := range rec.Ended() {
+		if s.SpanKind() != trace.SpanKindServer {
+			continue
+		}
+		attrs := attrMap(s.Attributes())
+		spanRoute := attrs["http.route"].AsString()
+		if spanRoute == "" {
+			spanRoute = s.Name()
+		}
+		seen = append(seen, fmt.Sprintf("%s (%d)", spanRoute, attrs["http.response.status_code"].AsInt64()))
+		if spanRoute != route && !strings.HasSuffix(s.Name(), " "+route) {
+			continue
+		}
+		if got := attrs["http.response.status_code"].AsInt64(); got != int64(status) {
+			t.Errorf("eoteltest: span for route %q has status %d, want %d", route, got, status)
+		}
+		return s
+	}
+	t.Errorf("eoteltest: no server span for route %q; recorded: %v", route, seen)
+	return nil
+}
+
+func attrMap(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+// AssertLogged checks that an entry at level ("debug", "info", "warn",
+// "error") whose message contains msg was logged.
+func AssertLogged(t testing.TB, rec *Recorder, level, msg string) observer.LoggedEntry {
+	t.Helper()
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		t.Fatalf("eoteltest: %v", err)
+	}
+	var seen []string
+	for _, e := range rec.Logged() {
+		if e.Level == lvl && strings.Contains(e.Message, msg) {
+			return e
+		}
+		seen = append(seen, fmt.Sprintf("[%s] %s", e.Level, e.Message))
+	}
+	t.Errorf("eoteltest: no %s entry containing %q; logged: %v", level, msg, seen)
+	return observer.LoggedEntry{}
+}
+
+// AssertSpan checks that a span called name was ended and returns it.
+func AssertSpan(t testing.TB, rec *Recorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	var seen []string
+	for _, s := range rec.Ended() {
+		if s.Name() == name {
+			return s
+		}
+		seen = append(seen, s.Name())
+	}
+	t.Errorf("eoteltest: no span %q; recorded: %v", name, seen)
+	return nil
+}
+
+// SpanAttr returns the value of the attribute key on s.
+func SpanAttr(s sdktrace.ReadOnlySpan, key string) attribute.Value {
+	return attrMap(s.Attributes())[attribute.Key(key)]
+}
@@ -0,0 +1,122 @@
+package eoteltest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+// recordingT captures assertion failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func serve(r *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
+
+func TestGinEngineRecordsServerSpan(t *testing.T) {
+	r, rec := eoteltest.NewGinEngine(t)
+	r.GET("/orders/:id", func(c *gin.Context) {
+		child := eotel.FromGin(c, "handler").Child("load-order")
+		child.Info("loaded")
+		child.End()
+		c.Status(http.StatusCreated)
+	})
+
+	if code := serve(r, "/orders/7"); code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", code)
+	}
+
+	span := eoteltest.AssertHTTPSpan(t, rec, "/orders/:id", http.StatusCreated)
+	if span == nil {
+		return
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span kind = %v, want server", span.SpanKind())
+	}
+	if span.Status().Code == codes.Error {
+		t.Errorf("span status = %v, want not Error", span.Status())
+	}
+	if got := eoteltest.SpanAttr(span, "http.route").AsString(); got != "/orders/:id" {
+		t.Errorf("http.route = %q, want /orders/:id", got)
+	}
+	child := eoteltest.AssertSpan(t, rec, "load-order")
+	if child != nil && child.Parent().SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("child parent = %v, want the server span", child.Parent().SpanID())
+	}
+	eoteltest.AssertLogged(t, rec, "info", "loaded")
+}
+
+func TestGinEngineServerErrorStatus(t *testing.T) {
+	r, rec := eoteltest.NewGinEngine(t)
+	r.GET("/broken", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+	serve(r, "/broken")
+	serve(r, "/missing")
+
+	if span := eoteltest.AssertHTTPSpan(t, rec, "/broken", http.StatusInternalServerError); span != nil && span.Status().Code != codes.Error {
+		t.Errorf("5xx span status = %v, want Error", span.Status())
+	}
+	// Client errors are the caller's fault and leave the server span unset.
+	if span := eoteltest.AssertHTTPSpan(t, rec, "/missing", http.StatusNotFound); span != nil && span.Status().Code == codes.Error {
+		t.Errorf("4xx span status = %v, want not Error", span.Status())
+	}
+}
+
+func TestGinEngineRecordsRequestMetric(t *testing.T) {
+	r, rec := eoteltest.NewGinEngine(t)
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve(r, "/ping")
+	serve(r, "/ping")
+
+	var total int64
+	for _, sm := range rec.Metrics(t).ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "http_server_requests_total" {
+				for _, dp := range sum.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	if total != 2 {
+		t.Errorf("http_server_requests_total = %d, want 2", total)
+	}
+}
+
+func TestAssertHTTPSpanReportsMismatch(t *testing.T) {
+	r, rec := eoteltest.NewGinEngine(t)
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve(r, "/ok")
+
+	wrongStatus := &recordingT{TB: t}
+	eoteltest.AssertHTTPSpan(wrongStatus, rec, "/ok", http.StatusTeapot)
+	if len(wrongStatus.errors) != 1 {
+		t.Errorf("wrong status reported %v, want one error", wrongStatus.errors)
+	}
+	missing := &recordingT{TB: t}
+	if span := eoteltest.AssertHTTPSpan(missing, rec, "/nope", http.StatusOK); span != nil || len(missing.errors) != 1 {
+		t.Errorf("missing route returned %v and reported %v, want nil and one error", span, missing.errors)
+	}
+}
@@ -0,0 +1,574 @@
+// Code generated by internal/genfacade; DO NOT EDIT.
+
+package eotel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	core "github.com/nicedev97/eotel-v2/eotel"
+)
+
+type (
+	AuditFields             = core.AuditFields
+	AuditRecord             = core.AuditRecord
+	BodyCapture             = core.BodyCapture
+	Breadcrumb              = core.Breadcrumb
+	CLI                     = core.CLI
+	Capture                 = core.Capture
+	CapturedLog             = core.CapturedLog
+	CapturedMetric          = core.CapturedMetric
+	CapturedSpan            = core.CapturedSpan
+	ChildOption             = core.ChildOption
+	CircuitBreaker          = core.CircuitBreaker
+	CodedError              = core.CodedError
+	Config                  = core.Config
+	ConfigChange            = core.ConfigChange
+	ContextExporter         = core.ContextExporter
+	ContextSender           = core.ContextSender
+	Counter                 = core.Counter
+	DiskBuffer              = core.DiskBuffer
+	Entry                   = core.Entry
+	Eotel                   = core.Eotel
+	ErrorMatcher            = core.ErrorMatcher
+	ExportQueue             = core.ExportQueue
+	Exporter                = core.Exporter
+	ExporterOption          = core.ExporterOption
+	ExporterTimeouts        = core.ExporterTimeouts
+	Field                   = core.Field
+	FieldProvider           = core.FieldProvider
+	FieldSender             = core.FieldSender
+	FieldValue              = core.FieldValue
+	Gauge                   = core.Gauge
+	HealthReport            = core.HealthReport
+	Histogram               = core.Histogram
+	InitResult              = core.InitResult
+	InstrumentConflictError = core.InstrumentConflictError
+	KeySanitizer            = core.KeySanitizer
+	LogOutput               = core.LogOutput
+	LogSampling             = core.LogSampling
+	LoggerConfig            = core.LoggerConfig
+	LokiEntry               = core.LokiEntry
+	LokiExporter            = core.LokiExporter
+	MiddlewareOption        = core.MiddlewareOption
+	OTLPRetry               = core.OTLPRetry
+	Objective               = core.Objective
+	PipelineHealth          = core.PipelineHealth
+	PrometheusBackend       = core.PrometheusBackend
+	Provider                = core.Provider
+	Record                  = core.Record
+	Redaction               = core.Redaction
+	RetryPolicy             = core.RetryPolicy
+	RotationConfig          = core.RotationConfig
+	RouterContext           = core.RouterContext
+	RouterError             = core.RouterError
+	SLOOption               = core.SLOOption
+	Scanner                 = core.Scanner
+	Scope                   = core.Scope
+	ScopeOption             = core.ScopeOption
+	SentryBackend           = core.SentryBackend
+	SentryEvent             = core.SentryEvent
+	SentryExporter          = core.SentryExporter
+	SentryFingerprintRule   = core.SentryFingerprintRule
+	SentryOptions           = core.SentryOptions
+	ShortSpans              = core.ShortSpans
+	Signal                  = core.Signal
+	Snapshot                = core.Snapshot
+	SpanLevelPolicy         = core.SpanLevelPolicy
+	SpanLimits              = core.SpanLimits
+	Subsystem               = core.Subsystem
+	SubsystemError          = core.SubsystemError
+	Summary                 = core.Summary
+	TLSConfig               = core.TLSConfig
+	TenantRoute             = core.TenantRoute
+	TenantRouter            = core.TenantRouter
+	TenantRoutes            = core.TenantRoutes
+	Timer                   = core.Timer
+	TimerOption             = core.TimerOption
+	UsageReport             = core.UsageReport
+)
+
+const (
+	BackpressureBlock         = core.BackpressureBlock
+	BackpressureDropNewest    = core.BackpressureDropNewest
+	BackpressureDropOldest    = core.BackpressureDropOldest
+	CardNumberPattern         = core.CardNumberPattern
+	ErrorKindCanceled         = core.ErrorKindCanceled
+	ErrorKindConflict         = core.ErrorKindConflict
+	ErrorKindInternal         = core.ErrorKindInternal
+	ErrorKindInvalidArgument  = core.ErrorKindInvalidArgument
+	ErrorKindNetwork          = core.ErrorKindNetwork
+	ErrorKindNotFound         = core.ErrorKindNotFound
+	ErrorKindPermissionDenied = core.ErrorKindPermissionDenied
+	ErrorKindRateLimited      = core.ErrorKindRateLimited
+	ErrorKindTimeout          = core.ErrorKindTimeout
+	ErrorKindUnauthenticated  = core.ErrorKindUnauthenticated
+	ErrorKindUnavailable      = core.ErrorKindUnavailable
+	ExemplarsAlwaysOff        = core.ExemplarsAlwaysOff
+	ExemplarsAlwaysOn         = core.ExemplarsAlwaysOn
+	ExemplarsTraceBased       = core.ExemplarsTraceBased
+	FatalExit                 = core.FatalExit
+	FatalLog                  = core.FatalLog
+	FatalPanic                = core.FatalPanic
+	LogOutputLoki             = core.LogOutputLoki
+	LogOutputOTLP             = core.LogOutputOTLP
+	QueueChannel              = core.QueueChannel
+	QueueDisk                 = core.QueueDisk
+	QueueRing                 = core.QueueRing
+	ShortSpanDrop             = core.ShortSpanDrop
+	ShortSpanMerge            = core.ShortSpanMerge
+	SignalLoki                = core.SignalLoki
+	SignalMetrics             = core.SignalMetrics
+	SignalOTLPLogs            = core.SignalOTLPLogs
+	SignalSentry              = core.SignalSentry
+	SignalTracing             = core.SignalTracing
+	SubsystemCollector        = core.SubsystemCollector
+	SubsystemConfig           = core.SubsystemConfig
+	SubsystemLogger           = core.SubsystemLogger
+	SubsystemLoki             = core.SubsystemLoki
+	SubsystemMetrics          = core.SubsystemMetrics
+	SubsystemOTLPLogs         = core.SubsystemOTLPLogs
+	SubsystemRuntimeMetrics   = core.SubsystemRuntimeMetrics
+	SubsystemSentry           = core.SubsystemSentry
+	SubsystemTracing          = core.SubsystemTracing
+	TraceFileSuffix           = core.TraceFileSuffix
+)
+
+var (
+	DefaultCorrelatedMetricAttributes = core.DefaultCorrelatedMetricAttributes
+	DefaultPromotedBaggage            = core.DefaultPromotedBaggage
+	DefaultRedactKeys                 = core.DefaultRedactKeys
+	ErrAuditIncomplete                = core.ErrAuditIncomplete
+	ErrAuditUndelivered               = core.ErrAuditUndelivered
+)
+
+func WithAccessLog(level string) MiddlewareOption {
+	return core.WithAccessLog(level)
+}
+
+func Aggregated(n int) ChildOption {
+	return core.Aggregated(n)
+}
+
+func WithBodyCapture(bc BodyCapture) MiddlewareOption {
+	return core.WithBodyCapture(bc)
+}
+
+func SetStrictInit(strict bool) {
+	core.SetStrictInit(strict)
+}
+
+func LoadCapture(path string) (*Capture, error) {
+	return core.LoadCapture(path)
+}
+
+func WithSpanKind(kind trace.SpanKind) ChildOption {
+	return core.WithSpanKind(kind)
+}
+
+func WithSpanAttributes(attrs ...attribute.KeyValue) ChildOption {
+	return core.WithSpanAttributes(attrs...)
+}
+
+func InitCLI(name string) (*CLI, error) {
+	return core.InitCLI(name)
+}
+
+func LoadConfig() (Config, error) {
+	return core.LoadConfig()
+}
+
+func LoadConfigFile(path string) (Config, error) {
+	return core.LoadConfigFile(path)
+}
+
+func DiffConfig(old, new Config) []ConfigChange {
+	return core.DiffConfig(old, new)
+}
+
+func ExportDashboards(w io.Writer) error {
+	return core.ExportDashboards(w)
+}
+
+func DevUIHandler() http.Handler {
+	return core.DevUIHandler()
+}
+
+func WriteDiagnostics(w io.Writer) error {
+	return core.WriteDiagnostics(w)
+}
+
+func Drain(ctx context.Context) error {
+	return core.Drain(ctx)
+}
+
+func RegisterHook(h func(ctx context.Context, e *Entry)) {
+	core.RegisterHook(h)
+}
+
+func New(ctx context.Context, name string) *Eotel {
+	return core.New(ctx, name)
+}
+
+func Inject(ctx context.Context, logger *Eotel) context.Context {
+	return core.Inject(ctx, logger)
+}
+
+func FromContext(ctx context.Context, name string) *Eotel {
+	return core.FromContext(ctx, name)
+}
+
+func Safe(l *Eotel) *Eotel {
+	return core.Safe(l)
+}
+
+func Noop(name string) *Eotel {
+	return core.Noop(name)
+}
+
+func RegisterErrorMatcher(m ErrorMatcher) {
+	core.RegisterErrorMatcher(m)
+}
+
+func ErrorKind(err error) string {
+	return core.ErrorKind(err)
+}
+
+func NewCodedError(code string, status int, message string) *CodedError {
+	return core.NewCodedError(code, status, message)
+}
+
+func AsCodedError(err error) (*CodedError, bool) {
+	return core.AsCodedError(err)
+}
+
+func WithExporterLevel(level string) ExporterOption {
+	return core.WithExporterLevel(level)
+}
+
+func WithExporterTimeout(d time.Duration) ExporterOption {
+	return core.WithExporterTimeout(d)
+}
+
+func RegisterExporter(exp Exporter, opts ...ExporterOption) {
+	core.RegisterExporter(exp, opts...)
+}
+
+func RegisterFieldProvider(p FieldProvider) {
+	core.RegisterFieldProvider(p)
+}
+
+func F[T FieldValue](key string, v T) Field {
+	return core.F[T](key, v)
+}
+
+func ErrField(key string, err error) Field {
+	return core.ErrField(key, err)
+}
+
+func StringerField(key string, v fmt.Stringer) Field {
+	return core.StringerField(key, v)
+}
+
+func Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	core.Debug(ctx, msg, fields...)
+}
+
+func Info(ctx context.Context, msg string, fields ...zap.Field) {
+	core.Info(ctx, msg, fields...)
+}
+
+func Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	core.Warn(ctx, msg, fields...)
+}
+
+func Error(ctx context.Context, err error, msg string, fields ...zap.Field) {
+	core.Error(ctx, err, msg, fields...)
+}
+
+func Health(ctx context.Context) HealthReport {
+	return core.Health(ctx)
+}
+
+func HTTPClient(base *http.Client) *http.Client {
+	return core.HTTPClient(base)
+}
+
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return core.Transport(base)
+}
+
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return core.HTTPMiddleware(next)
+}
+
+func NewHTTPMiddleware(name string) func(http.Handler) http.Handler {
+	return core.NewHTTPMiddleware(name)
+}
+
+func TracerProvider() trace.TracerProvider {
+	return core.TracerProvider()
+}
+
+func MeterProvider() metric.MeterProvider {
+	return core.MeterProvider()
+}
+
+func InitEOTEL(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	return core.InitEOTEL(ctx, cfg)
+}
+
+func InitWithResult(ctx context.Context, cfg Config) (func(context.Context) error, InitResult, error) {
+	return core.InitWithResult(ctx, cfg)
+}
+
+func Int64Counter(name, unit, description string) (metric.Int64Counter, error) {
+	return core.Int64Counter(name, unit, description)
+}
+
+func Float64Histogram(name, unit, description string) (metric.Float64Histogram, error) {
+	return core.Float64Histogram(name, unit, description)
+}
+
+func Float64Gauge(name, unit, description string) (metric.Float64Gauge, error) {
+	return core.Float64Gauge(name, unit, description)
+}
+
+func Enabled(signal Signal) bool {
+	return core.Enabled(signal)
+}
+
+func CurrentConfig() Config {
+	return core.CurrentConfig()
+}
+
+func Job(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	return core.Job(ctx, name, fn)
+}
+
+func JobFunc(name string, fn func(ctx context.Context) error) func() {
+	return core.JobFunc(name, fn)
+}
+
+func SetLevel(level string) error {
+	return core.SetLevel(level)
+}
+
+func Level() string {
+	return core.Level()
+}
+
+func LevelHandler() http.Handler {
+	return core.LevelHandler()
+}
+
+func PostShutdownLogs() int64 {
+	return core.PostShutdownLogs()
+}
+
+func NewWithRemoteParent(ctx context.Context, name string, carrier propagation.TextMapCarrier) *Eotel {
+	return core.NewWithRemoteParent(ctx, name, carrier)
+}
+
+func RemoteSpanContext(carrier propagation.TextMapCarrier) trace.SpanContext {
+	return core.RemoteSpanContext(carrier)
+}
+
+func RegisterLogRotator(newRotator func(path string, rot RotationConfig) io.Writer) {
+	core.RegisterLogRotator(newRotator)
+}
+
+func SendLokiAsync(level string, msg string, traceID string, spanID string) {
+	core.SendLokiAsync(level, msg, traceID, spanID)
+}
+
+func SendLokiFields(level string, msg string, traceID string, spanID string, fields map[string]any) {
+	core.SendLokiFields(level, msg, traceID, spanID, fields)
+}
+
+func WithSkipPaths(paths ...string) MiddlewareOption {
+	return core.WithSkipPaths(paths...)
+}
+
+func NewRouterMiddleware(name string, opts ...MiddlewareOption) func(rc RouterContext) {
+	return core.NewRouterMiddleware(name, opts...)
+}
+
+func RegisterPrometheusBackend(b PrometheusBackend) {
+	core.RegisterPrometheusBackend(b)
+}
+
+func MetricsHandler() http.Handler {
+	return core.MetricsHandler()
+}
+
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	return core.NewProvider(ctx, cfg)
+}
+
+func WithProxyServerSpan() MiddlewareOption {
+	return core.WithProxyServerSpan()
+}
+
+func PushMetrics(ctx context.Context) error {
+	return core.PushMetrics(ctx)
+}
+
+func Reload(cfg Config) error {
+	return core.Reload(cfg)
+}
+
+func ReloadOnSignal(path string, sigs ...os.Signal) (stop func()) {
+	return core.ReloadOnSignal(path, sigs...)
+}
+
+func WatchConfigFile(path string, interval time.Duration) (stop func()) {
+	return core.WatchConfigFile(path, interval)
+}
+
+func WithRequestScope(ctx context.Context) context.Context {
+	return core.WithRequestScope(ctx)
+}
+
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	return core.Retry(ctx, policy, fn)
+}
+
+func WithRouterSpanNameFunc(fn func(rc RouterContext) string) MiddlewareOption {
+	return core.WithRouterSpanNameFunc(fn)
+}
+
+func WithRouterFilter(fn func(rc RouterContext) bool) MiddlewareOption {
+	return core.WithRouterFilter(fn)
+}
+
+func WithRouterSession(extract func(rc RouterContext) string) MiddlewareOption {
+	return core.WithRouterSession(extract)
+}
+
+func WithSessionCookie(name string) MiddlewareOption {
+	return core.WithSessionCookie(name)
+}
+
+func RecoverRouter(rc RouterContext, rec any) error {
+	return core.RecoverRouter(rc, rec)
+}
+
+func WithSamplingPriority(ctx context.Context, probability float64) context.Context {
+	return core.WithSamplingPriority(ctx, probability)
+}
+
+func Scan(ctx context.Context, name string) *Scanner {
+	return core.Scan(ctx, name)
+}
+
+func WithScopeVersion(version string) ScopeOption {
+	return core.WithScopeVersion(version)
+}
+
+func WithScopeLevel(level zapcore.Level) ScopeOption {
+	return core.WithScopeLevel(level)
+}
+
+func WithScopeExporter(exp Exporter, opts ...ExporterOption) ScopeOption {
+	return core.WithScopeExporter(exp, opts...)
+}
+
+func NewScope(name string, opts ...ScopeOption) *Scope {
+	return core.NewScope(name, opts...)
+}
+
+func CaptureError(err error, tags map[string]string, extras map[string]interface{}) {
+	core.CaptureError(err, tags, extras)
+}
+
+func CaptureErrorContext(ctx context.Context, err error, tags map[string]string, extras map[string]any) {
+	core.CaptureErrorContext(ctx, err, tags, extras)
+}
+
+func RegisterSentryBackend(newBackend func(SentryOptions) (SentryBackend, error)) {
+	core.RegisterSentryBackend(newBackend)
+}
+
+func HashSessionID(id string) string {
+	return core.HashSessionID(id)
+}
+
+func SLOTarget(target float64) SLOOption {
+	return core.SLOTarget(target)
+}
+
+func SLOWindow(d time.Duration) SLOOption {
+	return core.SLOWindow(d)
+}
+
+func SLO(name string, threshold time.Duration, opts ...SLOOption) *Objective {
+	return core.SLO(name, threshold, opts...)
+}
+
+func WithGlobalFields(m map[string]any) {
+	core.WithGlobalFields(m)
+}
+
+func WithStreaming(heartbeat time.Duration) MiddlewareOption {
+	return core.WithStreaming(heartbeat)
+}
+
+func StreamSent(ctx context.Context) {
+	core.StreamSent(ctx)
+}
+
+func StreamReceived(ctx context.Context) {
+	core.StreamReceived(ctx)
+}
+
+func TimerSpan() TimerOption {
+	return core.TimerSpan()
+}
+
+func TimerEvents() TimerOption {
+	return core.TimerEvents()
+}
+
+func TraceMetadata(ctx context.Context) map[string]string {
+	return core.TraceMetadata(ctx)
+}
+
+func ContextFromMetadata(ctx context.Context, md map[string]string) context.Context {
+	return core.ContextFromMetadata(ctx, md)
+}
+
+func WriteTraceFile(ctx context.Context, path string) error {
+	return core.WriteTraceFile(ctx, path)
+}
+
+func ReadTraceFile(ctx context.Context, path string) (context.Context, error) {
+	return core.ReadTraceFile(ctx, path)
+}
+
+func WithTraceState(ctx context.Context, key, value string) context.Context {
+	return core.WithTraceState(ctx, key, value)
+}
+
+func TraceStateValue(ctx context.Context, key string) string {
+	return core.TraceStateValue(ctx, key)
+}
+
+func TraceStateValues(ctx context.Context) map[string]string {
+	return core.TraceStateValues(ctx)
+}
+
+func Usage() UsageReport {
+	return core.Usage()
+}
@@ -0,0 +1,90 @@
+package eotel
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	core "github.com/nicedev97/eotel-v2/eotel"
+	"github.com/nicedev97/eotel-v2/eotelgin"
+)
+
+// Middleware instruments a Gin router; see NewRouterMiddleware.
+func Middleware(name string, opts ...MiddlewareOption) gin.HandlerFunc {
+	serve := core.NewRouterMiddleware(name, opts...)
+	return func(c *gin.Context) {
+		serve(eotelgin.RouterContext(c))
+	}
+}
+
+// WithSpanNameFunc names the request span; the default is the method and
+// the matched route.
+func WithSpanNameFunc(fn func(c *gin.Context) string) MiddlewareOption {
+	return core.WithRouterSpanNameFunc(func(rc RouterContext) string {
+		return fn(eotelgin.GinContext(rc))
+	})
+}
+
+// WithFilter adds a predicate; requests are instrumented only when every
+// filter returns true.
+func WithFilter(fn func(c *gin.Context) bool) MiddlewareOption {
+	return core.WithRouterFilter(func(rc RouterContext) bool {
+		return fn(eotelgin.GinContext(rc))
+	})
+}
+
+// WithSession is WithRouterSession for Gin handlers.
+func WithSession(extract func(c *gin.Context) string) MiddlewareOption {
+	return core.WithRouterSession(func(rc RouterContext) string {
+		return extract(eotelgin.GinContext(rc))
+	})
+}
+
+func FromGin(c *gin.Context, name string) *Eotel {
+	return core.FromContext(c.Request.Context(), name)
+}
+
+func RecoverPanic(c *gin.Context) func() {
+	return func() {
+		if rec := recover(); rec != nil {
+			core.RecoverRouter(eotelgin.RouterContext(c), rec)
+		}
+	}
+}
+
+func RenderError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	body := gin.H{"error": "internal server error"}
+
+	if ce, ok := core.AsCodedError(err); ok {
+		if ce.Status != 0 {
+			status = ce.Status
+		}
+		body = gin.H{"error": ce.Message, "code": ce.Code}
+	}
+
+	log := core.Safe(FromGin(c, "error")).WithError(err)
+	if traceID := log.Summary().TraceID; traceID != "" {
+		body["trace_id"] = traceID
+	}
+
+	c.AbortWithStatusJSON(status, body)
+}
+
+// HealthHandler serves Health as JSON, with status 503 when a pipeline is
+// unhealthy.
+func HealthHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := core.Health(c.Request.Context())
+		status := http.StatusOK
+		if !report.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
+
+// GinLevelHandler is LevelHandler for Gin routers.
+func GinLevelHandler() gin.HandlerFunc {
+	return gin.WrapH(core.LevelHandler())
+}
@@ -0,0 +1,37 @@
+package eotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	eotel "github.com/nicedev97/eotel-v2"
+)
+
+func TestPackageLoggingWithoutRequestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	shutdown, err := eotel.InitEOTEL(context.Background(), eotel.Config{ServiceName: "test", Logger: zap.New(core)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+	ctx := context.Background()
+
+	eotel.Info(ctx, "job started", zap.String("job", "reindex"))
+	eotel.Error(ctx, errors.New("disk full"), "job failed")
+
+	started := logs.FilterMessage("job started").All()
+	if len(started) != 1 {
+		t.Fatalf("got %d \"job started\" entries, want 1", len(started))
+	}
+	if got := started[0].ContextMap()["job"]; got != "reindex" {
+		t.Errorf("job = %v, want the entry's field", got)
+	}
+	if n := logs.FilterMessage("job failed").FilterField(zap.Error(errors.New("disk full"))).Len(); n != 1 {
+		t.Errorf("got %d \"job failed\" entries with the error, want 1", n)
+	}
+}
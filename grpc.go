@@ -0,0 +1,27 @@
+package eotel
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/nicedev97/eotel-v2/eotelgrpc"
+)
+
+// UnaryServerInterceptor instruments unary RPCs; see eotelgrpc.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return eotelgrpc.UnaryServerInterceptor()
+}
+
+// StreamServerInterceptor instruments streaming RPCs; see eotelgrpc.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return eotelgrpc.StreamServerInterceptor()
+}
+
+// UnaryClientInterceptor instruments outgoing unary RPCs; see eotelgrpc.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return eotelgrpc.UnaryClientInterceptor()
+}
+
+// StreamClientInterceptor instruments outgoing streams; see eotelgrpc.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return eotelgrpc.StreamClientInterceptor()
+}
@@ -0,0 +1,78 @@
+package eotel_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eotelgin"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+func TestInjectedTracerProvider(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+
+	_, span := eotel.TracerProvider().Tracer("integration").Start(context.Background(), "call")
+	span.End()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(eotelgin.Middleware("svc"))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	var names []string
+	for _, s := range rec.Ended() {
+		names = append(names, s.Name())
+	}
+	if len(names) < 2 || names[0] != "call" || names[1] != "/ping" {
+		t.Errorf("spans = %v, want call and /ping on the injected provider", names)
+	}
+}
+
+func TestInitFailureRollsBack(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+	level := eotel.Level()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	_, err = eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:          "broken",
+		MinLevel:             "error",
+		EnableMetrics:        true,
+		MetricsExporter:      "prometheus",
+		PrometheusListenAddr: addr,
+		EnableSentry:         true,
+		SentryDSN:            "not a dsn",
+		StrictInit:           true,
+	})
+	var serr *eotel.SubsystemError
+	if !errors.As(err, &serr) || serr.Subsystem != eotel.SubsystemSentry {
+		t.Fatalf("err = %v, want a sentry SubsystemError", err)
+	}
+
+	// The Prometheus server started before Sentry failed must be stopped.
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("prometheus listener still open: %v", err)
+	}
+	_ = ln.Close()
+
+	// The configuration from NewRecorder must still be in place.
+	if got := eotel.Level(); got != level {
+		t.Errorf("level = %s after failed init, want %s", got, level)
+	}
+	eotel.New(context.Background(), "after").Info("still recorded")
+	eoteltest.AssertLogged(t, rec, "info", "still recorded")
+}
@@ -0,0 +1,288 @@
+// Command genfacade writes facade.go, the aliases and forwarders through
+// which the root package re-exports the core package. Run it with go
+// generate from the repository root after changing the core's exported API.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	coreDir    = "eotel"
+	corePath   = "github.com/nicedev97/eotel-v2/eotel"
+	outputFile = "facade.go"
+)
+
+type generator struct {
+	fset    *token.FileSet
+	imports map[string]string // name in the core sources -> import path
+	used    map[string]bool
+	types   []string
+	consts  []string
+	vars    []string
+	funcs   []string
+}
+
+func main() {
+	g := &generator{fset: token.NewFileSet(), imports: map[string]string{}, used: map[string]bool{}}
+	files, err := filepath.Glob(filepath.Join(coreDir, "*.go"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(g.fset, name, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !buildsEverywhere(f) {
+			continue
+		}
+		g.file(f)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/genfacade; DO NOT EDIT.\n\npackage eotel\n\nimport (\n")
+	var std, other []string
+	for name := range g.used {
+		p := g.imports[name]
+		spec := strconv.Quote(p)
+		if defaultName(p) != name {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.SplitN(p, "/", 2)[0], ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, group := range [][]string{std, other, {"core " + strconv.Quote(corePath)}} {
+		for _, spec := range group {
+			buf.WriteString("\t" + spec + "\n")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(")\n")
+	block(&buf, "type", g.types)
+	block(&buf, "const", g.consts)
+	block(&buf, "var", g.vars)
+	for _, fn := range g.funcs {
+		buf.WriteString("\n" + fn + "\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile(outputFile, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// buildsEverywhere reports whether f has no build constraint; the exported
+// API must not depend on the platform.
+func buildsEverywhere(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if strings.HasPrefix(c.Text, "//go:build") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (g *generator) file(f *ast.File) {
+	local := map[string]string{}
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := defaultName(p)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		local[name] = p
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.IsExported() {
+				g.funcs = append(g.funcs, g.forward(d, local))
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						g.types = append(g.types, g.alias(s, local))
+					}
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if !n.IsExported() {
+							continue
+						}
+						line := n.Name + " = core." + n.Name
+						if d.Tok == token.CONST {
+							g.consts = append(g.consts, line)
+						} else {
+							g.vars = append(g.vars, line)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (g *generator) alias(s *ast.TypeSpec, local map[string]string) string {
+	if s.TypeParams == nil {
+		return s.Name.Name + " = core." + s.Name.Name
+	}
+	return s.Name.Name + "[" + g.fieldList(s.TypeParams, local) + "] = core." +
+		s.Name.Name + "[" + strings.Join(fieldNames(s.TypeParams), ", ") + "]"
+}
+
+func (g *generator) forward(d *ast.FuncDecl, local map[string]string) string {
+	ft := d.Type
+	var args []string
+	if ft.Params != nil {
+		for i, f := range ft.Params.List {
+			if len(f.Names) == 0 {
+				f.Names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("p%d", i))}
+			}
+			for j, n := range f.Names {
+				if n.Name == "_" {
+					f.Names[j] = ast.NewIdent(fmt.Sprintf("p%d_%d", i, j))
+				}
+				arg := f.Names[j].Name
+				if _, ok := f.Type.(*ast.Ellipsis); ok {
+					arg += "..."
+				}
+				args = append(args, arg)
+			}
+		}
+	}
+
+	var sig strings.Builder
+	sig.WriteString("func " + d.Name.Name)
+	callee := "core." + d.Name.Name
+	if ft.TypeParams != nil {
+		sig.WriteString("[" + g.fieldList(ft.TypeParams, local) + "]")
+		callee += "[" + strings.Join(fieldNames(ft.TypeParams), ", ") + "]"
+	}
+	sig.WriteString("(" + g.fieldList(ft.Params, local) + ")")
+	if ft.Results != nil {
+		res := g.fieldList(ft.Results, local)
+		if len(ft.Results.List) > 1 || len(ft.Results.List[0].Names) > 0 {
+			res = "(" + res + ")"
+		}
+		sig.WriteString(" " + res)
+	}
+	call := callee + "(" + strings.Join(args, ", ") + ")"
+	if ft.Results != nil {
+		call = "return " + call
+	}
+	return sig.String() + " {\n\t" + call + "\n}"
+}
+
+func (g *generator) fieldList(fl *ast.FieldList, local map[string]string) string {
+	if fl == nil {
+		return ""
+	}
+	var parts []string
+	for _, f := range fl.List {
+		typ := g.expr(f.Type, local)
+		if len(f.Names) == 0 {
+			parts = append(parts, typ)
+			continue
+		}
+		var names []string
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+typ)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func fieldNames(fl *ast.FieldList) []string {
+	var names []string
+	for _, f := range fl.List {
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
+
+// expr prints e, recording the imports its qualified identifiers need.
+func (g *generator) expr(e ast.Expr, local map[string]string) string {
+	ast.Inspect(e, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			p, ok := local[x.Name]
+			if !ok {
+				log.Fatalf("%s: unknown package %s", g.fset.Position(x.Pos()), x.Name)
+			}
+			if prev, ok := g.imports[x.Name]; ok && prev != p {
+				log.Fatalf("%s: %s names both %s and %s", g.fset.Position(x.Pos()), x.Name, prev, p)
+			}
+			g.imports[x.Name] = p
+			g.used[x.Name] = true
+		}
+		return false
+	})
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, g.fset, e); err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
+
+// defaultName is the package name an unnamed import of p is expected to
+// bind: the last path element without a gopkg.in or major version suffix.
+func defaultName(p string) string {
+	name := path.Base(p)
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(p))
+	}
+	return name
+}
+
+func block(buf *bytes.Buffer, tok string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	sort.Strings(lines)
+	buf.WriteString("\n" + tok + " (\n")
+	for _, l := range lines {
+		buf.WriteString("\t" + l + "\n")
+	}
+	buf.WriteString(")\n")
+}
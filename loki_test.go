@@ -0,0 +1,81 @@
+package eotel_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	eotel "github.com/nicedev97/eotel-v2"
+)
+
+// lokiStream is one stream of a Loki push request.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func TestLokiLabelFromLogField(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		streams []lokiStream
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("push body: %v", err)
+			return
+		}
+		var push struct {
+			Streams []lokiStream `json:"streams"`
+		}
+		if err := json.NewDecoder(zr).Decode(&push); err != nil {
+			t.Errorf("push body: %v", err)
+			return
+		}
+		mu.Lock()
+		streams = append(streams, push.Streams...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	shutdown, err := eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:     "loki",
+		EnableLoki:      true,
+		LokiURL:         srv.URL,
+		LokiLabelFields: []string{"tenant"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eotel.New(context.Background(), "handler").
+		WithField("tenant", "acme").
+		WithField("order", 42).
+		Info("order placed")
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range streams {
+		for _, v := range s.Values {
+			if !strings.Contains(v[1], "order placed") {
+				continue
+			}
+			if s.Stream["tenant"] != "acme" {
+				t.Errorf("labels = %v, want tenant=acme", s.Stream)
+			}
+			if !strings.Contains(v[1], `"order":42`) {
+				t.Errorf("line = %s, want the order field", v[1])
+			}
+			return
+		}
+	}
+	t.Fatalf("entry not pushed; got %v", streams)
+}
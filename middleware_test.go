@@ -0,0 +1,319 @@
+package eotel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+func TestMiddlewareSpan(t *testing.T) {
+	r, rec := eoteltest.NewGinEngine(t)
+	r.GET("/users/:id", func(c *gin.Context) {
+		eotel.FromGin(c, "handler").Info("loading user")
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	span := eoteltest.AssertHTTPSpan(t, rec, "/users/:id", http.StatusOK)
+	if span != nil && span.Status().Code == codes.Error {
+		t.Errorf("span status = %v, want not Error", span.Status())
+	}
+	eoteltest.AssertLogged(t, rec, "info", "loading user")
+	eoteltest.AssertLogged(t, rec, "info", "request completed")
+}
+
+func TestMiddlewareServerError(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(eotel.Middleware("api"))
+	r.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(errors.New("db down"))
+		c.Status(http.StatusServiceUnavailable)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want the server span", len(spans))
+	}
+	span := spans[0]
+	var status int64
+	for _, kv := range span.Attributes() {
+		if kv.Key == "http.response.status_code" {
+			status = kv.Value.AsInt64()
+		}
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("status attribute = %d, want 503", status)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want Error", span.Status())
+	}
+	if len(span.Events()) == 0 || span.Events()[0].Name != "exception" {
+		t.Errorf("span events = %v, want the handler error", span.Events())
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	r, rec := eoteltest.NewGinEngine(t)
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	span := eoteltest.AssertHTTPSpan(t, rec, "/panic", http.StatusInternalServerError)
+	if span == nil {
+		return
+	}
+	if span.Status().Code != codes.Error || span.Status().Description != "panic: boom" {
+		t.Errorf("span status = %v, want Error with the panic", span.Status())
+	}
+	eoteltest.AssertLogged(t, rec, "error", "unhandled panic")
+}
+
+func TestHTTPMiddlewarePanic(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	h := eotel.HTTPMiddleware(mux)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	span := eoteltest.AssertHTTPSpan(t, rec, "GET /panic", http.StatusInternalServerError)
+	if span != nil && span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want Error", span.Status())
+	}
+}
+
+func TestMiddlewareFlushesAggregatedChildren(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		panic bool
+		spans int
+	}{
+		// The server span, one child and one aggregate.
+		{name: "completed", spans: 3},
+		{name: "panicked", panic: true, spans: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := eoteltest.NewRecorder(t)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(eotel.Middleware("api"))
+			r.GET("/batch", func(c *gin.Context) {
+				log := eotel.FromGin(c, "handler")
+				for i := 0; i < 3; i++ {
+					log.Child("query", eotel.Aggregated(1)).End()
+				}
+				if tc.panic {
+					panic("boom")
+				}
+				c.Status(http.StatusOK)
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/batch", nil))
+
+			spans := rec.Ended()
+			if len(spans) != tc.spans {
+				t.Fatalf("got %d spans, want %d", len(spans), tc.spans)
+			}
+			var aggregates int
+			for _, s := range spans {
+				for _, kv := range s.Attributes() {
+					if kv.Key == "aggregated.count" && kv.Value.AsInt64() == 2 {
+						aggregates++
+					}
+				}
+			}
+			if aggregates != 1 {
+				t.Errorf("got %d aggregate spans counting 2 children, want 1", aggregates)
+			}
+		})
+	}
+}
+
+func TestAggregatedChildrenFromCopies(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+	log := eotel.New(context.Background(), "parent")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.WithField("worker", i).Child("query", eotel.Aggregated(1)).End()
+		}()
+	}
+	wg.Wait()
+	log.End()
+
+	var real, counted int64
+	for _, s := range rec.Ended() {
+		if s.Name() != "query" {
+			continue
+		}
+		real++
+		for _, kv := range s.Attributes() {
+			if kv.Key == "aggregated.count" {
+				real--
+				counted = kv.Value.AsInt64()
+			}
+		}
+	}
+	if real != 1 || counted != 7 {
+		t.Errorf("got %d real children and an aggregate of %d, want 1 and 7", real, counted)
+	}
+}
+
+func TestHTTPMiddlewareUnmatchedSpanName(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+	h := eotel.HTTPMiddleware(http.NewServeMux())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	spans := rec.Ended()
+	if len(spans) != 1 || spans[0].Name() != http.MethodGet {
+		for _, s := range spans {
+			t.Errorf("span %q", s.Name())
+		}
+		t.Fatal("want a single span named after the method")
+	}
+}
+
+func TestHTTPMiddlewareFlushAndHijack(t *testing.T) {
+	eoteltest.NewRecorder(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream", func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Error("writer is not an http.Flusher")
+			return
+		}
+		_, _ = w.Write([]byte("chunk"))
+		f.Flush()
+	})
+	mux.HandleFunc("GET /upgrade", func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("writer is not an http.Hijacker")
+			return
+		}
+		conn, buf, err := hj.Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		_ = buf.Flush()
+	})
+	// Closed once the middleware is done with the hijacked request, so its
+	// logging does not outlive the test.
+	served := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(served)
+		eotel.HTTPMiddleware(mux).ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	w := httptest.NewRecorder()
+	eotel.HTTPMiddleware(mux).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	if !w.Flushed {
+		t.Error("flush did not reach the underlying writer")
+	}
+
+	resp, err := http.Get(srv.URL + "/upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	<-served
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("status = %d, want 101 from the hijacked connection", resp.StatusCode)
+	}
+}
+
+// sentLogs records the messages sent to it.
+type sentLogs struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (s *sentLogs) Send(level, msg, traceID, spanID string) {
+	s.mu.Lock()
+	s.msgs = append(s.msgs, msg)
+	s.mu.Unlock()
+}
+
+func (s *sentLogs) CaptureError(error, map[string]string, map[string]any) {}
+
+func (s *sentLogs) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.msgs...)
+}
+
+func TestTraceAwareLogsWithTracingOff(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sent := &sentLogs{}
+	shutdown, err := eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:    "untraced",
+		TraceAwareLogs: true,
+		TracerProvider: tracenoop.NewTracerProvider(),
+		Exporters:      []eotel.Exporter{sent},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+
+	r := gin.New()
+	r.Use(eotel.Middleware("untraced"))
+	r.GET("/gin", func(c *gin.Context) {
+		eotel.FromGin(c, "handler").Info("from gin")
+	})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /http", func(w http.ResponseWriter, r *http.Request) {
+		eotel.FromContext(r.Context(), "handler").Info("from net/http")
+	})
+	h := eotel.HTTPMiddleware(mux)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/gin", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/http", nil))
+	eotel.New(context.Background(), "job").Info("outside a request")
+
+	want := []string{"from gin", "request completed", "from net/http", "request completed", "outside a request"}
+	if got := sent.sent(); !slices.Equal(got, want) {
+		t.Errorf("exported %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package eotel_test
+
+import (
+	"context"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+func newTestProvider(t *testing.T, service string) (*eotel.Provider, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	p, err := eotel.NewProvider(context.Background(), eotel.Config{ServiceName: service, MeterProvider: mp})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = p.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+	})
+	return p, reader
+}
+
+// counterSum returns the total of the int64 sum called name.
+func counterSum(t *testing.T, rm metricdata.ResourceMetrics, name string) int64 {
+	t.Helper()
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("%s is %T, want a sum", name, m.Data)
+			}
+			for _, dp := range sum.DataPoints {
+				total += dp.Value
+			}
+		}
+	}
+	return total
+}
+
+func collect(t *testing.T, r *sdkmetric.ManualReader) metricdata.ResourceMetrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := r.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	return rm
+}
+
+func TestProviderMetricsIsolated(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+	a, readerA := newTestProvider(t, "a")
+	b, readerB := newTestProvider(t, "b")
+
+	a.New(context.Background(), "a").Count("orders_total", 1)
+	b.New(context.Background(), "b").Count("orders_total", 2)
+	b.New(context.Background(), "b").Child("step").Count("orders_total", 2)
+	eotel.New(context.Background(), "global").Count("orders_total", 8)
+
+	if got := counterSum(t, collect(t, readerA), "orders_total"); got != 1 {
+		t.Errorf("provider a counted %d, want 1", got)
+	}
+	if got := counterSum(t, collect(t, readerB), "orders_total"); got != 4 {
+		t.Errorf("provider b counted %d, want 4", got)
+	}
+	if got := counterSum(t, rec.Metrics(t), "orders_total"); got != 8 {
+		t.Errorf("global meter counted %d, want 8", got)
+	}
+}
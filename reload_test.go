@@ -0,0 +1,55 @@
+package eotel_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+// Run with -race: Reload swaps the configuration under loggers in use.
+func TestReloadWhileLogging(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					eotel.New(context.Background(), "worker").Info("working")
+				}
+			}
+		}()
+	}
+	for i := range 50 {
+		level := "debug"
+		if i%2 == 1 {
+			level = "info"
+		}
+		if err := eotel.Reload(eotel.Config{ServiceName: "eoteltest", MinLevel: level}); err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if err := eotel.Reload(eotel.Config{ServiceName: "eoteltest", MinLevel: "warn"}); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	eotel.New(context.Background(), "worker").Info("filtered")
+	eotel.New(context.Background(), "worker").Warn("kept")
+	for _, e := range rec.Logged() {
+		if e.Message == "filtered" {
+			t.Error("info entry logged after reloading with min level warn")
+		}
+	}
+	eoteltest.AssertLogged(t, rec, "warn", "kept")
+}
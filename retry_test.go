@@ -0,0 +1,59 @@
+package eotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	eotel "github.com/nicedev97/eotel-v2"
+	"github.com/nicedev97/eotel-v2/eoteltest"
+)
+
+func TestRetryCancelled(t *testing.T) {
+	rec := eoteltest.NewRecorder(t)
+
+	ctx, span := eotel.TracerProvider().Tracer("test").Start(context.Background(), "job")
+	ctx, cancel := context.WithCancel(ctx)
+	policy := eotel.RetryPolicy{Name: "fetch", MaxAttempts: 5, InitialBackoff: time.Minute}
+	err := eotel.Retry(ctx, policy, func(context.Context) error {
+		cancel()
+		return errors.New("unavailable")
+	})
+	span.End()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	ended := rec.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("got %d spans, want the job span", len(ended))
+	}
+	for _, kv := range ended[0].Attributes() {
+		if kv.Key == "retries_exhausted" {
+			t.Errorf("retries_exhausted = %v on a cancelled retry", kv.Value.Emit())
+		}
+	}
+
+	rm := rec.Metrics(t)
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "retry_attempts" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Histogram[int64]).DataPoints {
+				found = true
+				outcome, _ := dp.Attributes.Value("outcome")
+				if outcome.AsString() != "cancelled" || dp.Sum != 1 {
+					t.Errorf("retry_attempts = %d with outcome %q, want 1 cancelled", dp.Sum, outcome.AsString())
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("retry_attempts not recorded")
+	}
+}
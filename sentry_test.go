@@ -0,0 +1,91 @@
+package eotel_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	eotel "github.com/nicedev97/eotel-v2"
+)
+
+// sentryServer records the bodies of the envelopes posted to it.
+type sentryServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies []string
+}
+
+func newSentryServer(t *testing.T) *sentryServer {
+	s := &sentryServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			body = gz
+		}
+		data, _ := io.ReadAll(body)
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(data))
+		s.mu.Unlock()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *sentryServer) dsn() string {
+	return strings.Replace(s.URL, "://", "://public@", 1) + "/1"
+}
+
+func (s *sentryServer) received() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.bodies, "\n")
+}
+
+func TestSentryCapturesRequestError(t *testing.T) {
+	srv := newSentryServer(t)
+	shutdown, err := eotel.InitEOTEL(context.Background(), eotel.Config{
+		ServiceName:  "sentrytest",
+		MinLevel:     "info",
+		EnableSentry: true,
+		SentryDSN:    srv.dsn(),
+		StrictInit:   true,
+	})
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	h := eotel.NewHTTPMiddleware("orders")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eotel.FromContext(r.Context(), "orders").Info("loading order")
+		eotel.CaptureErrorContext(r.Context(), errors.New("order store down"),
+			map[string]string{"component": "store"}, map[string]any{"order": "42"})
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	got := srv.received()
+	for _, want := range []string{"order store down", `"component":"store"`, `"order":"42"`, "loading order", "/orders/42", `"trace_id"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("sentry event lacks %s:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Bearer secret") {
+		t.Error("sentry event carries the Authorization header")
+	}
+}